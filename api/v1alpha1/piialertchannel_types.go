@@ -114,6 +114,76 @@ type PIIAlertChannelSpec struct {
 	// RateLimitPerMinute limits alerts per minute
 	// +kubebuilder:default=10
 	RateLimitPerMinute int `json:"rateLimitPerMinute,omitempty"`
+
+	// MessageTemplate is an optional Go template over the alert used to render
+	// the human-readable message for slack and webhook channels
+	MessageTemplate string `json:"messageTemplate,omitempty"`
+
+	// Timeout bounds each delivery attempt through the channel (e.g., "5s",
+	// "2m"), including connecting to the SMTP server for email channels.
+	// Defaults to 30s.
+	Timeout string `json:"timeout,omitempty"`
+
+	// TestAlert is a sample alert to send through the channel to verify its
+	// delivery and formatting end-to-end
+	TestAlert *TestAlert `json:"testAlert,omitempty"`
+}
+
+// TestAlert is a crafted sample alert. It must not contain real PII.
+type TestAlert struct {
+	// ID identifies the test. The sample alert is sent once per ID; change it
+	// to send the test again.
+	// +kubebuilder:validation:MinLength=1
+	ID string `json:"id"`
+
+	// Severity is the severity of the sample alert
+	// +kubebuilder:validation:Enum=critical;high;medium;low
+	// +kubebuilder:default=high
+	Severity string `json:"severity,omitempty"`
+
+	// PatternName is the pattern the sample alert reports
+	// +kubebuilder:default=email
+	PatternName string `json:"patternName,omitempty"`
+
+	// Pod is the pod the sample alert reports
+	Pod string `json:"pod,omitempty"`
+
+	// Container is the container the sample alert reports
+	Container string `json:"container,omitempty"`
+
+	// RedactedText is the sample redacted text shown in the alert
+	RedactedText string `json:"redactedText,omitempty"`
+
+	// Labels are added to the sample alert
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TestAlertResult records the delivery of a channel's test alert
+type TestAlertResult struct {
+	// ID is the ID of the test alert that was sent
+	ID string `json:"id"`
+
+	// SentAt is when the test alert was sent
+	SentAt *metav1.Time `json:"sentAt,omitempty"`
+
+	// Delivered indicates whether the channel accepted the test alert
+	Delivered bool `json:"delivered"`
+
+	// Error is the delivery error, if any
+	Error string `json:"error,omitempty"`
+}
+
+// CircuitBreakerStatus reports the state of a channel's circuit breaker
+type CircuitBreakerStatus struct {
+	// State is closed, open or half-open
+	// +kubebuilder:validation:Enum=closed;open;half-open
+	State string `json:"state"`
+
+	// ConsecutiveFailures is the number of send failures since the last success
+	ConsecutiveFailures int `json:"consecutiveFailures,omitempty"`
+
+	// OpenedAt is when the breaker opened, while it is open
+	OpenedAt *metav1.Time `json:"openedAt,omitempty"`
 }
 
 // PIIAlertChannelStatus defines the observed state of PIIAlertChannel
@@ -130,6 +200,13 @@ type PIIAlertChannelStatus struct {
 	// LastError is the last error message
 	LastError string `json:"lastError,omitempty"`
 
+	// LastTestAlert is the result of the most recent test alert
+	LastTestAlert *TestAlertResult `json:"lastTestAlert,omitempty"`
+
+	// CircuitBreaker is the state of the channel's circuit breaker when the
+	// channel was last reconciled
+	CircuitBreaker *CircuitBreakerStatus `json:"circuitBreaker,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
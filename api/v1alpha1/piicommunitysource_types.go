@@ -42,6 +42,23 @@ type OCISourceConfig struct {
 	// +kubebuilder:default=latest
 	Tag string `json:"tag,omitempty"`
 
+	// Path is the file or subdirectory within the artifact to read rules
+	// from; the whole artifact is read when empty
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Subject is the digest (sha256:<hex>) of a manifest, such as a base
+	// image, that the rules artifact is attached to. When set, the artifact
+	// is discovered through the registry's referrers API and Tag is ignored.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Subject string `json:"subject,omitempty"`
+
+	// ArtifactType is the artifact type of the rules artifact referring to
+	// Subject; defaults to application/vnd.pii-redactor.rules.v1+tar
+	// +optional
+	ArtifactType string `json:"artifactType,omitempty"`
+
 	// Auth contains authentication settings
 	Auth *OCIAuth `json:"auth,omitempty"`
 }
@@ -159,6 +176,10 @@ type PIICommunitySourceStatus struct {
 	// TotalPatterns is the total number of available patterns
 	TotalPatterns int `json:"totalPatterns,omitempty"`
 
+	// Warnings lists problems with the last sync that did not fail it, such
+	// as patterns dropped beyond the per-source limit
+	Warnings []string `json:"warnings,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
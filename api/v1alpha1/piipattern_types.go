@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PatternRule defines a regex pattern for PII detection
@@ -14,29 +15,106 @@ type PatternRule struct {
 	// +kubebuilder:validation:Enum=high;medium;low
 	// +kubebuilder:default=medium
 	Confidence string `json:"confidence,omitempty"`
+
+	// ContextKeywords override the pattern's context keywords for matches
+	// of this rule
+	// +optional
+	ContextKeywords []string `json:"contextKeywords,omitempty"`
+
+	// ContextWindow overrides the pattern's context window for matches of
+	// this rule
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ContextWindow int `json:"contextWindow,omitempty"`
 }
 
 // MaskingStrategy defines how to mask detected PII
 type MaskingStrategy struct {
 	// Type is the masking strategy type
-	// +kubebuilder:validation:Enum=full;partial;hash;tokenize
+	// +kubebuilder:validation:Enum=full;partial;hash;tokenize;pseudonym;maskBefore;maskAfter
 	// +kubebuilder:default=partial
 	Type string `json:"type,omitempty"`
 
-	// ShowFirst is the number of characters to show at the beginning
+	// ShowFirst is the number of characters to show at the beginning,
+	// either absolute (e.g. 4) or a percentage of the match length (e.g. "25%")
+	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:default=0
-	ShowFirst int `json:"showFirst,omitempty"`
+	ShowFirst intstr.IntOrString `json:"showFirst,omitempty"`
 
-	// ShowLast is the number of characters to show at the end
+	// ShowLast is the number of characters to show at the end,
+	// either absolute (e.g. 4) or a percentage of the match length (e.g. "25%")
+	// +kubebuilder:validation:XIntOrString
 	// +kubebuilder:default=0
-	ShowLast int `json:"showLast,omitempty"`
+	ShowLast intstr.IntOrString `json:"showLast,omitempty"`
 
 	// MaskChar is the character used for masking
 	// +kubebuilder:default="*"
 	MaskChar string `json:"maskChar,omitempty"`
 
-	// Replacement is used when Type is "full" to replace the entire match
+	// Replacement is used when Type is "full" to replace the entire match,
+	// and as the prefix of the sequential pseudonyms (e.g. USER_000001)
+	// assigned when Type is "pseudonym"
+	Replacement string `json:"replacement,omitempty"`
+
+	// Delimiter anchors the maskBefore and maskAfter types, which mask only
+	// the text before the last or after the first occurrence of it (e.g. "@").
+	// Values without the delimiter fall back to partial masking.
+	// +optional
+	Delimiter string `json:"delimiter,omitempty"`
+
+	// Chain lists further masking steps, each applied in order to the output
+	// of the previous one (e.g. partial masking followed by hash)
+	// +optional
+	Chain []MaskingStep `json:"chain,omitempty"`
+
+	// PreserveBoundaryLines keeps the first and last lines of a multiline
+	// match visible and masks only the lines between them, so that e.g. the
+	// BEGIN and END markers of a PEM private key show what was redacted
+	// +optional
+	PreserveBoundaryLines bool `json:"preserveBoundaryLines,omitempty"`
+
+	// Group names the capture group of the matching regex to mask, by name
+	// or index (e.g. "password" or "1"), leaving the rest of the match
+	// visible. Matches in which the group did not take part are masked whole.
+	// +optional
+	Group string `json:"group,omitempty"`
+}
+
+// MaskingStep is a single step of a chained masking strategy
+type MaskingStep struct {
+	// Type is the masking strategy type
+	// +kubebuilder:validation:Enum=full;partial;hash;tokenize;pseudonym;maskBefore;maskAfter
+	Type string `json:"type"`
+
+	// ShowFirst is the number or percentage of characters to show at the beginning
+	// +kubebuilder:validation:XIntOrString
+	ShowFirst intstr.IntOrString `json:"showFirst,omitempty"`
+
+	// ShowLast is the number or percentage of characters to show at the end
+	// +kubebuilder:validation:XIntOrString
+	ShowLast intstr.IntOrString `json:"showLast,omitempty"`
+
+	// MaskChar is the character used for masking
+	MaskChar string `json:"maskChar,omitempty"`
+
+	// Replacement is used when Type is "full" to replace the entire input
 	Replacement string `json:"replacement,omitempty"`
+
+	// Delimiter anchors the maskBefore and maskAfter types
+	// +optional
+	Delimiter string `json:"delimiter,omitempty"`
+}
+
+// Strategy returns the step as a masking strategy without a chain
+func (s MaskingStep) Strategy() MaskingStrategy {
+	return MaskingStrategy{
+		Type:        s.Type,
+		ShowFirst:   s.ShowFirst,
+		ShowLast:    s.ShowLast,
+		MaskChar:    s.MaskChar,
+		Replacement: s.Replacement,
+		Delimiter:   s.Delimiter,
+	}
 }
 
 // PIIPatternSpec defines the desired state of PIIPattern
@@ -47,9 +125,15 @@ type PIIPatternSpec struct {
 	// Description provides details about this pattern
 	Description string `json:"description,omitempty"`
 
-	// Patterns is a list of regex patterns for detection
-	// +kubebuilder:validation:MinItems=1
-	Patterns []PatternRule `json:"patterns"`
+	// Tags are cross-cutting labels, such as compliance regimes (pci, gdpr,
+	// hipaa), that policies can select the pattern by
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Patterns is a list of regex patterns for detection. It is required
+	// unless RequiresAll is set.
+	// +optional
+	Patterns []PatternRule `json:"patterns,omitempty"`
 
 	// Validator is an optional validation function name
 	Validator string `json:"validator,omitempty"`
@@ -57,6 +141,62 @@ type PIIPatternSpec struct {
 	// MaskingStrategy defines how to mask detected PII
 	MaskingStrategy MaskingStrategy `json:"maskingStrategy,omitempty"`
 
+	// ConfidenceMasking defines alternate masking strategies keyed by match
+	// confidence (high, medium, low). MaskingStrategy applies to other matches.
+	ConfidenceMasking map[string]MaskingStrategy `json:"confidenceMasking,omitempty"`
+
+	// SeparatorInsensitive matches the patterns against a copy of the input with
+	// spaces, dots, hyphens and parentheses between digits removed, so that
+	// "010.1234.5678" matches a rule written for "010-1234-5678"
+	// +optional
+	SeparatorInsensitive bool `json:"separatorInsensitive,omitempty"`
+
+	// MinLength drops matches shorter than this many characters, filtering
+	// noise from broad patterns. Zero disables the check.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinLength int `json:"minLength,omitempty"`
+
+	// RequiresAll makes this a composite pattern that detects only where all
+	// of the named patterns match, such as a name together with a date of
+	// birth, and reports a single detection spanning them. Names are built-in
+	// pattern names or namespace/name of other PIIPatterns. Components are
+	// matched even when disabled.
+	// +optional
+	RequiresAll []string `json:"requiresAll,omitempty"`
+
+	// Window is the largest span in bytes a composite match may cover. Zero
+	// allows the components anywhere in the input.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Window int `json:"window,omitempty"`
+
+	// MaxMatchLength bounds the length in bytes of a match, for patterns that
+	// can match arbitrarily long text such as PEM blocks. Streaming detection
+	// overlaps its windows by at least this much. Zero derives the bound
+	// from the patterns.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxMatchLength int `json:"maxMatchLength,omitempty"`
+
+	// ContextKeywords, when set, keep only matches with one of the keywords
+	// starting a word within ContextWindow characters before or after them,
+	// compared without regard to case, e.g. "routing" near a 9-digit number
+	// +optional
+	ContextKeywords []string `json:"contextKeywords,omitempty"`
+
+	// ContextWindow is how many characters around a match are searched for
+	// ContextKeywords. Zero means 32.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ContextWindow int `json:"contextWindow,omitempty"`
+
+	// SensitiveGroups names capture groups, by name or by index, whose values
+	// are kept out of detection metadata. Groups masked by the masking
+	// strategy are always kept out.
+	// +optional
+	SensitiveGroups []string `json:"sensitiveGroups,omitempty"`
+
 	// Severity is the severity level of this PII type
 	// +kubebuilder:validation:Enum=critical;high;medium;low
 	// +kubebuilder:default=medium
@@ -90,6 +230,10 @@ type PIIPatternStatus struct {
 	// ValidationErrors contains any errors from pattern validation
 	ValidationErrors []string `json:"validationErrors,omitempty"`
 
+	// ValidationWarnings contains non-blocking findings, such as masking that
+	// reveals a large share of high severity values
+	ValidationWarnings []string `json:"validationWarnings,omitempty"`
+
 	// MatchCount is the number of matches detected (for metrics)
 	MatchCount int64 `json:"matchCount,omitempty"`
 }
@@ -38,6 +38,15 @@ type PatternSelection struct {
 
 	// Community is a list of community pattern references
 	Community []string `json:"community,omitempty"`
+
+	// Tags selects every built-in and community pattern carrying any of
+	// these tags, e.g. hipaa to use all HIPAA patterns
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Overrides adjusts severity, masking or enablement of built-in
+	// patterns within the scope of this policy only
+	Overrides []PatternOverride `json:"overrides,omitempty"`
 }
 
 // RedactAction defines redaction behavior
@@ -46,7 +55,8 @@ type RedactAction struct {
 	// +kubebuilder:default=true
 	Enabled bool `json:"enabled,omitempty"`
 
-	// Destination is where to send redacted logs
+	// Destination is the name of an operator-configured sink to send
+	// redacted logs to; redacted logs go to stdout when empty
 	Destination string `json:"destination,omitempty"`
 }
 
@@ -55,7 +65,9 @@ type DeduplicationConfig struct {
 	// Window is the time window for deduplication
 	Window string `json:"window,omitempty"`
 
-	// Key is the template for deduplication key
+	// Key is the Go template the deduplication key is rendered from, evaluated
+	// against the alert, e.g. "{{ .Namespace }}/{{ .PatternName }}". Defaults to
+	// a key derived from the namespace, pod, pattern and matched values.
 	Key string `json:"key,omitempty"`
 }
 
@@ -82,10 +94,39 @@ type AuditAction struct {
 	// +kubebuilder:default=false
 	IncludeOriginal bool `json:"includeOriginal,omitempty"`
 
-	// Destination is where to send audit logs
+	// Destination is the name of an operator-configured audit sink to send
+	// audit logs to; the default audit logger is used when empty
 	Destination string `json:"destination,omitempty"`
 }
 
+// BlockAction defines fail-closed behavior: log entries with a detection of
+// at least MinSeverity are replaced by a block notice instead of being redacted
+type BlockAction struct {
+	// Enabled indicates whether blocking is enabled
+	// +kubebuilder:default=true
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinSeverity is the lowest detection severity that blocks an entry
+	// +kubebuilder:validation:Enum=critical;high;medium;low
+	// +kubebuilder:default=critical
+	MinSeverity string `json:"minSeverity,omitempty"`
+
+	// Notice replaces the content of a blocked entry
+	Notice string `json:"notice,omitempty"`
+}
+
+// ConfigScanAction defines scanning of ConfigMap and Secret data in the
+// matched namespaces for PII stored in configuration
+type ConfigScanAction struct {
+	// Enabled indicates whether ConfigMap data is scanned
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// IncludeSecrets indicates whether Secret data is scanned as well
+	// +kubebuilder:default=false
+	IncludeSecrets bool `json:"includeSecrets,omitempty"`
+}
+
 // PolicyActions defines actions to take when PII is detected
 type PolicyActions struct {
 	// Redact defines redaction behavior
@@ -96,6 +137,12 @@ type PolicyActions struct {
 
 	// Audit defines audit logging behavior
 	Audit *AuditAction `json:"audit,omitempty"`
+
+	// Block defines blocking behavior for the most sensitive detections
+	Block *BlockAction `json:"block,omitempty"`
+
+	// ScanConfig defines scanning of ConfigMap and Secret data
+	ScanConfig *ConfigScanAction `json:"scanConfig,omitempty"`
 }
 
 // PerformanceConfig defines performance settings
@@ -106,10 +153,25 @@ type PerformanceConfig struct {
 	// +kubebuilder:default=100
 	SamplingRate int `json:"samplingRate,omitempty"`
 
+	// SamplingMode determines how logs are picked for sampling: hash picks
+	// the same logs in every controller process, random picks independently
+	// each time, and seeded picks the same logs for identical content within
+	// a process but a different subset after a restart
+	// +kubebuilder:validation:Enum=hash;random;seeded
+	// +kubebuilder:default=hash
+	// +optional
+	SamplingMode string `json:"samplingMode,omitempty"`
+
 	// MaxLogSizeKB is the maximum log size to process
 	// +kubebuilder:default=1024
 	MaxLogSizeKB int `json:"maxLogSizeKB,omitempty"`
 
+	// OversizeAction determines how logs larger than MaxLogSizeKB are handled:
+	// skip them entirely or scan only the first MaxLogSizeKB
+	// +kubebuilder:validation:Enum=skip;truncate
+	// +kubebuilder:default=truncate
+	OversizeAction string `json:"oversizeAction,omitempty"`
+
 	// BatchSize is the number of logs to process in a batch
 	// +kubebuilder:default=100
 	BatchSize int `json:"batchSize,omitempty"`
@@ -25,9 +25,9 @@ type CategorySubscription struct {
 	Patterns []string `json:"patterns"`
 }
 
-// PatternOverride defines an override for a community pattern
+// PatternOverride defines an override for a community or built-in pattern
 type PatternOverride struct {
-	// Pattern is the pattern identifier (e.g., "korea/phone")
+	// Pattern is the pattern identifier (e.g., "korea/phone" or "email")
 	Pattern string `json:"pattern"`
 
 	// Severity overrides the pattern severity
@@ -90,6 +90,62 @@ type SubscribedPatternInfo struct {
 	Overridden bool `json:"overridden,omitempty"`
 }
 
+// RetryingPatternInfo describes a subscribed pattern that failed to register
+// and is retried with backoff on later reconciles
+type RetryingPatternInfo struct {
+	// Name is the pattern name
+	Name string `json:"name"`
+
+	// Source is the source name
+	Source string `json:"source"`
+
+	// Attempts is the number of failed registration attempts
+	Attempts int `json:"attempts"`
+
+	// LastError is the error of the last attempt
+	LastError string `json:"lastError,omitempty"`
+
+	// NextRetry is the earliest time of the next attempt
+	NextRetry metav1.Time `json:"nextRetry"`
+}
+
+// ValidateCorpusAnnotation names a ConfigMap in the subscription's namespace
+// whose data values, split into lines, form a corpus the subscribed patterns
+// are dry run against before they are activated. The result is reported in
+// status.corpusValidation; patterns failing the dry run are not activated.
+const ValidateCorpusAnnotation = "pii.namjun.kim/validate-corpus"
+
+// CorpusPatternResult is the outcome of dry running a pattern against a corpus
+type CorpusPatternResult struct {
+	// Name is the pattern name
+	Name string `json:"name"`
+
+	// Matches is the number of matches in the corpus
+	Matches int `json:"matches"`
+
+	// MatchedLines is the number of corpus lines with at least one match
+	MatchedLines int `json:"matchedLines"`
+}
+
+// CorpusValidationStatus summarizes a dry run of the subscribed patterns
+// against a corpus, to measure how noisy they are before relying on them
+type CorpusValidationStatus struct {
+	// ConfigMap is the name of the ConfigMap holding the corpus
+	ConfigMap string `json:"configMap"`
+
+	// Lines is the number of corpus lines scanned
+	Lines int `json:"lines,omitempty"`
+
+	// Patterns lists the match counts per pattern, noisiest first
+	Patterns []CorpusPatternResult `json:"patterns,omitempty"`
+
+	// Error is set when the corpus could not be read or scanned
+	Error string `json:"error,omitempty"`
+
+	// ValidatedAt is the time of the dry run
+	ValidatedAt metav1.Time `json:"validatedAt"`
+}
+
 // PIIRuleSubscriptionSpec defines the desired state of PIIRuleSubscription
 type PIIRuleSubscriptionSpec struct {
 	// SourceRef references the community source
@@ -131,6 +187,20 @@ type PIIRuleSubscriptionStatus struct {
 	// LastError is the last error message
 	LastError string `json:"lastError,omitempty"`
 
+	// Warnings contains non-blocking problems found while subscribing, such
+	// as overrides that do not match any subscribed pattern. Overrides that
+	// cannot be applied, such as an invalid masking strategy, fail the
+	// subscription instead.
+	Warnings []string `json:"warnings,omitempty"`
+
+	// RetryingPatterns lists patterns that failed to register and are
+	// being retried
+	RetryingPatterns []RetryingPatternInfo `json:"retryingPatterns,omitempty"`
+
+	// CorpusValidation is the result of dry running the subscribed patterns
+	// against the corpus named by the validate-corpus annotation
+	CorpusValidation *CorpusValidationStatus `json:"corpusValidation,omitempty"`
+
 	// Conditions represent the latest available observations
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
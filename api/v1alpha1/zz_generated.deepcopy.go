@@ -48,6 +48,21 @@ func (in *AuditAction) DeepCopy() *AuditAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockAction) DeepCopyInto(out *BlockAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockAction.
+func (in *BlockAction) DeepCopy() *BlockAction {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CategorySubscription) DeepCopyInto(out *CategorySubscription) {
 	*out = *in
@@ -68,6 +83,76 @@ func (in *CategorySubscription) DeepCopy() *CategorySubscription {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CircuitBreakerStatus) DeepCopyInto(out *CircuitBreakerStatus) {
+	*out = *in
+	if in.OpenedAt != nil {
+		in, out := &in.OpenedAt, &out.OpenedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CircuitBreakerStatus.
+func (in *CircuitBreakerStatus) DeepCopy() *CircuitBreakerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CircuitBreakerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigScanAction) DeepCopyInto(out *ConfigScanAction) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigScanAction.
+func (in *ConfigScanAction) DeepCopy() *ConfigScanAction {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigScanAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorpusPatternResult) DeepCopyInto(out *CorpusPatternResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorpusPatternResult.
+func (in *CorpusPatternResult) DeepCopy() *CorpusPatternResult {
+	if in == nil {
+		return nil
+	}
+	out := new(CorpusPatternResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorpusValidationStatus) DeepCopyInto(out *CorpusValidationStatus) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]CorpusPatternResult, len(*in))
+		copy(*out, *in)
+	}
+	in.ValidatedAt.DeepCopyInto(&out.ValidatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorpusValidationStatus.
+func (in *CorpusValidationStatus) DeepCopy() *CorpusValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CorpusValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeduplicationConfig) DeepCopyInto(out *DeduplicationConfig) {
 	*out = *in
@@ -185,6 +270,11 @@ func (in *HTTPSourceConfig) DeepCopy() *HTTPSourceConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MaskingStrategy) DeepCopyInto(out *MaskingStrategy) {
 	*out = *in
+	if in.Chain != nil {
+		in, out := &in.Chain, &out.Chain
+		*out = make([]MaskingStep, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskingStrategy.
@@ -197,6 +287,21 @@ func (in *MaskingStrategy) DeepCopy() *MaskingStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaskingStep) DeepCopyInto(out *MaskingStep) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaskingStep.
+func (in *MaskingStep) DeepCopy() *MaskingStep {
+	if in == nil {
+		return nil
+	}
+	out := new(MaskingStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OCIAuth) DeepCopyInto(out *OCIAuth) {
 	*out = *in
@@ -268,7 +373,7 @@ func (in *PatternOverride) DeepCopyInto(out *PatternOverride) {
 	if in.MaskingStrategy != nil {
 		in, out := &in.MaskingStrategy, &out.MaskingStrategy
 		*out = new(MaskingStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -300,6 +405,11 @@ func (in *PatternRef) DeepCopy() *PatternRef {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatternRule) DeepCopyInto(out *PatternRule) {
 	*out = *in
+	if in.ContextKeywords != nil {
+		in, out := &in.ContextKeywords, &out.ContextKeywords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternRule.
@@ -330,6 +440,18 @@ func (in *PatternSelection) DeepCopyInto(out *PatternSelection) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]PatternOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatternSelection.
@@ -454,6 +576,11 @@ func (in *PIIAlertChannelSpec) DeepCopyInto(out *PIIAlertChannelSpec) {
 		*out = new(EmailConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TestAlert != nil {
+		in, out := &in.TestAlert, &out.TestAlert
+		*out = new(TestAlert)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PIIAlertChannelSpec.
@@ -473,6 +600,16 @@ func (in *PIIAlertChannelStatus) DeepCopyInto(out *PIIAlertChannelStatus) {
 		in, out := &in.LastAlertSent, &out.LastAlertSent
 		*out = (*in).DeepCopy()
 	}
+	if in.LastTestAlert != nil {
+		in, out := &in.LastTestAlert, &out.LastTestAlert
+		*out = new(TestAlertResult)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreaker != nil {
+		in, out := &in.CircuitBreaker, &out.CircuitBreaker
+		*out = new(CircuitBreakerStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -599,6 +736,11 @@ func (in *PIICommunitySourceStatus) DeepCopyInto(out *PIICommunitySourceStatus)
 		*out = make([]RuleSetInfo, len(*in))
 		copy(*out, *in)
 	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -680,12 +822,41 @@ func (in *PIIPatternList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PIIPatternSpec) DeepCopyInto(out *PIIPatternSpec) {
 	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Patterns != nil {
 		in, out := &in.Patterns, &out.Patterns
 		*out = make([]PatternRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.MaskingStrategy.DeepCopyInto(&out.MaskingStrategy)
+	if in.ConfidenceMasking != nil {
+		in, out := &in.ConfidenceMasking, &out.ConfidenceMasking
+		*out = make(map[string]MaskingStrategy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.RequiresAll != nil {
+		in, out := &in.RequiresAll, &out.RequiresAll
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContextKeywords != nil {
+		in, out := &in.ContextKeywords, &out.ContextKeywords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SensitiveGroups != nil {
+		in, out := &in.SensitiveGroups, &out.SensitiveGroups
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	out.MaskingStrategy = in.MaskingStrategy
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
@@ -720,6 +891,11 @@ func (in *PIIPatternStatus) DeepCopyInto(out *PIIPatternStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ValidationWarnings != nil {
+		in, out := &in.ValidationWarnings, &out.ValidationWarnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PIIPatternStatus.
@@ -956,6 +1132,23 @@ func (in *PIIRuleSubscriptionStatus) DeepCopyInto(out *PIIRuleSubscriptionStatus
 		*out = make([]PendingUpdate, len(*in))
 		copy(*out, *in)
 	}
+	if in.Warnings != nil {
+		in, out := &in.Warnings, &out.Warnings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryingPatterns != nil {
+		in, out := &in.RetryingPatterns, &out.RetryingPatterns
+		*out = make([]RetryingPatternInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CorpusValidation != nil {
+		in, out := &in.CorpusValidation, &out.CorpusValidation
+		*out = new(CorpusValidationStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -993,6 +1186,16 @@ func (in *PolicyActions) DeepCopyInto(out *PolicyActions) {
 		*out = new(AuditAction)
 		**out = **in
 	}
+	if in.Block != nil {
+		in, out := &in.Block, &out.Block
+		*out = new(BlockAction)
+		**out = **in
+	}
+	if in.ScanConfig != nil {
+		in, out := &in.ScanConfig, &out.ScanConfig
+		*out = new(ConfigScanAction)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyActions.
@@ -1055,6 +1258,22 @@ func (in *RedactAction) DeepCopy() *RedactAction {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryingPatternInfo) DeepCopyInto(out *RetryingPatternInfo) {
+	*out = *in
+	in.NextRetry.DeepCopyInto(&out.NextRetry)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryingPatternInfo.
+func (in *RetryingPatternInfo) DeepCopy() *RetryingPatternInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryingPatternInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RuleSetInfo) DeepCopyInto(out *RuleSetInfo) {
 	*out = *in
@@ -1150,6 +1369,47 @@ func (in *SyncConfig) DeepCopy() *SyncConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestAlert) DeepCopyInto(out *TestAlert) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestAlert.
+func (in *TestAlert) DeepCopy() *TestAlert {
+	if in == nil {
+		return nil
+	}
+	out := new(TestAlert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestAlertResult) DeepCopyInto(out *TestAlertResult) {
+	*out = *in
+	if in.SentAt != nil {
+		in, out := &in.SentAt, &out.SentAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestAlertResult.
+func (in *TestAlertResult) DeepCopy() *TestAlertResult {
+	if in == nil {
+		return nil
+	}
+	out := new(TestAlertResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TestCases) DeepCopyInto(out *TestCases) {
 	*out = *in
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const benchFixture = `apiVersion: pii.namjun.kim/v1alpha1
+kind: PIIPattern
+metadata:
+  name: employee-id
+spec:
+  displayName: Employee ID
+  patterns:
+    - regex: 'EMP-\d{6}'
+      confidence: high
+  severity: medium
+  testCases:
+    shouldMatch:
+      - "employee EMP-123456 logged in"
+    shouldNotMatch:
+      - "EMP-12"
+`
+
+func TestRunRulesBench(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "employee-id.yaml"), []byte(benchFixture), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broken := strings.Replace(strings.Replace(benchFixture, "employee-id", "broken", 1), `EMP-\d{6}`, `EMP-(\d{6}`, 1)
+	if err := os.WriteFile(filepath.Join(dir, "broken.yml"), []byte(broken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := runRulesBench([]string{"-o", "json", "-iterations", "3", "-sort", "name", dir}, &out); err != nil {
+			t.Fatalf("runRulesBench() error = %v", err)
+		}
+
+		var results []benchResult
+		if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+			t.Fatalf("invalid JSON report: %v\n%s", err, out.String())
+		}
+		if len(results) != 2 {
+			t.Fatalf("got %d results, want 2", len(results))
+		}
+
+		brokenResult, employee := results[0], results[1]
+		if brokenResult.Name != "broken" || brokenResult.Error == "" {
+			t.Errorf("broken result = %+v, want a compile error", brokenResult)
+		}
+		if employee.Name != "employee-id" || employee.Error != "" {
+			t.Fatalf("employee result = %+v", employee)
+		}
+		if employee.TimePerRun <= 0 {
+			t.Errorf("TimePerRun = %v, want a positive timing", employee.TimePerRun)
+		}
+		if employee.Matches != 1 {
+			t.Errorf("Matches = %d, want 1", employee.Matches)
+		}
+		if employee.CorpusLines != len(benchSample)+2 {
+			t.Errorf("CorpusLines = %d, want %d", employee.CorpusLines, len(benchSample)+2)
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := runRulesBench([]string{"-iterations", "3", filepath.Join(dir, "employee-id.yaml")}, &out); err != nil {
+			t.Fatalf("runRulesBench() error = %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 || !strings.HasPrefix(lines[0], "PATTERN") {
+			t.Fatalf("unexpected report:\n%s", out.String())
+		}
+		if fields := strings.Fields(lines[1]); fields[0] != "employee-id" || !strings.HasSuffix(fields[1], "s") {
+			t.Errorf("report row = %q, want employee-id with a timing", lines[1])
+		}
+	})
+
+	t.Run("builtin", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := runRulesBench([]string{"-builtin", "-iterations", "1", "-o", "json"}, &out); err != nil {
+			t.Fatalf("runRulesBench() error = %v", err)
+		}
+		var results []benchResult
+		if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+			t.Fatalf("invalid JSON report: %v", err)
+		}
+		if len(results) == 0 {
+			t.Error("no built-in patterns benchmarked")
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		for _, args := range [][]string{{}, {"-builtin", dir}, {"-sort", "size", dir}, {"-o", "xml", dir}} {
+			if err := runRulesBench(args, &bytes.Buffer{}); err == nil {
+				t.Errorf("runRulesBench(%v) error = nil, want error", args)
+			}
+		}
+	})
+}
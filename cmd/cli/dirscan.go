@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// dirScanOptions configures a directory scan
+type dirScanOptions struct {
+	// Excludes are glob patterns of paths, relative to the scanned directory,
+	// that are not scanned. "**" matches any number of path segments, and a
+	// pattern without a slash matches the file name at any depth.
+	Excludes []string
+
+	// MaxFileSize skips files larger than this many bytes, and gzip files
+	// that decompress to more; zero disables the limit, though gzip files
+	// are still decompressed only up to source.DefaultMaxExtractTotalSize
+	MaxFileSize int64
+
+	// Concurrency is the number of files scanned at once
+	Concurrency int
+
+	// Patterns restricts the scan to the named patterns; empty scans with all enabled patterns
+	Patterns []string
+
+	// Offsets is the unit detection positions are reported in
+	Offsets detector.OffsetUnit
+}
+
+// fileResult is the detections found in a single file
+type fileResult struct {
+	Path       string                     `json:"path"`
+	Detections []detector.DetectionResult `json:"detections"`
+}
+
+// skippedFile is a file or directory a scan left out, with the reason
+type skippedFile struct {
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// dirScanResult aggregates the results of a directory scan
+type dirScanResult struct {
+	FilesScanned   int           `json:"files_scanned"`
+	DetectionCount int           `json:"detection_count"`
+	Files          []fileResult  `json:"files"`
+	Skipped        []skippedFile `json:"skipped"`
+}
+
+// scanDir scans every regular file below root with up to opts.Concurrency
+// workers. Files are reported in walk order whatever the concurrency, and
+// only files with detections are listed.
+func scanDir(ctx context.Context, r *redactor.Redactor, root string, opts dirScanOptions) (*dirScanResult, error) {
+	result := &dirScanResult{Files: []fileResult{}, Skipped: []skippedFile{}}
+
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil {
+			return relErr
+		}
+		rel = filepath.ToSlash(rel)
+
+		if err != nil {
+			result.Skipped = append(result.Skipped, skippedFile{Path: rel, Reason: err.Error()})
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == "." {
+			return nil
+		}
+
+		if d.IsDir() {
+			if excludedDir(opts.Excludes, rel) {
+				result.Skipped = append(result.Skipped, skippedFile{Path: rel + "/", Reason: "excluded"})
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if excludedFile(opts.Excludes, rel) {
+			result.Skipped = append(result.Skipped, skippedFile{Path: rel, Reason: "excluded"})
+			return nil
+		}
+
+		if opts.MaxFileSize > 0 {
+			info, err := d.Info()
+			if err != nil {
+				result.Skipped = append(result.Skipped, skippedFile{Path: rel, Reason: err.Error()})
+				return nil
+			}
+			if info.Size() > opts.MaxFileSize {
+				result.Skipped = append(result.Skipped, skippedFile{
+					Path:   rel,
+					Reason: fmt.Sprintf("larger than %d bytes (%d bytes)", opts.MaxFileSize, info.Size()),
+				})
+				return nil
+			}
+		}
+
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]fileResult, len(files))
+	failures := make([]error, len(files))
+
+	workers := max(opts.Concurrency, 1)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], failures[i] = scanDirFile(ctx, r, root, files[i], opts)
+			}
+		}()
+	}
+	for i := range files {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, file := range results {
+		if failures[i] != nil {
+			result.Skipped = append(result.Skipped, skippedFile{Path: files[i], Reason: failures[i].Error()})
+			continue
+		}
+		result.FilesScanned++
+		if len(file.Detections) > 0 {
+			result.Files = append(result.Files, file)
+			result.DetectionCount += len(file.Detections)
+		}
+	}
+
+	return result, nil
+}
+
+// scanDirFile scans a single file of a directory scan
+func scanDirFile(ctx context.Context, r *redactor.Redactor, root, rel string, opts dirScanOptions) (fileResult, error) {
+	data, err := readInputFile(filepath.Join(root, filepath.FromSlash(rel)), opts.MaxFileSize)
+	if err != nil {
+		return fileResult{}, err
+	}
+
+	var result *redactor.RedactResult
+	if len(opts.Patterns) > 0 {
+		result, err = r.RedactWithPatterns(ctx, string(data), opts.Patterns)
+	} else {
+		result, err = r.Redact(ctx, string(data))
+	}
+	if err != nil {
+		return fileResult{}, err
+	}
+	if result.Skipped {
+		return fileResult{}, fmt.Errorf("exceeds the input size limit")
+	}
+
+	// Redaction reports detections in order of position
+	detections := detector.ConvertOffsets(result.OriginalText, result.Detections, opts.Offsets)
+	return fileResult{Path: rel, Detections: detections}, nil
+}
+
+// parseExcludes splits a comma-separated list of exclude globs, dropping blanks
+func parseExcludes(value string) []string {
+	var excludes []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			excludes = append(excludes, pattern)
+		}
+	}
+	return excludes
+}
+
+// excludedFile reports whether a file path matches an exclude pattern
+func excludedFile(excludes []string, rel string) bool {
+	for _, pattern := range excludes {
+		if matchGlob(pattern, rel) {
+			return true
+		}
+		if !strings.Contains(pattern, "/") && matchGlob(pattern, path.Base(rel)) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedDir reports whether every path below a directory is excluded, so
+// the directory need not be walked
+func excludedDir(excludes []string, rel string) bool {
+	for _, pattern := range excludes {
+		if prefix, ok := strings.CutSuffix(pattern, "/**"); ok && matchGlob(prefix, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated path against a glob pattern in which
+// "**" matches any number of whole path segments and other segments use
+// path.Match syntax
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// writeDirScanReport writes a directory scan as a text report or JSON
+func writeDirScanReport(w io.Writer, format string, result *dirScanResult) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case "text":
+		var b strings.Builder
+		for _, file := range result.Files {
+			fmt.Fprintf(&b, "%s (%d found)\n", file.Path, len(file.Detections))
+			for _, d := range file.Detections {
+				fmt.Fprintf(&b, "  [%s] %s at %d-%d: %s\n", d.Severity, d.PatternName, d.Position.Start, d.Position.End, d.RedactedText)
+			}
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "Scanned %d file(s): %d PII instance(s) in %d file(s), %d skipped\n",
+			result.FilesScanned, result.DetectionCount, len(result.Files), len(result.Skipped))
+		for _, s := range result.Skipped {
+			fmt.Fprintf(&b, "  skipped %s: %s\n", s.Path, s.Reason)
+		}
+
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q for directory scans (available: json, text)", format)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+func TestScanDir(t *testing.T) {
+	root := t.TempDir()
+	writeFile := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("app.log", "login test@example.com\n")
+	writeFile("src/clean.go", "package main\n")
+	writeFile("node_modules/lib/index.js", "contact dep@example.com\n")
+	writeFile("web/app.min.js", "contact min@example.com\n")
+	writeFile("dump.bin", "owner big@example.com "+strings.Repeat("x", 4096))
+	for i := 0; i < 40; i++ {
+		writeFile(fmt.Sprintf("logs/%02d.log", i), fmt.Sprintf("user%d@example.com card 4111-1111-1111-1111\n", i))
+	}
+
+	opts := dirScanOptions{
+		Excludes:    []string{"**/node_modules/**", "*.min.js"},
+		MaxFileSize: 1024,
+		Concurrency: 1,
+		Patterns:    []string{"email", "credit-card"},
+	}
+
+	ctx := context.Background()
+	sequential, err := scanDir(ctx, redactor.NewRedactor(detector.NewEngine()), root, opts)
+	if err != nil {
+		t.Fatalf("scanDir() error = %v", err)
+	}
+
+	if sequential.FilesScanned != 42 {
+		t.Errorf("FilesScanned = %d, want 42", sequential.FilesScanned)
+	}
+	if len(sequential.Files) != 41 || sequential.DetectionCount != 81 {
+		t.Errorf("got %d detections in %d files, want 81 in 41", sequential.DetectionCount, len(sequential.Files))
+	}
+
+	skipped := make(map[string]string)
+	for _, s := range sequential.Skipped {
+		skipped[s.Path] = s.Reason
+	}
+	if skipped["node_modules/"] != "excluded" || skipped["web/app.min.js"] != "excluded" {
+		t.Errorf("skipped = %v, want node_modules/ and web/app.min.js excluded", skipped)
+	}
+	if !strings.HasPrefix(skipped["dump.bin"], "larger than 1024 bytes") {
+		t.Errorf("dump.bin skip reason = %q, want the size limit", skipped["dump.bin"])
+	}
+	if len(skipped) != 3 {
+		t.Errorf("skipped = %v, want 3 entries", skipped)
+	}
+
+	for _, f := range sequential.Files {
+		for i := 1; i < len(f.Detections); i++ {
+			if f.Detections[i].Position.Start < f.Detections[i-1].Position.Start {
+				t.Errorf("%s detections are not in reading order: %+v", f.Path, f.Detections)
+				break
+			}
+		}
+	}
+
+	opts.Concurrency = 8
+	parallel, err := scanDir(ctx, redactor.NewRedactor(detector.NewEngine()), root, opts)
+	if err != nil {
+		t.Fatalf("scanDir() error = %v", err)
+	}
+	if !reflect.DeepEqual(parallel, sequential) {
+		t.Error("parallel scan results differ from the sequential scan")
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"**/node_modules/**", "node_modules/lib/index.js", true},
+		{"**/node_modules/**", "web/node_modules/a.js", true},
+		{"**/node_modules/**", "web/modules/a.js", false},
+		{"*.min.js", "app.min.js", true},
+		{"*.min.js", "web/app.min.js", false},
+		{"logs/*.log", "logs/a.log", true},
+		{"logs/*.log", "logs/old/a.log", false},
+		{"logs/**/*.log", "logs/old/a.log", true},
+		{"logs/**/*.log", "logs/a.log", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,16 +4,30 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/bunseokbot/pii-redactor/internal/buildinfo"
 	"github.com/bunseokbot/pii-redactor/internal/detector"
 	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 	"github.com/bunseokbot/pii-redactor/internal/redactor"
+	"github.com/bunseokbot/pii-redactor/internal/source"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Build information, injected via -ldflags
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
 )
 
 func main() {
@@ -22,6 +36,9 @@ func main() {
 		case "rules":
 			handleRulesCommand(os.Args[2:])
 			return
+		case "serve":
+			handleServeCommand(os.Args[2:])
+			return
 		}
 	}
 
@@ -59,10 +76,31 @@ type PatternDef struct {
 }
 
 type MaskingStrategy struct {
-	Type      string `yaml:"type"`
-	ShowFirst int    `yaml:"showFirst"`
-	ShowLast  int    `yaml:"showLast"`
-	MaskChar  string `yaml:"maskChar"`
+	Type        string `yaml:"type"`
+	ShowFirst   int    `yaml:"showFirst"`
+	ShowLast    int    `yaml:"showLast"`
+	MaskChar    string `yaml:"maskChar"`
+	Replacement string `yaml:"replacement"`
+	Delimiter   string `yaml:"delimiter"`
+
+	PreserveBoundaryLines bool   `yaml:"preserveBoundaryLines"`
+	Group                 string `yaml:"group"`
+}
+
+// toPatternStrategy converts the rule file's masking strategy to the one
+// the redactor applies
+func (m MaskingStrategy) toPatternStrategy() patterns.MaskingStrategy {
+	return patterns.MaskingStrategy{
+		Type:        m.Type,
+		ShowFirst:   m.ShowFirst,
+		ShowLast:    m.ShowLast,
+		MaskChar:    m.MaskChar,
+		Replacement: m.Replacement,
+		Delimiter:   m.Delimiter,
+
+		PreserveBoundaryLines: m.PreserveBoundaryLines,
+		Group:                 m.Group,
+	}
 }
 
 type TestCases struct {
@@ -76,6 +114,7 @@ func handleRulesCommand(args []string) {
 		fmt.Fprintln(os.Stderr, "")
 		fmt.Fprintln(os.Stderr, "Commands:")
 		fmt.Fprintln(os.Stderr, "  test <file>    Test patterns in a rule file against its test cases")
+		fmt.Fprintln(os.Stderr, "  bench <path>   Report the evaluation cost of patterns in a rule file or directory")
 		os.Exit(1)
 	}
 
@@ -86,6 +125,11 @@ func handleRulesCommand(args []string) {
 			os.Exit(1)
 		}
 		runRulesTest(args[1])
+	case "bench":
+		if err := runRulesBench(args[1:], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown rules command: %s\n", args[0])
 		os.Exit(1)
@@ -147,6 +191,7 @@ func runRulesTest(filePath string) {
 		}
 		if matched {
 			fmt.Printf("  ✓ \"%s\"\n", truncate(testCase, 60))
+			fmt.Printf("      masked: \"%s\"\n", truncate(maskPreview(compiledPatterns, rule.Spec.MaskingStrategy, testCase), 60))
 		} else {
 			fmt.Printf("  ✗ \"%s\" (no pattern matched)\n", truncate(testCase, 60))
 			failures = append(failures, fmt.Sprintf("shouldMatch: %s", testCase))
@@ -191,6 +236,20 @@ func runRulesTest(filePath string) {
 	}
 }
 
+// maskPreview returns a test case with the matches of the first pattern
+// that matches it masked with the rule's masking strategy
+func maskPreview(compiledPatterns []*regexp.Regexp, strategy MaskingStrategy, testCase string) string {
+	masking := strategy.toPatternStrategy()
+	for _, re := range compiledPatterns {
+		if re.MatchString(testCase) {
+			return re.ReplaceAllStringFunc(testCase, func(match string) string {
+				return redactor.ApplyMasking(match, masking)
+			})
+		}
+	}
+	return testCase
+}
+
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
 		return s
@@ -206,16 +265,62 @@ func handleScanCommand() {
 		outputFormat string
 		patternList  string
 		listPatterns bool
+		listCategory bool
+		outputSchema bool
 		noValidate   bool
+		explain      bool
+		binaryInput  bool
+		maxSizeKB    int
+		oversize     string
+		redactQuery  bool
+		queryKeys    string
+		minSeverity  string
+		severityMask string
+		defaultMask  string
+		failSeverity string
+		failCount    int
+		rulesPath    string
+		allowlist    string
+		skipComments string
+		categories   string
+		tags         string
+		offsets      string
+		excludes     string
+		maxFileKB    int
+		concurrency  int
+		showVersion  bool
 		showHelp     bool
 	)
 
-	flag.StringVar(&inputFile, "f", "", "Input file to scan")
+	flag.StringVar(&inputFile, "f", "", "Input file or directory to scan (gzip compressed files are decompressed)")
 	flag.StringVar(&inputText, "t", "", "Input text to scan")
-	flag.StringVar(&outputFormat, "o", "text", "Output format: text, json")
-	flag.StringVar(&patternList, "p", "", "Comma-separated list of patterns to use (empty = all)")
+	flag.StringVar(&outputFormat, "o", "text", "Output format: "+strings.Join(formatterNames(), ", "))
+	flag.StringVar(&rulesPath, "rules", "", "Rule file or directory of YAML pattern definitions to load alongside the built-in patterns")
+	flag.StringVar(&allowlist, "allowlist", "", "File of values (or re:<regex> lines) never reported as PII; reloaded when it changes")
+	flag.StringVar(&skipComments, "skip-comment-prefixes", "", "Comma-separated prefixes of comment lines, e.g. #,//, whose matches are not reported")
+	flag.StringVar(&patternList, "p", "", "Comma-separated list of patterns to use (omit to use all)")
 	flag.BoolVar(&listPatterns, "list", false, "List all available patterns")
+	flag.BoolVar(&listCategory, "categories", false, "List pattern categories with enabled and total pattern counts")
+	flag.BoolVar(&outputSchema, "output-schema", false, "Print the JSON Schema of the -o json output")
 	flag.BoolVar(&noValidate, "no-validate", false, "Skip checksum validation (for testing)")
+	flag.BoolVar(&explain, "explain", false, "Explain each detection: the matching regex, validator outcome and confidence promotion")
+	flag.BoolVar(&binaryInput, "binary", false, "Treat the input file as binary and scan embedded text")
+	flag.IntVar(&maxSizeKB, "max-size-kb", 0, "Maximum input size in KB to scan (0 = unlimited)")
+	flag.StringVar(&oversize, "oversize", "truncate", "Action for input above -max-size-kb: skip, truncate")
+	flag.BoolVar(&redactQuery, "redact-query", false, "Redact values of sensitive URL query parameters")
+	flag.StringVar(&queryKeys, "query-keys", strings.Join(redactor.DefaultSensitiveQueryKeys, ","), "Comma-separated query parameter names redacted by -redact-query")
+	flag.StringVar(&minSeverity, "min-severity", "", "Scan only patterns at or above this severity: critical, high, medium, low")
+	flag.StringVar(&severityMask, "severity-mask", "", "Comma-separated severity=mode masking overrides, e.g. critical=full:[CRITICAL],low=partial:2 or low=partial:25%")
+	flag.StringVar(&defaultMask, "default-mask", "", "Masking mode for patterns without a valid masking strategy, e.g. full:[REDACTED]")
+	flag.StringVar(&failSeverity, "fail-on-severity", "", "Exit with status 2 when any detection is at or above this severity: critical, high, medium, low")
+	flag.IntVar(&failCount, "fail-on-count", 0, "Exit with status 2 when there are at least this many detections (0 = disabled)")
+	flag.StringVar(&categories, "category", "", "Comma-separated pattern categories to scan (omit to use all)")
+	flag.StringVar(&tags, "tag", "", "Comma-separated pattern tags to scan, e.g. pci,gdpr,hipaa (omit to use all)")
+	flag.StringVar(&offsets, "offsets", string(detector.OffsetBytes), "Unit of reported detection positions: bytes, runes, utf16")
+	flag.StringVar(&excludes, "exclude", "", "Comma-separated globs of paths to skip when -f is a directory (e.g. **/node_modules/**,*.min.js)")
+	flag.IntVar(&maxFileKB, "max-file-kb", 0, "Skip files larger than this many KB when -f is a directory (0 = unlimited)")
+	flag.IntVar(&concurrency, "concurrency", 4, "Number of files scanned at once when -f is a directory")
+	flag.BoolVar(&showVersion, "version", false, "Show version information")
 	flag.BoolVar(&showHelp, "h", false, "Show help")
 	flag.Parse()
 
@@ -224,6 +329,25 @@ func handleScanCommand() {
 		return
 	}
 
+	if showVersion {
+		fmt.Printf("pii-redactor %s\n", buildinfo.New(version, commit, date))
+		return
+	}
+
+	if outputSchema {
+		if err := writeOutputSchema(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	offsetUnit, err := detector.ParseOffsetUnit(offsets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create detection engine
 	engine := detector.NewEngine()
 
@@ -231,21 +355,157 @@ func handleScanCommand() {
 	if noValidate {
 		engine.DisableValidation()
 	}
+	engine.SetExplain(explain)
+
+	if rulesPath != "" {
+		loaded, failures, err := loadRules(engine, rulesPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading rules: %v\n", err)
+			os.Exit(1)
+		}
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load rule: %s\n", failure)
+		}
+		fmt.Fprintf(os.Stderr, "Loaded %d pattern(s) from %s\n", len(loaded), rulesPath)
+	}
+
+	if allowlist != "" {
+		err := engine.WatchAllowlistFile(context.Background(), allowlist, 0, func(err error) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to reload allowlist, keeping the previous one: %v\n", err)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading allowlist: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := applyScanFilters(engine, minSeverity, categories, tags); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if skipComments != "" {
+		engine.SetCommentFilter(&detector.CommentFilter{Prefixes: parseCommentPrefixes(skipComments)})
+	}
+
+	oversizeAction, err := redactor.ParseOversizeAction(oversize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
 
 	redact := redactor.NewRedactor(engine)
+	limiter := redactor.NewInputLimiter(maxSizeKB, oversizeAction)
+	redact.SetInputLimiter(limiter)
+	threshold := failThreshold{Severity: failSeverity, Count: failCount}
+	if err := threshold.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	severityMasking, err := parseSeverityMasking(severityMask)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	redact.SetSeverityMasking(severityMasking)
+	if defaultMask != "" {
+		strategy, err := parseMaskingMode(defaultMask)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -default-mask: %v\n", err)
+			os.Exit(1)
+		}
+		if err := redact.SetDefaultMasking(&strategy); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if redactQuery {
+		redact.SetSensitiveQueryKeys(strings.Split(queryKeys, ","))
+	}
 
 	if listPatterns {
 		printPatterns(engine)
 		return
 	}
 
+	if listCategory {
+		if err := writeCategoryStats(os.Stdout, outputFormat, engine.CategoryStats()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Parse pattern list
+	patternsSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "p" {
+			patternsSet = true
+		}
+	})
+	selectedPatterns, err := parsePatternList(patternList, patternsSet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+
+	if info, err := os.Stat(inputFile); inputFile != "" && err == nil && info.IsDir() {
+		if binaryInput {
+			fmt.Fprintln(os.Stderr, "-binary cannot be used with a directory")
+			os.Exit(1)
+		}
+		result, err := scanDir(ctx, redact, inputFile, dirScanOptions{
+			Excludes:    parseExcludes(excludes),
+			MaxFileSize: int64(maxFileKB) * 1024,
+			Concurrency: concurrency,
+			Patterns:    selectedPatterns,
+			Offsets:     offsetUnit,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error scanning directory: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeDirScanReport(os.Stdout, outputFormat, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		var detections []detector.DetectionResult
+		for _, file := range result.Files {
+			detections = append(detections, file.Detections...)
+		}
+		exitOnThreshold(threshold, detections)
+		return
+	}
+
+	if binaryInput {
+		if inputFile == "" {
+			fmt.Fprintln(os.Stderr, "-binary requires an input file (-f)")
+			os.Exit(1)
+		}
+		// Binary content has no characters to count, only bytes
+		if offsetUnit != detector.OffsetBytes {
+			fmt.Fprintln(os.Stderr, "-binary reports byte offsets only and cannot be used with -offsets "+offsets)
+			os.Exit(1)
+		}
+		result := scanBinaryFile(ctx, engine, redact, limiter, inputFile, extractLimit(maxSizeKB), selectedPatterns)
+		warnOversized(result, maxSizeKB)
+		writeOutput(outputFormat, offsetUnit, result)
+		if result.Skipped {
+			os.Exit(exitNotScanned)
+		}
+		exitOnThreshold(threshold, result.Detections)
+		return
+	}
+
 	// Determine input source
 	var input string
 	if inputText != "" {
 		input = inputText
 	} else if inputFile != "" {
-		content, err := os.ReadFile(inputFile)
-		if err != nil {
+		content, err := readInputFile(inputFile, extractLimit(maxSizeKB))
+		if err != nil && !(maxSizeKB > 0 && errors.Is(err, source.ErrExtractLimit)) {
 			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
 			os.Exit(1)
 		}
@@ -266,20 +526,8 @@ func handleScanCommand() {
 		os.Exit(1)
 	}
 
-	ctx := context.Background()
-
-	// Parse pattern list
-	var selectedPatterns []string
-	if patternList != "" {
-		selectedPatterns = strings.Split(patternList, ",")
-		for i := range selectedPatterns {
-			selectedPatterns[i] = strings.TrimSpace(selectedPatterns[i])
-		}
-	}
-
 	// Perform detection and redaction
 	var result *redactor.RedactResult
-	var err error
 
 	if len(selectedPatterns) > 0 {
 		result, err = redact.RedactWithPatterns(ctx, input, selectedPatterns)
@@ -292,13 +540,359 @@ func handleScanCommand() {
 		os.Exit(1)
 	}
 
+	warnOversized(result, maxSizeKB)
+
 	// Output results
-	switch outputFormat {
-	case "json":
-		outputJSON(result)
+	writeOutput(outputFormat, offsetUnit, result)
+	if result.Skipped {
+		os.Exit(exitNotScanned)
+	}
+	exitOnThreshold(threshold, result.Detections)
+}
+
+// warnOversized reports on stderr an input the input limiter skipped or
+// truncated
+func warnOversized(result *redactor.RedactResult, maxSizeKB int) {
+	if result.Skipped {
+		fmt.Fprintf(os.Stderr, "Error: input exceeds %d KB and was not scanned; its text is withheld\n", maxSizeKB)
+	} else if result.Truncated {
+		fmt.Fprintf(os.Stderr, "Warning: input exceeds %d KB; only the first %d KB was scanned and the rest is withheld\n", maxSizeKB, maxSizeKB)
+	}
+}
+
+// exitThresholdExceeded is the exit status when findings meet the
+// -fail-on-severity or -fail-on-count threshold
+const exitThresholdExceeded = 2
+
+// exitNotScanned is the exit status when the input exceeded -max-size-kb and
+// -oversize skip left it unscanned
+const exitNotScanned = 3
+
+// failThreshold decides whether findings should fail a run, e.g. in CI.
+// A zero threshold never fails.
+type failThreshold struct {
+	// Severity fails on any detection at or above this severity
+	Severity string
+
+	// Count fails when there are at least this many detections; zero disables it
+	Count int
+}
+
+// validate checks the threshold's severity and count
+func (t failThreshold) validate() error {
+	if t.Severity != "" && patterns.SeverityLevel(t.Severity) == 0 {
+		return fmt.Errorf("unknown severity %q for -fail-on-severity (use critical, high, medium or low)", t.Severity)
+	}
+	if t.Count < 0 {
+		return fmt.Errorf("-fail-on-count must not be negative")
+	}
+	return nil
+}
+
+// check returns an error describing why the detections meet the threshold,
+// or nil if they do not
+func (t failThreshold) check(detections []detector.DetectionResult) error {
+	if t.Severity != "" {
+		minLevel := patterns.SeverityLevel(t.Severity)
+		for _, d := range detections {
+			if patterns.SeverityLevel(d.Severity) >= minLevel {
+				return fmt.Errorf("%s detection (%s) at or above severity %s", d.Severity, d.PatternName, t.Severity)
+			}
+		}
+	}
+	if t.Count > 0 && len(detections) >= t.Count {
+		return fmt.Errorf("%d detection(s), threshold is %d", len(detections), t.Count)
+	}
+	return nil
+}
+
+// exitOnThreshold exits with exitThresholdExceeded when the detections meet the threshold
+func exitOnThreshold(t failThreshold, detections []detector.DetectionResult) {
+	if err := t.check(detections); err != nil {
+		fmt.Fprintf(os.Stderr, "Failing: %v\n", err)
+		os.Exit(exitThresholdExceeded)
+	}
+}
+
+// writeOutput formats the result with the selected formatter to stdout,
+// reporting detection positions in the given unit
+func writeOutput(format string, unit detector.OffsetUnit, result *redactor.RedactResult) {
+	result.Detections = detector.ConvertOffsets(result.OriginalText, result.Detections, unit)
+	if err := formatResult(os.Stdout, format, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// readInputFile reads an input file, decompressing it when it has a .gz
+// extension or starts with the gzip magic bytes. At most maxSize bytes are
+// decompressed (source.DefaultMaxExtractTotalSize when maxSize is zero or
+// less); beyond that the first maxSize bytes are returned with an error
+// wrapping source.ErrExtractLimit.
+func readInputFile(path string, maxSize int64) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(path, ".gz") || source.IsGzip(data) {
+		return source.Gunzip(data, maxSize)
+	}
+	return data, nil
+}
+
+// extractLimit returns the size compressed input is decompressed to: one
+// byte past -max-size-kb, so that the input limiter sees oversized input and
+// skips or truncates it as configured, or 0 when there is no limit
+func extractLimit(maxSizeKB int) int64 {
+	if maxSizeKB <= 0 {
+		return 0
+	}
+	return int64(maxSizeKB)*1024 + 1
+}
+
+// scanBinaryFile detects PII in the text embedded in a binary file, within
+// the size limit enforced by limiter. Binary content is not rewritten, so only
+// the per-detection redactions are reported.
+func scanBinaryFile(ctx context.Context, engine *detector.Engine, redact *redactor.Redactor, limiter *redactor.InputLimiter, path string, maxSize int64, selectedPatterns []string) *redactor.RedactResult {
+	data, err := readInputFile(path, maxSize)
+	if err != nil && !(maxSize > 0 && errors.Is(err, source.ErrExtractLimit)) {
+		fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+		os.Exit(1)
+	}
+
+	scanned, ok := limiter.Limit(string(data))
+	if !ok || len(scanned) < len(data) {
+		engine.RecordOversizedInput(!ok)
+	}
+	if !ok {
+		return &redactor.RedactResult{RedactedText: redactor.UnscannedPlaceholder, Skipped: true}
+	}
+	truncated := len(scanned) < len(data)
+	data = data[:len(scanned)]
+
+	var detections []detector.DetectionResult
+	if len(selectedPatterns) > 0 {
+		detections, err = engine.DetectInBinaryWithPatterns(ctx, data, selectedPatterns)
+	} else {
+		detections, err = engine.DetectInBinary(ctx, data)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error during detection: %v\n", err)
+		os.Exit(1)
+	}
+
+	redactedCount := 0
+	for i := range detections {
+		if strategy, ok := redact.MaskingStrategy(&detections[i]); ok {
+			detections[i].RedactedText = redact.MaskDetection(&detections[i], strategy)
+			redactedCount++
+		}
+	}
+
+	return &redactor.RedactResult{
+		Detections:    detections,
+		RedactedCount: redactedCount,
+		Scanned:       true,
+		Truncated:     truncated,
+	}
+}
+
+// loadRules adds and enables the patterns defined in the rule file or
+// directory at path. It returns the names of the loaded patterns, sorted,
+// and a description of every file or pattern that failed to load.
+func loadRules(engine *detector.Engine, path string) ([]string, []string, error) {
+	result, err := source.LoadLocalRules(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	failures := append([]string{}, result.Errors...)
+
+	var specs []detector.NamedPatternSpec
+	for _, ruleSet := range result.RuleSets {
+		for i := range ruleSet.Patterns {
+			def := &ruleSet.Patterns[i]
+			if def.Name == "" {
+				failures = append(failures, fmt.Sprintf("%s: pattern without a name", ruleSet.Name))
+				continue
+			}
+			specs = append(specs, detector.NamedPatternSpec{Name: def.Name, Spec: def.ToPatternSpec()})
+		}
+	}
+
+	failed := engine.AddPatterns(specs)
+	var loaded []string
+	for _, named := range specs {
+		if err, ok := failed[named.Name]; ok {
+			failures = append(failures, fmt.Sprintf("%s: %v", named.Name, err))
+			continue
+		}
+		engine.EnablePattern(named.Name)
+		loaded = append(loaded, named.Name)
+	}
+	sort.Strings(loaded)
+	return loaded, failures, nil
+}
+
+// applyScanFilters restricts scanning to patterns at or above minSeverity and,
+// when categories or tags are non-empty, disables patterns outside the listed
+// categories or carrying none of the listed tags. Patterns selected explicitly
+// with -p are still subject to the severity filter.
+func applyScanFilters(engine *detector.Engine, minSeverity, categories, tags string) error {
+	if minSeverity != "" {
+		if patterns.SeverityLevel(minSeverity) == 0 {
+			return fmt.Errorf("unknown severity %q (use critical, high, medium or low)", minSeverity)
+		}
+		engine.SetMinSeverity(minSeverity)
+	}
+
+	if categories != "" {
+		known := make(map[string]bool)
+		for _, category := range patterns.GetCategories() {
+			known[category] = true
+		}
+		selected := make(map[string]bool)
+		for _, category := range strings.Split(categories, ",") {
+			if category = strings.TrimSpace(category); category == "" {
+				continue
+			}
+			if !known[category] {
+				return fmt.Errorf("unknown category %q", category)
+			}
+			selected[category] = true
+		}
+
+		for _, name := range engine.ListPatterns() {
+			if pattern, ok := engine.GetPattern(name); ok && !selected[pattern.Category] {
+				engine.DisablePattern(name)
+			}
+		}
+	}
+
+	if tags != "" {
+		var selected []string
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag == "" {
+				continue
+			}
+			// Tags are free-form and may come from -rules, so a tag is
+			// known when any loaded pattern carries it
+			if len(engine.ListPatternsByTag(tag)) == 0 {
+				return fmt.Errorf("unknown tag %q", tag)
+			}
+			selected = append(selected, tag)
+		}
+
+		tagged := make(map[string]bool)
+		for _, name := range engine.ListPatternsByTags(selected) {
+			tagged[name] = true
+		}
+		for _, name := range engine.ListPatterns() {
+			if !tagged[name] {
+				engine.DisablePattern(name)
+			}
+		}
+	}
+	return nil
+}
+
+// defaultSeverityReveal is the number of characters partial severity masking
+// shows at each end when no count is given
+const defaultSeverityReveal = 2
+
+// parseSeverityMasking parses the -severity-mask flag value, a comma-separated
+// list of severity=mode pairs. A mode is full (optionally full:REPLACEMENT),
+// partial (optionally partial:N or partial:N%, revealing N characters or N
+// percent of the match at each end), hash or tokenize. An empty value sets no overrides.
+func parseSeverityMasking(value string) (map[string]patterns.MaskingStrategy, error) {
+	strategies := make(map[string]patterns.MaskingStrategy)
+	for _, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		severity, mode, ok := strings.Cut(pair, "=")
+		severity = strings.TrimSpace(severity)
+		if !ok {
+			return nil, fmt.Errorf("invalid severity mask %q: expected severity=mode", pair)
+		}
+		if patterns.SeverityLevel(severity) == 0 {
+			return nil, fmt.Errorf("unknown severity %q in severity mask (use critical, high, medium or low)", severity)
+		}
+
+		strategy, err := parseMaskingMode(mode)
+		if err != nil {
+			return nil, fmt.Errorf("severity %s: %w", severity, err)
+		}
+		strategies[severity] = strategy
+	}
+
+	if len(strategies) == 0 {
+		return nil, nil
+	}
+	return strategies, nil
+}
+
+// parseMaskingMode parses a masking mode of the -severity-mask and
+// -default-mask flags: full (optionally full:REPLACEMENT), partial
+// (optionally partial:N or partial:N%, revealing N characters or N percent
+// of the match at each end), hash or tokenize
+func parseMaskingMode(mode string) (patterns.MaskingStrategy, error) {
+	mode, arg, hasArg := strings.Cut(strings.TrimSpace(mode), ":")
+	strategy := patterns.MaskingStrategy{Type: mode, MaskChar: "*"}
+	switch mode {
+	case "full":
+		strategy.Replacement = arg
+	case "partial":
+		strategy.ShowFirst, strategy.ShowLast = defaultSeverityReveal, defaultSeverityReveal
+		if hasArg {
+			n, percent, err := patterns.ParseRevealAmount(intstr.Parse(arg))
+			if err != nil {
+				return strategy, fmt.Errorf("invalid partial reveal %q: expected a non-negative number or a percentage such as 25%%", arg)
+			}
+			strategy.ShowFirst, strategy.ShowLast = n, n
+			strategy.ShowFirstPercent, strategy.ShowLastPercent = percent, percent
+		}
+	case "hash", "tokenize":
+		if hasArg {
+			return strategy, fmt.Errorf("masking mode %s takes no argument", mode)
+		}
 	default:
-		outputText(result)
+		return strategy, fmt.Errorf("unknown masking mode %q (use full, partial, hash or tokenize)", mode)
+	}
+	return strategy, nil
+}
+
+// parseCommentPrefixes splits a comma-separated list of comment prefixes,
+// trimming them and dropping blanks
+func parseCommentPrefixes(value string) []string {
+	var prefixes []string
+	for _, prefix := range strings.Split(value, ",") {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// parsePatternList parses the -p flag value. When the flag was not given, nil is
+// returned and all enabled patterns are used. An explicitly given list that
+// contains no pattern names after trimming (e.g. "" or ",") is an error.
+func parsePatternList(value string, set bool) ([]string, error) {
+	if !set {
+		return nil, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
 	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("-p was given but lists no patterns (omit -p to use all enabled patterns)")
+	}
+	return names, nil
 }
 
 func printHelp() {
@@ -310,14 +904,61 @@ Usage:
 
 Commands:
   rules test <file>    Test patterns in a rule file against its test cases
+  rules bench <path>   Report per-pattern evaluation time and matches for a rule
+                       file or directory, or the built-in patterns with -builtin
+                       (flags: -o text|json, -sort time|matches|name, -iterations)
+  serve                Serve POST /v1/redact/stream, which redacts a stream of
+                       lines as they arrive, e.g. as a log forwarding sidecar
+                       (flags: -addr, -rules, -max-line-kb)
 
 Flags:
   -t string      Input text to scan
-  -f string      Input file to scan
-  -o string      Output format: text, json (default "text")
-  -p string      Comma-separated list of patterns to use (empty = all)
+  -f string      Input file or directory to scan (gzip compressed files are decompressed)
+  -o string      Output format: text, json (default "text"); the JSON output carries
+                 a schema_version bumped only on breaking changes
+  -output-schema Print the JSON Schema of the -o json output (see docs/output-schema.json)
+  -p string      Comma-separated list of patterns to use (omit to use all)
+  -rules string  Rule file or directory of YAML pattern definitions (a single pattern,
+                 a list or a rule set) to load alongside the built-in patterns
+  -allowlist     File of known false positives never reported as PII, one value or
+                 re:<regex> per line; reloaded while scanning when the file changes
+  -skip-comment-prefixes
+                 Comma-separated prefixes of comment lines, e.g. #,//; matches on lines
+                 starting with one (after indentation) are not reported
   -list          List all available patterns
+  -categories    List pattern categories with enabled and total pattern counts
+                 (respects -min-severity, -category and -tag; -o json for JSON)
   -no-validate   Skip checksum validation (for testing)
+  -explain       Explain each detection: the matching regex, validator outcome
+                 and confidence promotion (for tuning rules)
+  -binary        Treat the input file as binary and scan embedded text
+  -max-size-kb   Maximum input size in KB to scan (0 = unlimited); .gz input is
+                 decompressed only this far, and at most 100 MB when unlimited
+  -oversize      Action for input above -max-size-kb: skip, truncate (default "truncate");
+                 text that is not scanned is output as [UNSCANNED], and skipped
+                 input exits with status 3
+  -redact-query  Redact values of sensitive URL query parameters
+  -query-keys    Comma-separated query parameter names redacted by -redact-query
+  -min-severity  Scan only patterns at or above this severity: critical, high, medium, low
+  -category      Comma-separated pattern categories to scan (omit to use all)
+  -tag           Comma-separated pattern tags to scan, e.g. pci,gdpr,hipaa (omit to use all)
+  -fail-on-severity
+                 Exit with status 2 when any detection is at or above this severity
+  -fail-on-count Exit with status 2 when there are at least this many detections
+                 (without either flag the exit status ignores findings)
+  -severity-mask Comma-separated severity=mode masking overrides applied instead of
+                 pattern strategies; modes: full[:REPLACEMENT], partial[:N|:N%], hash,
+                 tokenize
+  -default-mask  Masking mode for patterns without a valid masking strategy (e.g. rules
+                 missing one), instead of partial masking; same modes as -severity-mask
+  -offsets       Unit of reported positions: bytes, runes, utf16 (default "bytes");
+                 redaction always uses byte offsets internally; -binary
+                 supports bytes only
+  -exclude       Comma-separated globs of paths to skip when -f is a directory;
+                 ** matches any number of directories (e.g. **/node_modules/**,*.min.js)
+  -max-file-kb   Skip files larger than this many KB when -f is a directory (0 = unlimited)
+  -concurrency   Number of files scanned at once when -f is a directory (default 4)
+  -version       Show version information
   -h             Show help
 
 Examples:
@@ -327,20 +968,66 @@ Examples:
   # Scan file
   pii-redactor -f /var/log/app.log
 
+  # Scan a source tree, skipping dependencies, minified files and large files
+  pii-redactor -f . -exclude "**/node_modules/**,*.min.js" -max-file-kb 1024
+
+  # Scan a gzipped log file
+  pii-redactor -f /var/log/app.log.gz
+
+  # Scan a binary (e.g. protobuf) log file
+  pii-redactor -f /var/log/app.pb -binary
+
+  # Try custom rules together with the built-in patterns
+  pii-redactor -f /var/log/app.log -rules ./rules
+
+  # Suppress known test values listed in a file
+  pii-redactor -f /var/log/app.log -allowlist ./allowlist.txt
+
+  # Ignore example values in comments of config files
+  pii-redactor -f ./config -skip-comment-prefixes "#,//"
+
   # Use specific patterns
   pii-redactor -t "Call me at 010-1234-5678" -p "phone-kr,email"
 
+  # Redact tokens in URL query strings
+  pii-redactor -t "GET /callback?code=abc&access_token=xyz" -redact-query
+
+  # Gate CI on high severity findings or more than 10 findings of any severity
+  pii-redactor -f . -exclude "**/testdata/**" -fail-on-severity high -fail-on-count 11
+
+  # Mark critical findings loudly and lightly mask low severity ones
+  pii-redactor -f /var/log/app.log -severity-mask "critical=full:[CRITICAL PII],low=partial:2"
+
+  # Scan only for critical secrets
+  pii-redactor -f /var/log/app.log -min-severity critical -category secrets
+
+  # Scan only with the patterns relevant to HIPAA
+  pii-redactor -f /var/log/app.log -tag hipaa
+
   # Output as JSON
   pii-redactor -t "SSN: 920101-1234567" -o json
 
+  # See which rule and validator produced a match
+  pii-redactor -t "card 4111-1111-1111-1111" -p credit-card -explain
+
   # Read from stdin
   echo "test@example.com" | pii-redactor
 
   # List all patterns
   pii-redactor -list
 
+  # Count patterns per category as JSON
+  pii-redactor -categories -o json
+
   # Test a rule file
-  pii-redactor rules test rules/korea/rrn.yaml`)
+  pii-redactor rules test rules/korea/rrn.yaml
+
+  # Find the most expensive built-in patterns
+  pii-redactor rules bench -builtin -sort time
+
+  # Redact log lines streamed over HTTP
+  pii-redactor serve -addr :8080 &
+  tail -f /var/log/app.log | curl -sN -X POST -T - http://localhost:8080/v1/redact/stream`)
 }
 
 func printPatterns(engine *detector.Engine) {
@@ -351,65 +1038,35 @@ func printPatterns(engine *detector.Engine) {
 	for name, spec := range patterns.BuiltInPatterns {
 		fmt.Printf("%-25s %s\n", name, spec.DisplayName)
 		fmt.Printf("  Severity: %s\n", spec.Severity)
+		if len(spec.Tags) > 0 {
+			fmt.Printf("  Tags: %s\n", strings.Join(spec.Tags, ", "))
+		}
 		fmt.Printf("  Description: %s\n", spec.Description)
 		fmt.Println()
 	}
 }
 
-func outputText(result *redactor.RedactResult) {
-	if result.RedactedCount == 0 {
-		fmt.Println("No PII detected.")
-		fmt.Println()
-		fmt.Println("Original text:")
-		fmt.Println(result.OriginalText)
-		return
-	}
-
-	fmt.Printf("Detected %d PII instance(s)\n", result.RedactedCount)
-	fmt.Println("========================================")
-	fmt.Println()
-
-	// Group detections by pattern
-	byPattern := make(map[string][]detector.DetectionResult)
-	for _, d := range result.Detections {
-		byPattern[d.PatternName] = append(byPattern[d.PatternName], d)
-	}
-
-	for pattern, detections := range byPattern {
-		fmt.Printf("[%s] %s (%d found)\n", detections[0].Severity, pattern, len(detections))
-		for _, d := range detections {
-			fmt.Printf("  - Original: %s\n", d.MatchedText)
-			fmt.Printf("    Redacted: %s\n", d.RedactedText)
-			fmt.Printf("    Position: %d-%d\n", d.Position.Start, d.Position.End)
+// writeCategoryStats writes the pattern counts per category as a text table or JSON
+func writeCategoryStats(w io.Writer, format string, stats map[string]patterns.CategoryStat) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	case "text":
+		categories := make([]string, 0, len(stats))
+		for category := range stats {
+			categories = append(categories, category)
 		}
-		fmt.Println()
-	}
-
-	fmt.Println("========================================")
-	fmt.Println("Redacted Output:")
-	fmt.Println("========================================")
-	fmt.Println(result.RedactedText)
-}
+		sort.Strings(categories)
 
-type jsonOutput struct {
-	DetectionCount int                        `json:"detection_count"`
-	Detections     []detector.DetectionResult `json:"detections"`
-	OriginalText   string                     `json:"original_text"`
-	RedactedText   string                     `json:"redacted_text"`
-}
-
-func outputJSON(result *redactor.RedactResult) {
-	output := jsonOutput{
-		DetectionCount: result.RedactedCount,
-		Detections:     result.Detections,
-		OriginalText:   result.OriginalText,
-		RedactedText:   result.RedactedText,
-	}
-
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(output); err != nil {
-		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
-		os.Exit(1)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CATEGORY\tENABLED\tTOTAL")
+		for _, category := range categories {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", category, stats[category].Enabled, stats[category].Total)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q (available: json, text)", format)
 	}
 }
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// OutputFormatter writes a redaction result in a specific output format
+type OutputFormatter interface {
+	Format(w io.Writer, result *redactor.RedactResult) error
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[string]OutputFormatter{
+		"text": textFormatter{},
+		"json": jsonFormatter{},
+	}
+)
+
+// RegisterFormatter registers an output formatter under the given format name,
+// replacing any existing formatter with that name
+func RegisterFormatter(name string, formatter OutputFormatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters[name] = formatter
+}
+
+// formatterNames returns the registered format names in sorted order
+func formatterNames() []string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatResult writes the result using the formatter registered for format
+func formatResult(w io.Writer, format string, result *redactor.RedactResult) error {
+	formattersMu.RLock()
+	formatter, ok := formatters[format]
+	formattersMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("unknown output format %q (available: %s)", format, strings.Join(formatterNames(), ", "))
+	}
+	return formatter.Format(w, result)
+}
+
+// textFormatter writes a human-readable report
+type textFormatter struct{}
+
+// Format implements OutputFormatter
+func (textFormatter) Format(w io.Writer, result *redactor.RedactResult) error {
+	var b strings.Builder
+
+	if result.RedactedCount == 0 {
+		if result.Skipped {
+			b.WriteString("Input not scanned.\n")
+		} else {
+			b.WriteString("No PII detected.\n")
+		}
+		switch {
+		case result.Skipped || result.Truncated:
+			// Text that was not scanned may hold PII, so it stays withheld
+			if result.RedactedText != "" {
+				b.WriteString("\nOutput:\n")
+				b.WriteString(result.RedactedText + "\n")
+			}
+		case result.OriginalText != "":
+			b.WriteString("\nOriginal text:\n")
+			b.WriteString(result.OriginalText + "\n")
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "Detected %d PII instance(s)\n", result.RedactedCount)
+	b.WriteString("========================================\n\n")
+
+	// Group detections by pattern
+	byPattern := make(map[string][]detector.DetectionResult)
+	for _, d := range result.Detections {
+		byPattern[d.PatternName] = append(byPattern[d.PatternName], d)
+	}
+
+	for pattern, detections := range byPattern {
+		fmt.Fprintf(&b, "[%s] %s (%d found)\n", detections[0].Severity, pattern, len(detections))
+		for _, d := range detections {
+			fmt.Fprintf(&b, "  - Original: %s\n", d.MatchedText)
+			fmt.Fprintf(&b, "    Redacted: %s\n", d.RedactedText)
+			fmt.Fprintf(&b, "    Position: %d-%d\n", d.Position.Start, d.Position.End)
+			if d.Explanation != nil {
+				writeExplanation(&b, d)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if result.RedactedText != "" {
+		b.WriteString("========================================\n")
+		b.WriteString("Redacted Output:\n")
+		b.WriteString("========================================\n")
+		b.WriteString(result.RedactedText + "\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeExplanation writes the provenance of a detection in explain mode
+func writeExplanation(b *strings.Builder, d detector.DetectionResult) {
+	x := d.Explanation
+	if len(x.Components) > 0 {
+		fmt.Fprintf(b, "    Composite: %s\n", strings.Join(x.Components, " + "))
+	} else {
+		fmt.Fprintf(b, "    Rule: #%d %s\n", x.Rule, x.Regex)
+	}
+	if x.Validator != "" {
+		fmt.Fprintf(b, "    Validator: %s (%s)\n", x.Validator, x.Validation)
+	} else {
+		b.WriteString("    Validator: none\n")
+	}
+	if x.PromotedBy != "" {
+		fmt.Fprintf(b, "    Confidence: %s (promoted from %s by %s)\n", d.Confidence, x.RuleConfidence, x.PromotedBy)
+	} else {
+		fmt.Fprintf(b, "    Confidence: %s\n", d.Confidence)
+	}
+}
+
+// outputSchemaVersion is the version of the JSON output shape, described by
+// docs/output-schema.json. Adding fields keeps the version; it is bumped only
+// when a field is renamed, removed or changes type, so parsers can pin to it.
+const outputSchemaVersion = 1
+
+// jsonOutput is the JSON output of a scan
+type jsonOutput struct {
+	SchemaVersion  int                        `json:"schema_version"`
+	DetectionCount int                        `json:"detection_count"`
+	Detections     []detector.DetectionResult `json:"detections"`
+	OriginalText   string                     `json:"original_text"`
+	RedactedText   string                     `json:"redacted_text"`
+}
+
+// jsonFormatter writes the result as indented JSON
+type jsonFormatter struct{}
+
+// Format implements OutputFormatter
+func (jsonFormatter) Format(w io.Writer, result *redactor.RedactResult) error {
+	output := jsonOutput{
+		SchemaVersion:  outputSchemaVersion,
+		DetectionCount: result.RedactedCount,
+		Detections:     result.Detections,
+		OriginalText:   result.OriginalText,
+		RedactedText:   result.RedactedText,
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
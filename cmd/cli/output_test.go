@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// countFormatter writes only the detection count
+type countFormatter struct{}
+
+func (countFormatter) Format(w io.Writer, result *redactor.RedactResult) error {
+	_, err := fmt.Fprintf(w, "count=%d\n", result.RedactedCount)
+	return err
+}
+
+func testResult() *redactor.RedactResult {
+	return &redactor.RedactResult{
+		OriginalText: "mail test@example.com",
+		RedactedText: "mail te**************",
+		Detections: []detector.DetectionResult{
+			{PatternName: "email", MatchedText: "test@example.com", RedactedText: "te**************", Severity: "medium"},
+		},
+		RedactedCount: 1,
+	}
+}
+
+func TestFormatResult_CustomFormatter(t *testing.T) {
+	RegisterFormatter("count", countFormatter{})
+	defer func() {
+		formattersMu.Lock()
+		delete(formatters, "count")
+		formattersMu.Unlock()
+	}()
+
+	var buf bytes.Buffer
+	if err := formatResult(&buf, "count", testResult()); err != nil {
+		t.Fatalf("formatResult() error = %v", err)
+	}
+	if buf.String() != "count=1\n" {
+		t.Errorf("output = %q, want count=1", buf.String())
+	}
+
+	found := false
+	for _, name := range formatterNames() {
+		if name == "count" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected registered formatter to be listed")
+	}
+}
+
+func TestFormatResult_BuiltIn(t *testing.T) {
+	tests := []struct {
+		format string
+		check  func(t *testing.T, out string)
+	}{
+		{
+			format: "text",
+			check: func(t *testing.T, out string) {
+				if !strings.Contains(out, "Detected 1 PII instance(s)") || !strings.Contains(out, "mail te**************") {
+					t.Errorf("unexpected text output: %q", out)
+				}
+			},
+		},
+		{
+			format: "json",
+			check: func(t *testing.T, out string) {
+				var decoded jsonOutput
+				if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+					t.Fatalf("invalid JSON output: %v", err)
+				}
+				if decoded.SchemaVersion != outputSchemaVersion {
+					t.Errorf("SchemaVersion = %d, want %d", decoded.SchemaVersion, outputSchemaVersion)
+				}
+				if decoded.DetectionCount != 1 || decoded.RedactedText != "mail te**************" {
+					t.Errorf("unexpected JSON output: %+v", decoded)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := formatResult(&buf, tt.format, testResult()); err != nil {
+				t.Fatalf("formatResult() error = %v", err)
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestOutputSchema(t *testing.T) {
+	// The committed schema must match the one generated from jsonOutput;
+	// regenerate it with make output-schema and bump outputSchemaVersion
+	// when the change is breaking
+	want, err := os.ReadFile(filepath.Join("..", "..", "docs", "output-schema.json"))
+	if err != nil {
+		t.Fatalf("reading committed schema: %v", err)
+	}
+	var got bytes.Buffer
+	if err := writeOutputSchema(&got); err != nil {
+		t.Fatalf("writeOutputSchema() error = %v", err)
+	}
+	if got.String() != string(want) {
+		t.Errorf("docs/output-schema.json is out of date, run make output-schema:\n%s", got.String())
+	}
+
+	// Every top-level key of the output is described, including the version
+	var buf bytes.Buffer
+	if err := formatResult(&buf, "json", testResult()); err != nil {
+		t.Fatalf("formatResult() error = %v", err)
+	}
+	var output map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if string(output["schema_version"]) != strconv.Itoa(outputSchemaVersion) {
+		t.Errorf("schema_version = %s, want %d", output["schema_version"], outputSchemaVersion)
+	}
+	schema := outputSchema()
+	if len(output) != len(schema.Properties) {
+		t.Errorf("output has %d keys, schema describes %d", len(output), len(schema.Properties))
+	}
+	for key := range output {
+		if _, ok := schema.Properties[key]; !ok {
+			t.Errorf("output key %q is missing from the schema", key)
+		}
+	}
+	for _, key := range schema.Required {
+		if _, ok := output[key]; !ok {
+			t.Errorf("required key %q is missing from the output", key)
+		}
+	}
+}
+
+func TestFormatResult_Explain(t *testing.T) {
+	result := testResult()
+	result.Detections[0].Confidence = "high"
+	result.Detections[0].Explanation = &detector.Explanation{
+		Rule:           1,
+		Regex:          `\d{16}`,
+		RuleConfidence: "medium",
+		Validator:      "luhn",
+		Validation:     detector.ValidationPassed,
+		PromotedBy:     "validator",
+	}
+
+	var buf bytes.Buffer
+	if err := formatResult(&buf, "text", result); err != nil {
+		t.Fatalf("formatResult() error = %v", err)
+	}
+	for _, want := range []string{
+		`Rule: #1 \d{16}`,
+		"Validator: luhn (passed)",
+		"Confidence: high (promoted from medium by validator)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("text output %q does not contain %q", buf.String(), want)
+		}
+	}
+
+	buf.Reset()
+	if err := formatResult(&buf, "text", testResult()); err != nil {
+		t.Fatalf("formatResult() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Validator:") {
+		t.Errorf("text output %q explains a detection without an explanation", buf.String())
+	}
+}
+
+func TestFormatResult_Unknown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := formatResult(&buf, "nope", testResult()); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
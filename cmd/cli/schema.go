@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// jsonSchema is the subset of JSON Schema the output schema uses
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 any                    `json:"type,omitempty"`
+	Const                any                    `json:"const,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+}
+
+// outputSchema returns the JSON Schema of the JSON output, generated from
+// jsonOutput so the two cannot drift apart
+func outputSchema() *jsonSchema {
+	schema := typeSchema(reflect.TypeOf(jsonOutput{}))
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = fmt.Sprintf("pii-redactor scan output, schema version %d", outputSchemaVersion)
+	schema.Properties["schema_version"].Const = outputSchemaVersion
+	return schema
+}
+
+// writeOutputSchema writes the output schema as indented JSON
+func writeOutputSchema(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(outputSchema())
+}
+
+// typeSchema describes how encoding/json encodes values of type t. Slices and
+// maps may encode as null, and fields tagged omitempty are not required.
+func typeSchema(t reflect.Type) *jsonSchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: []string{"array", "null"}, Items: typeSchema(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: []string{"object", "null"}, AdditionalProperties: typeSchema(t.Elem())}
+	case reflect.Struct:
+		schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema)}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" && opts == "" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = typeSchema(field.Type)
+			if !strings.Contains(","+opts+",", ",omitempty,") {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		return schema
+	default:
+		return &jsonSchema{}
+	}
+}
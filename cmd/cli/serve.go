@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// streamPath is the endpoint redacting a stream of lines
+const streamPath = "/v1/redact/stream"
+
+// redactionErrorsTrailer is the response trailer carrying the number of lines
+// of a stream that could not be redacted
+const redactionErrorsTrailer = "X-Redaction-Errors"
+
+// runServe implements "serve"
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	rulesPath := fs.String("rules", "", "Rule file or directory of YAML pattern definitions to load alongside the built-in patterns")
+	maxLineKB := fs.Int("max-line-kb", redactor.DefaultMaxLineBytes/1024, "Longest line in KB redacted; longer lines are replaced by "+redactor.LineFailedPlaceholder)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("usage: pii-redactor serve [flags]")
+	}
+
+	engine := detector.NewEngine()
+	if *rulesPath != "" {
+		loaded, failures, err := loadRules(engine, *rulesPath)
+		if err != nil {
+			return fmt.Errorf("loading rules: %w", err)
+		}
+		for _, failure := range failures {
+			log.Printf("Warning: failed to load rule: %s", failure)
+		}
+		log.Printf("Loaded %d pattern(s) from %s", len(loaded), *rulesPath)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(streamPath, streamHandler(redactor.NewRedactor(engine), *maxLineKB*1024))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	log.Printf("Serving %s on %s", streamPath, *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+// streamHandler redacts the lines of a POST body as they arrive, flushing
+// each redacted line before reading the next. Lines that cannot be redacted
+// are replaced by a placeholder without ending the stream, and counted in
+// the X-Redaction-Errors trailer.
+func streamHandler(redact *redactor.Redactor, maxLineBytes int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Respond while the body is still being read, as a log forwarder
+		// keeps the request open for as long as it has lines to send
+		controller := http.NewResponseController(w)
+		if err := controller.EnableFullDuplex(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Trailer", redactionErrorsTrailer)
+		w.WriteHeader(http.StatusOK)
+		if err := controller.Flush(); err != nil {
+			return
+		}
+
+		var failed atomic.Int64
+		_, err := redact.RedactStream(r.Context(), r.Body, flushWriter{w: w, controller: controller}, redactor.StreamOptions{
+			MaxLineBytes: maxLineBytes,
+			OnError: func(line int, err error) {
+				failed.Add(1)
+				log.Printf("Failed to redact line %d from %s: %v", line, r.RemoteAddr, err)
+			},
+		})
+		if err != nil && !errors.Is(err, r.Context().Err()) {
+			log.Printf("Redaction stream from %s ended early: %v", r.RemoteAddr, err)
+		}
+		w.Header().Set(redactionErrorsTrailer, strconv.FormatInt(failed.Load(), 10))
+	})
+}
+
+// flushWriter flushes every write to the client
+type flushWriter struct {
+	w          io.Writer
+	controller *http.ResponseController
+}
+
+// Write implements io.Writer
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, f.controller.Flush()
+}
+
+// handleServeCommand runs the streaming redaction server until it fails
+func handleServeCommand(args []string) {
+	if err := runServe(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+func TestStreamHandler(t *testing.T) {
+	server := httptest.NewServer(streamHandler(redactor.NewRedactor(detector.NewEngine()), 256))
+	defer server.Close()
+
+	body, input := io.Pipe()
+	req, err := http.NewRequest(http.MethodPost, server.URL+streamPath, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	output := bufio.NewReader(resp.Body)
+
+	// Each line is redacted as soon as it arrives, while the request is open
+	if _, err := io.WriteString(input, "first user@example.com\n"); err != nil {
+		t.Fatal(err)
+	}
+	line, err := output.ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading first line: %v", err)
+	}
+	if line != "first us**************\n" {
+		t.Errorf("first line = %q", line)
+	}
+
+	const lines = 2000
+	const malformed = 1000
+	go func() {
+		w := bufio.NewWriter(input)
+		for i := 1; i < lines; i++ {
+			if i == malformed {
+				fmt.Fprintf(w, "%s\n", strings.Repeat("x", 1024))
+				continue
+			}
+			fmt.Fprintf(w, "line %d user%d@example.com\n", i, i)
+		}
+		w.Flush()
+		input.Close()
+	}()
+
+	for i := 1; i < lines; i++ {
+		line, err := output.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading line %d: %v", i, err)
+		}
+		want := redactor.LineFailedPlaceholder + "\n"
+		if i != malformed {
+			want = fmt.Sprintf("line %d us%s\n", i, strings.Repeat("*", len(fmt.Sprintf("user%d@example.com", i))-2))
+		}
+		if line != want {
+			t.Fatalf("line %d = %q, want %q", i, line, want)
+		}
+	}
+	if rest, _ := io.ReadAll(output); len(rest) != 0 {
+		t.Errorf("unexpected output after the last line: %q", rest)
+	}
+	if got := resp.Trailer.Get(redactionErrorsTrailer); got != "1" {
+		t.Errorf("%s trailer = %q, want 1", redactionErrorsTrailer, got)
+	}
+}
+
+func TestStreamHandler_MethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+	streamHandler(redactor.NewRedactor(detector.NewEngine()), 0).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, streamPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+// patternAdminPath is the debug endpoint for enabling and disabling patterns
+// engine-wide, without a policy
+const patternAdminPath = "/debug/patterns"
+
+// maxPatternAdminBody bounds the size of a pattern toggle request
+const maxPatternAdminBody = 1 << 20
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// patternToggleRequest names the patterns to enable and disable. Patterns
+// listed in both are disabled.
+type patternToggleRequest struct {
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+}
+
+// patternToggleResponse reports the patterns that were enabled or disabled
+// and the requested names that match no pattern
+type patternToggleResponse struct {
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+	Missing  []string `json:"missing"`
+}
+
+// patternStateResponse lists the engine's enabled and disabled patterns
+type patternStateResponse struct {
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+}
+
+// patternAdminHandler serves the engine's pattern enablement on GET and
+// applies a patternToggleRequest on POST
+func patternAdminHandler(engine *detector.Engine) http.Handler {
+	logger := ctrl.Log.WithName("pattern-admin")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			enabled, disabled := engine.ListEnabledPatterns(), engine.ListDisabledPatterns()
+			sort.Strings(enabled)
+			sort.Strings(disabled)
+			writeJSON(w, http.StatusOK, patternStateResponse{Enabled: enabled, Disabled: disabled})
+
+		case http.MethodPost:
+			var req patternToggleRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxPatternAdminBody)).Decode(&req); err != nil {
+				http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			var resp patternToggleResponse
+			var missing []string
+			resp.Enabled, missing = engine.EnablePatterns(req.Enable)
+			resp.Missing = append(resp.Missing, missing...)
+			resp.Disabled, missing = engine.DisablePatterns(req.Disable)
+			resp.Missing = append(resp.Missing, missing...)
+
+			logger.Info("Patterns toggled",
+				"user", r.Header.Get(reviewedUserHeader),
+				"enabled", resp.Enabled,
+				"disabled", resp.Disabled,
+				"missing", resp.Missing,
+			)
+			writeJSON(w, http.StatusOK, resp)
+
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// checkAdminServing refuses to serve the pattern admin API from a metrics
+// endpoint without TLS, where callers' bearer tokens would travel in the clear
+func checkAdminServing(enablePatternAdmin, secureMetrics bool) error {
+	if enablePatternAdmin && !secureMetrics {
+		return fmt.Errorf("%s requires the metrics endpoint to serve https (-metrics-secure)", patternAdminPath)
+	}
+	return nil
+}
+
+// reviewedUserHeader carries the authenticated user name from requireAccess
+// to the wrapped handler
+const reviewedUserHeader = "X-Reviewed-User"
+
+// requireAccess serves requests to next only when the bearer token
+// authenticates with the API server and Kubernetes RBAC allows its user the
+// request's method as a verb on the non-resource URL path. Requests that did
+// not arrive over TLS are refused before their token is looked at.
+func requireAccess(c client.Client, path string, next http.Handler) http.Handler {
+	logger := ctrl.Log.WithName("pattern-admin")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Never trust a caller-supplied user header
+		r.Header.Del(reviewedUserHeader)
+
+		if r.TLS == nil {
+			http.Error(w, "https required", http.StatusForbidden)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+		if err := c.Create(r.Context(), review); err != nil {
+			logger.Error(err, "Failed to review token")
+			http.Error(w, "token review failed", http.StatusInternalServerError)
+			return
+		}
+		if !review.Status.Authenticated {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		user := review.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, value := range user.Extra {
+			extra[key] = authorizationv1.ExtraValue(value)
+		}
+		access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+				Path: path,
+				Verb: strings.ToLower(r.Method),
+			},
+		}}
+		if err := c.Create(r.Context(), access); err != nil {
+			logger.Error(err, "Failed to review access")
+			http.Error(w, "access review failed", http.StatusInternalServerError)
+			return
+		}
+		if !access.Status.Allowed {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		r.Header.Set(reviewedUserHeader, user.Username)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+// newReviewClient returns a client whose token reviews authenticate the
+// tokens in users, mapped to user names, and whose access reviews allow the
+// users in allowed the given verbs
+func newReviewClient(users map[string]string, allowed map[string][]string) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if name, ok := users[review.Spec.Token]; ok {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{Username: name}
+				}
+			case *authorizationv1.SubjectAccessReview:
+				attrs := review.Spec.NonResourceAttributes
+				for _, verb := range allowed[review.Spec.User] {
+					if attrs != nil && attrs.Path == patternAdminPath && attrs.Verb == verb {
+						review.Status.Allowed = true
+					}
+				}
+			}
+			return nil
+		},
+	}).Build()
+}
+
+func TestPatternAdmin(t *testing.T) {
+	reviews := newReviewClient(
+		map[string]string{"responder-token": "responder", "viewer-token": "viewer"},
+		map[string][]string{"responder": {"get", "post"}, "viewer": {"get"}},
+	)
+
+	tests := []struct {
+		name        string
+		method      string
+		token       string
+		plainHTTP   bool
+		body        string
+		wantStatus  int
+		wantToggled *patternToggleResponse
+	}{
+		{name: "no token", method: http.MethodGet, wantStatus: http.StatusUnauthorized},
+		{name: "plain http", method: http.MethodGet, token: "viewer-token", plainHTTP: true, wantStatus: http.StatusForbidden},
+		{name: "unknown token", method: http.MethodGet, token: "stolen", wantStatus: http.StatusUnauthorized},
+		{name: "viewer lists patterns", method: http.MethodGet, token: "viewer-token", wantStatus: http.StatusOK},
+		{name: "viewer cannot toggle", method: http.MethodPost, token: "viewer-token", body: `{"disable":["email"]}`, wantStatus: http.StatusForbidden},
+		{name: "responder sends invalid json", method: http.MethodPost, token: "responder-token", body: `{"disable":`, wantStatus: http.StatusBadRequest},
+		{
+			name:       "responder toggles mixed names",
+			method:     http.MethodPost,
+			token:      "responder-token",
+			body:       `{"enable":["ip-address","nope"],"disable":["email","typo"]}`,
+			wantStatus: http.StatusOK,
+			wantToggled: &patternToggleResponse{
+				Enabled:  []string{"ip-address"},
+				Disabled: []string{"email"},
+				Missing:  []string{"nope", "typo"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := detector.NewEngine()
+			handler := requireAccess(reviews, patternAdminPath, patternAdminHandler(engine))
+
+			req := httptest.NewRequest(tt.method, patternAdminPath, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if !tt.plainHTTP {
+				req.TLS = &tls.ConnectionState{}
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.method == http.MethodPost && tt.wantToggled == nil && !engine.IsPatternEnabled("email") {
+				t.Error("rejected request changed the engine")
+			}
+			if tt.wantToggled == nil {
+				return
+			}
+
+			var got patternToggleResponse
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if !reflect.DeepEqual(&got, tt.wantToggled) {
+				t.Errorf("response = %+v, want %+v", got, *tt.wantToggled)
+			}
+			if !engine.IsPatternEnabled("ip-address") || engine.IsPatternEnabled("email") {
+				t.Error("engine enablement does not match the response")
+			}
+		})
+	}
+}
+
+func TestCheckAdminServing(t *testing.T) {
+	tests := []struct {
+		name               string
+		enablePatternAdmin bool
+		secureMetrics      bool
+		wantErr            bool
+	}{
+		{name: "admin disabled", enablePatternAdmin: false, secureMetrics: false},
+		{name: "admin over https", enablePatternAdmin: true, secureMetrics: true},
+		{name: "admin over http", enablePatternAdmin: true, secureMetrics: false, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAdminServing(tt.enablePatternAdmin, tt.secureMetrics)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAdminServing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
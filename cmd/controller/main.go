@@ -1,23 +1,33 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
 	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
 	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/buildinfo"
 	"github.com/bunseokbot/pii-redactor/internal/controller"
 	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/health"
 	"github.com/bunseokbot/pii-redactor/internal/notifier"
 	"github.com/bunseokbot/pii-redactor/internal/policy"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
 	"github.com/bunseokbot/pii-redactor/internal/source"
 	"github.com/bunseokbot/pii-redactor/internal/subscription"
 )
@@ -27,6 +37,13 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// Build information, injected via -ldflags
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(piiv1alpha1.AddToScheme(scheme))
@@ -34,14 +51,70 @@ func init() {
 
 func main() {
 	var metricsAddr string
+	var secureMetrics bool
 	var enableLeaderElection bool
 	var probeAddr string
+	var showVersion bool
+	var readinessMode string
+	var maxConcurrentFetches int
+	var maxRevealRatio float64
+	var enableConfigScan bool
+	var configScanInterval time.Duration
+	var auditDestinations string
+	var redactDestinations string
+	var enableWebhooks bool
+	var auditBufferSize int
+	var shutdownGracePeriod time.Duration
+	var enablePatternAdmin bool
+	var maxPatternsPerSource int
+	var maxMatchesPerPattern int
+	var severityScale string
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
+	flag.BoolVar(&secureMetrics, "metrics-secure", false,
+		"Serve the metric endpoint over https, with a self-signed certificate unless one is provided in its cert directory.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.BoolVar(&showVersion, "version", false, "Print version information and exit.")
+	flag.StringVar(&readinessMode, "readiness-mode", string(health.ReadinessLenient),
+		"How community source failures affect readiness: strict (any failing source) "+
+			"or lenient (only when all sources fail).")
+	flag.IntVar(&maxConcurrentFetches, "max-concurrent-fetches", 4,
+		"Maximum number of community source fetches running at once (0 = unlimited).")
+	flag.IntVar(&maxPatternsPerSource, "max-patterns-per-source", source.DefaultMaxPatternsPerSource,
+		"Maximum number of patterns kept from each community source; the rest are dropped with a status warning (0 = unlimited).")
+	flag.IntVar(&maxMatchesPerPattern, "max-matches-per-pattern", 0,
+		"Maximum number of matches a pattern reports in one input; further matches are not redacted "+
+			"and are counted in pii_redactor_match_cap_hits_total (0 = unlimited).")
+	flag.StringVar(&severityScale, "severity-scale", "",
+		"Custom severity ranking as comma separated name=rank pairs, higher ranks more severe "+
+			"(e.g. sev1=10,sev2=7,sev3=3). Empty uses critical, high, medium and low.")
+	flag.Float64Var(&maxRevealRatio, "max-reveal-ratio", redactor.DefaultMaxRevealRatio,
+		"Largest share of a critical or high severity test value that pattern masking may reveal.")
+	flag.BoolVar(&enableConfigScan, "enable-config-scan", false,
+		"Allow policies to scan ConfigMap and Secret data for PII. "+
+			"Requires RBAC to list configmaps and secrets.")
+	flag.DurationVar(&configScanInterval, "config-scan-interval", controller.DefaultConfigScanInterval,
+		"How often policies that scan ConfigMap and Secret data rescan it. Findings are reported again only when their object changes.")
+	flag.StringVar(&auditDestinations, "audit-destinations", "",
+		"Comma-separated name=target audit sinks that policies can select with actions.audit.destination. "+
+			"A target is stdout, stderr or an absolute file path.")
+	flag.StringVar(&redactDestinations, "redact-destinations", "",
+		"Comma-separated name=target sinks for redacted logs that policies can select with actions.redact.destination. "+
+			"A target is stdout, stderr, an absolute file path or an http(s) URL.")
+	flag.IntVar(&auditBufferSize, "audit-buffer-size", 0,
+		"Number of audit entries buffered and written asynchronously (0 = write synchronously). "+
+			"Buffered entries are flushed on shutdown.")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", 10*time.Second,
+		"How long to wait after the manager stops for audit entries to be flushed and in-flight alerts to be sent.")
+	flag.BoolVar(&enablePatternAdmin, "enable-pattern-admin", false,
+		"Serve "+patternAdminPath+" on the metrics endpoint to enable and disable patterns engine-wide. "+
+			"Requires -metrics-secure. Callers need RBAC permission for the get and post verbs on the non-resource URL.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the PIIPattern validating admission webhook. "+
+			"Requires serving certificates in the webhook server's cert directory.")
 
 	opts := zap.Options{
 		Development: true,
@@ -49,12 +122,64 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	info := buildinfo.New(version, commit, date)
+	if showVersion {
+		fmt.Printf("pii-redactor controller %s\n", info)
+		return
+	}
+
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	setupLog.Info("build info", info.KeysAndValues()...)
+
+	mode, err := health.ParseReadinessMode(readinessMode)
+	if err != nil {
+		setupLog.Error(err, "invalid readiness mode")
+		os.Exit(1)
+	}
+
+	var severities patterns.SeverityScale
+	if severityScale != "" {
+		if severities, err = patterns.ParseSeverityScale(severityScale); err != nil {
+			setupLog.Error(err, "invalid severity scale")
+			os.Exit(1)
+		}
+	}
+
+	if err := checkAdminServing(enablePatternAdmin, secureMetrics); err != nil {
+		setupLog.Error(err, "unable to serve the pattern admin API")
+		os.Exit(1)
+	}
+
+	engine := detector.NewEngine()
+	engine.SetSeverityScale(severities)
+	engine.SetMaxMatchesPerPattern(maxMatchesPerPattern)
+	if err := registerEngineMetrics(ctrlmetrics.Registry, engine); err != nil {
+		setupLog.Error(err, "unable to register engine metrics")
+		os.Exit(1)
+	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	cfg := ctrl.GetConfigOrDie()
+	debugHandlers := map[string]http.Handler{
+		"/version":          info.Handler(),
+		"/debug/categories": categoryStatsHandler(engine),
+	}
+	if enablePatternAdmin {
+		// Token and access reviews are not cached, so a plain client serves
+		// them before the manager starts
+		reviewClient, err := client.New(cfg, client.Options{Scheme: scheme})
+		if err != nil {
+			setupLog.Error(err, "unable to create review client")
+			os.Exit(1)
+		}
+		debugHandlers[patternAdminPath] = requireAccess(reviewClient, patternAdminPath, patternAdminHandler(engine))
+	}
+
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
+			BindAddress:   metricsAddr,
+			SecureServing: secureMetrics,
+			ExtraHandlers: debugHandlers,
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -66,14 +191,34 @@ func main() {
 	}
 
 	// Create shared components
-	engine := detector.NewEngine()
 	notifierManager := notifier.NewManager()
-	auditLogger := audit.NewControllerRuntimeLogger()
+	notifierManager.SetSeverityScale(severities)
+	var auditLogger audit.AuditLogger = audit.NewControllerRuntimeLogger()
+	var bufferedAudit *audit.BufferedLogger
+	if auditBufferSize > 0 {
+		bufferedAudit = audit.NewBufferedLogger(auditLogger, auditBufferSize)
+		auditLogger = bufferedAudit
+	}
+	auditSinks := audit.NewDestinations(auditLogger)
+	if err := auditSinks.RegisterSpec(auditDestinations); err != nil {
+		setupLog.Error(err, "invalid audit destinations")
+		os.Exit(1)
+	}
+	redactSinks := policy.NewRedactDestinations()
+	if err := redactSinks.RegisterSpec(redactDestinations); err != nil {
+		setupLog.Error(err, "invalid redact destinations")
+		os.Exit(1)
+	}
 	sourceCache := source.NewCache()
+	sourceCache.SetMaxPatternsPerSource(maxPatternsPerSource)
 
 	// Create policy components
 	policyMatcher := policy.NewMatcher(mgr.GetClient())
 	policyAggregator := policy.NewAggregator(mgr.GetClient(), engine)
+	var configScanner *policy.ConfigScanner
+	if enableConfigScan {
+		configScanner = policy.NewConfigScanner(mgr.GetClient())
+	}
 
 	// Create subscription components
 	subscriptionManager := subscription.NewManager(sourceCache, engine)
@@ -81,13 +226,22 @@ func main() {
 
 	// Setup PIIPattern controller
 	if err = (&controller.PIIPatternReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Engine: engine,
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Engine:         engine,
+		MaxRevealRatio: maxRevealRatio,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PIIPattern")
 		os.Exit(1)
 	}
+	if enableWebhooks {
+		if err = (&controller.PIIPatternValidator{
+			MaxRevealRatio: maxRevealRatio,
+		}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "PIIPattern")
+			os.Exit(1)
+		}
+	}
 
 	// Setup PIIAlertChannel controller
 	if err = (&controller.PIIAlertChannelReconciler{
@@ -101,13 +255,17 @@ func main() {
 
 	// Setup PIIPolicy controller
 	if err = (&controller.PIIPolicyReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		Engine:          engine,
-		NotifierManager: notifierManager,
-		AuditLogger:     auditLogger,
-		Matcher:         policyMatcher,
-		Aggregator:      policyAggregator,
+		Client:             mgr.GetClient(),
+		Scheme:             mgr.GetScheme(),
+		Engine:             engine,
+		NotifierManager:    notifierManager,
+		AuditLogger:        auditLogger,
+		Matcher:            policyMatcher,
+		Aggregator:         policyAggregator,
+		ConfigScanner:      configScanner,
+		ConfigScanInterval: configScanInterval,
+		AuditDestinations:  auditSinks,
+		RedactDestinations: redactSinks,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PIIPolicy")
 		os.Exit(1)
@@ -115,9 +273,10 @@ func main() {
 
 	// Setup PIICommunitySource controller
 	if err = (&controller.PIICommunitySourceReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Cache:  sourceCache,
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Cache:        sourceCache,
+		FetchLimiter: source.NewFetchLimiter(maxConcurrentFetches),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PIICommunitySource")
 		os.Exit(1)
@@ -141,14 +300,39 @@ func main() {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+	readiness := health.NewReadinessChecker(engine, sourceCache, mode)
+	if err := mgr.AddReadyzCheck("readyz", readiness.Check); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctrl.SetupSignalHandler())
+
+	// The manager has stopped its controllers; let the alerts they started
+	// finish, then flush audit entries, which sending alerts may still add
+	steps := []shutdownStep{{name: "notifiers", run: notifierManager.Shutdown}}
+	if bufferedAudit != nil {
+		steps = append(steps, shutdownStep{name: "audit buffer", run: bufferedAudit.Shutdown})
+	}
+	steps = append(steps,
+		closeStep("audit destinations", auditSinks),
+		closeStep("redact destinations", redactSinks),
+	)
+	setupLog.Info("shutting down", "gracePeriod", shutdownGracePeriod)
+	if shutdownErr := runShutdown(shutdownGracePeriod, steps...); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "incomplete shutdown")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
+
+// categoryStatsHandler serves the engine's pattern counts per category as JSON
+func categoryStatsHandler(engine *detector.Engine) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(engine.CategoryStats())
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+// registerEngineMetrics exposes the engine's drop counters on the metrics
+// endpoint, so the blind spots left by performance limits can be watched
+func registerEngineMetrics(registry prometheus.Registerer, engine *detector.Engine) error {
+	counters := []struct {
+		name string
+		help string
+		read func(detector.Stats) int64
+	}{
+		{
+			name: "pii_redactor_dropped_events_total",
+			help: "Detections not published because the event sink was full.",
+			read: func(s detector.Stats) int64 { return s.DroppedEvents },
+		},
+		{
+			name: "pii_redactor_size_skipped_inputs_total",
+			help: "Inputs not scanned because they exceeded the size limit.",
+			read: func(s detector.Stats) int64 { return s.SizeSkipped },
+		},
+		{
+			name: "pii_redactor_size_truncated_inputs_total",
+			help: "Inputs of which only a prefix was scanned because they exceeded the size limit.",
+			read: func(s detector.Stats) int64 { return s.SizeTruncated },
+		},
+		{
+			name: "pii_redactor_sampled_out_inputs_total",
+			help: "Inputs whose detections were not processed because sampling left them out.",
+			read: func(s detector.Stats) int64 { return s.SampledOut },
+		},
+		{
+			name: "pii_redactor_match_cap_hits_total",
+			help: "Times a pattern stopped reporting matches in an input at the match cap.",
+			read: func(s detector.Stats) int64 { return s.MatchCapHit },
+		},
+	}
+
+	for _, c := range counters {
+		read := c.read
+		collector := prometheus.NewCounterFunc(prometheus.CounterOpts{Name: c.name, Help: c.help}, func() float64 {
+			return float64(read(engine.Stats()))
+		})
+		if err := registry.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
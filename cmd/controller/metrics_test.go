@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+func TestRegisterEngineMetrics(t *testing.T) {
+	engine := detector.NewEngine()
+	registry := prometheus.NewRegistry()
+	if err := registerEngineMetrics(registry, engine); err != nil {
+		t.Fatalf("registerEngineMetrics() error = %v", err)
+	}
+
+	engine.RecordOversizedInput(true)
+	engine.RecordOversizedInput(false)
+	engine.RecordOversizedInput(false)
+	engine.RecordSampledOut()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		got[family.GetName()] = family.GetMetric()[0].GetCounter().GetValue()
+	}
+
+	want := map[string]float64{
+		"pii_redactor_dropped_events_total":        0,
+		"pii_redactor_size_skipped_inputs_total":   1,
+		"pii_redactor_size_truncated_inputs_total": 2,
+		"pii_redactor_sampled_out_inputs_total":    1,
+		"pii_redactor_match_cap_hits_total":        0,
+	}
+	for name, value := range want {
+		if v, ok := got[name]; !ok || v != value {
+			t.Errorf("%s = %v (exported %v), want %v", name, v, ok, value)
+		}
+	}
+
+	// Registering twice is rejected rather than silently duplicated
+	if err := registerEngineMetrics(registry, engine); err == nil {
+		t.Error("expected an error registering the metrics twice")
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// shutdownStep is a cleanup run once the manager has stopped
+type shutdownStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// closeStep adapts a Close method to a shutdown step
+func closeStep(name string, closer interface{ Close() error }) shutdownStep {
+	return shutdownStep{name: name, run: func(context.Context) error { return closer.Close() }}
+}
+
+// runShutdown runs the steps in order within a shared grace period and
+// returns their combined errors. The steps after one that fails or runs out
+// of time still run, so that one stuck sink does not keep the others from
+// being flushed.
+func runShutdown(grace time.Duration, steps ...shutdownStep) error {
+	ctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+
+	var errs []error
+	for _, step := range steps {
+		if err := step.run(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/notifier"
+)
+
+// slowAuditLogger records entries after a delay, standing in for a slow sink
+type slowAuditLogger struct {
+	mu      sync.Mutex
+	entries []*audit.AuditEntry
+	closed  bool
+}
+
+func (l *slowAuditLogger) Log(_ context.Context, entry *audit.AuditEntry) error {
+	time.Sleep(5 * time.Millisecond)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+func (l *slowAuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	return nil
+}
+
+// slowNotifier delivers alerts after a delay
+type slowNotifier struct {
+	mu        sync.Mutex
+	delivered []string
+	started   chan struct{}
+}
+
+func (n *slowNotifier) Send(_ context.Context, alert *notifier.Alert) error {
+	close(n.started)
+	time.Sleep(50 * time.Millisecond)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.delivered = append(n.delivered, alert.ID)
+	return nil
+}
+
+func (n *slowNotifier) Type() string    { return "slow" }
+func (n *slowNotifier) Validate() error { return nil }
+
+func TestRunShutdown_FlushesBufferedWork(t *testing.T) {
+	sink := &slowAuditLogger{}
+	buffered := audit.NewBufferedLogger(sink, 100)
+	for i := 0; i < 20; i++ {
+		if err := buffered.Log(context.Background(), audit.NewAuditEntry(audit.EventTypePIIDetected, "default", "policy", "email")); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	manager := notifier.NewManager()
+	n := &slowNotifier{started: make(chan struct{})}
+	if err := manager.Register("slack", n, notifier.NotifierConfig{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	go manager.SendAlert(context.Background(), "slack", &notifier.Alert{ID: "in-flight"})
+	<-n.started
+
+	err := runShutdown(5*time.Second,
+		shutdownStep{name: "notifiers", run: manager.Shutdown},
+		shutdownStep{name: "audit buffer", run: buffered.Shutdown},
+	)
+	if err != nil {
+		t.Fatalf("runShutdown() error = %v", err)
+	}
+
+	if len(sink.entries) != 20 || !sink.closed {
+		t.Errorf("audit sink has %d entries, closed %v; want 20 entries and closed", len(sink.entries), sink.closed)
+	}
+	if len(n.delivered) != 1 {
+		t.Errorf("delivered %v, want the in-flight alert", n.delivered)
+	}
+}
+
+func TestRunShutdown_GracePeriod(t *testing.T) {
+	stuck := shutdownStep{name: "stuck", run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	var ran bool
+	after := shutdownStep{name: "after", run: func(context.Context) error {
+		ran = true
+		return nil
+	}}
+
+	start := time.Now()
+	err := runShutdown(20*time.Millisecond, stuck, after)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("runShutdown() took %v, want it bounded by the grace period", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("runShutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !ran {
+		t.Error("step after a stuck step did not run")
+	}
+}
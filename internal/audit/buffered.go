@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ErrLoggerClosed is returned when logging to a closed BufferedLogger
+var ErrLoggerClosed = errors.New("audit logger is closed")
+
+// ErrBufferFull is returned when a BufferedLogger's buffer has no room for an entry
+var ErrBufferFull = errors.New("audit buffer is full")
+
+// bufferedEntry is an entry queued with the context it was logged with
+type bufferedEntry struct {
+	ctx   context.Context
+	entry *AuditEntry
+}
+
+// BufferedLogger logs audit entries asynchronously. Entries are queued and
+// written to the underlying logger by a background goroutine, so Log does
+// not block on slow sinks. Flush waits for the queued entries to be
+// written; Close flushes before closing the underlying logger.
+type BufferedLogger struct {
+	logger AuditLogger
+	queue  chan bufferedEntry
+
+	mu      sync.RWMutex
+	closed  bool
+	pending sync.WaitGroup
+	done    chan struct{}
+}
+
+// NewBufferedLogger creates a BufferedLogger holding up to size entries
+// in front of logger
+func NewBufferedLogger(logger AuditLogger, size int) *BufferedLogger {
+	b := &BufferedLogger{
+		logger: logger,
+		queue:  make(chan bufferedEntry, max(size, 1)),
+		done:   make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// run writes queued entries to the underlying logger until the queue is closed
+func (b *BufferedLogger) run() {
+	defer close(b.done)
+	for queued := range b.queue {
+		if err := b.logger.Log(queued.ctx, queued.entry); err != nil {
+			log.FromContext(queued.ctx).Error(err, "Failed to write buffered audit entry")
+		}
+		b.pending.Done()
+	}
+}
+
+// Log queues an audit entry. It fails rather than blocks when the buffer is full.
+func (b *BufferedLogger) Log(ctx context.Context, entry *AuditEntry) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrLoggerClosed
+	}
+
+	// The entry is written after Log returns, so detach it from the
+	// caller's cancellation while keeping its values
+	b.pending.Add(1)
+	select {
+	case b.queue <- bufferedEntry{ctx: context.WithoutCancel(ctx), entry: entry}:
+		return nil
+	default:
+		b.pending.Done()
+		return ErrBufferFull
+	}
+}
+
+// Flush waits until every entry queued so far has been written, or the
+// context is done
+func (b *BufferedLogger) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	go func() {
+		b.pending.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("audit flush interrupted with %d entries buffered: %w", len(b.queue), ctx.Err())
+	}
+}
+
+// Shutdown stops accepting entries, writes the buffered ones and closes the
+// underlying logger. If the context is done first, the remaining entries
+// are abandoned and the underlying logger is left open for them.
+func (b *BufferedLogger) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return b.logger.Close()
+	case <-ctx.Done():
+		return fmt.Errorf("audit shutdown interrupted with %d entries buffered: %w", len(b.queue), ctx.Err())
+	}
+}
+
+// Close writes the buffered entries and closes the underlying logger
+func (b *BufferedLogger) Close() error {
+	return b.Shutdown(context.Background())
+}
@@ -0,0 +1,57 @@
+package audit
+
+import "context"
+
+// ContextFields are correlation values carried in a context and copied onto
+// every audit entry logged with that context
+type ContextFields struct {
+	// TraceID identifies the distributed trace
+	TraceID string
+
+	// RequestID identifies the request being handled
+	RequestID string
+
+	// User identifies the user on whose behalf the request is handled
+	User string
+}
+
+// contextFieldsKey is the context key for ContextFields
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a context carrying the given fields. Empty fields
+// keep the values already set by an enclosing context.
+func ContextWithFields(ctx context.Context, fields ContextFields) context.Context {
+	merged := FieldsFromContext(ctx)
+	if fields.TraceID != "" {
+		merged.TraceID = fields.TraceID
+	}
+	if fields.RequestID != "" {
+		merged.RequestID = fields.RequestID
+	}
+	if fields.User != "" {
+		merged.User = fields.User
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields carried by the context
+func FieldsFromContext(ctx context.Context) ContextFields {
+	fields, _ := ctx.Value(contextFieldsKey{}).(ContextFields)
+	return fields
+}
+
+// WithContextFields copies the context fields onto the entry, keeping any
+// value the entry already has
+func (e *AuditEntry) WithContextFields(ctx context.Context) *AuditEntry {
+	fields := FieldsFromContext(ctx)
+	if e.TraceID == "" {
+		e.TraceID = fields.TraceID
+	}
+	if e.RequestID == "" {
+		e.RequestID = fields.RequestID
+	}
+	if e.User == "" {
+		e.User = fields.User
+	}
+	return e
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Destinations resolves the audit destination named by a policy to the
+// logger of a sink configured by the operator. Policies can only select
+// configured sinks, never arbitrary files.
+type Destinations struct {
+	mu       sync.RWMutex
+	fallback AuditLogger
+	sinks    map[string]AuditLogger
+}
+
+// NewDestinations creates a new Destinations that resolves an empty
+// destination to the fallback logger
+func NewDestinations(fallback AuditLogger) *Destinations {
+	return &Destinations{
+		fallback: fallback,
+		sinks:    make(map[string]AuditLogger),
+	}
+}
+
+// Register configures a named sink. The target is "stdout", "stderr", or a
+// file given as an absolute path or file:// URL that entries are appended
+// to as JSON lines. Registering a name again replaces and closes its sink.
+func (d *Destinations) Register(name, target string) error {
+	if name == "" {
+		return fmt.Errorf("audit destination name is empty")
+	}
+
+	logger, err := newSinkLogger(target)
+	if err != nil {
+		return fmt.Errorf("audit destination %s: %w", name, err)
+	}
+
+	d.mu.Lock()
+	previous := d.sinks[name]
+	d.sinks[name] = logger
+	d.mu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// RegisterSpec registers the sinks of a comma-separated list of
+// name=target pairs, e.g. "team-a=/var/log/audit/team-a.jsonl,ops=stdout"
+func (d *Destinations) RegisterSpec(spec string) error {
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, target, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid audit destination %q: expected name=target", pair)
+		}
+		if err := d.Register(strings.TrimSpace(name), strings.TrimSpace(target)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve returns the logger for a destination name. An empty name
+// resolves to the fallback logger.
+func (d *Destinations) Resolve(name string) (AuditLogger, error) {
+	if name == "" {
+		return d.fallback, nil
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	logger, ok := d.sinks[name]
+	if !ok {
+		return nil, fmt.Errorf("audit destination %s is not configured", name)
+	}
+	return logger, nil
+}
+
+// Close closes all sinks. The fallback logger is left open.
+func (d *Destinations) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs []error
+	for name, logger := range d.sinks {
+		if err := logger.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(d.sinks, name)
+	}
+	return errors.Join(errs...)
+}
+
+// newSinkLogger creates the logger writing to a sink target
+func newSinkLogger(target string) (AuditLogger, error) {
+	switch target {
+	case "stdout":
+		return &JSONLogger{writer: os.Stdout}, nil
+	case "stderr":
+		return &JSONLogger{writer: os.Stderr}, nil
+	}
+
+	path := strings.TrimPrefix(target, "file://")
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("unsupported target %q: use stdout, stderr or an absolute file path", target)
+	}
+	return NewJSONFileLogger(path)
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDestinations_Resolve(t *testing.T) {
+	var fallback bytes.Buffer
+	destinations := NewDestinations(NewJSONLogger(&fallback))
+	defer destinations.Close()
+
+	path := filepath.Join(t.TempDir(), "team-a.jsonl")
+	if err := destinations.RegisterSpec("team-a=file://" + path); err != nil {
+		t.Fatalf("RegisterSpec() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, name := range []string{"team-a", ""} {
+		logger, err := destinations.Resolve(name)
+		if err != nil {
+			t.Fatalf("Resolve(%q) error = %v", name, err)
+		}
+		entry := NewAuditEntry(EventTypePIIDetected, "default", "policy-"+name, "email")
+		if err := logger.Log(ctx, entry); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "policy-team-a") || strings.Contains(string(data), `"policy-"`) {
+		t.Errorf("sink file = %s, want only the team-a entry", data)
+	}
+	if strings.Contains(fallback.String(), "policy-team-a") || !strings.Contains(fallback.String(), `"policy-"`) {
+		t.Errorf("fallback = %s, want only the default entry", fallback.String())
+	}
+
+	if _, err := destinations.Resolve("team-b"); err == nil {
+		t.Error("Resolve() should fail for an unconfigured destination")
+	}
+}
+
+func TestDestinations_RegisterSpecInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{name: "missing target", spec: "team-a"},
+		{name: "relative path", spec: "team-a=audit.jsonl"},
+		{name: "unsupported scheme", spec: "team-a=syslog://localhost:514"},
+		{name: "empty name", spec: "=stdout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			destinations := NewDestinations(NewNoOpLogger())
+			if err := destinations.RegisterSpec(tt.spec); err == nil {
+				t.Errorf("RegisterSpec(%q) should fail", tt.spec)
+			}
+		})
+	}
+}
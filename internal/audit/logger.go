@@ -3,6 +3,7 @@ package audit
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -65,6 +66,12 @@ type AuditEntry struct {
 	// Source identifies where the PII was detected
 	Source string `json:"source,omitempty"`
 
+	// TraceID, RequestID and User correlate the entry with the request that
+	// produced it. Loggers fill them from the context's ContextFields.
+	TraceID   string `json:"traceId,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	User      string `json:"user,omitempty"`
+
 	// Labels contains additional metadata
 	Labels map[string]string `json:"labels,omitempty"`
 }
@@ -73,6 +80,7 @@ type AuditEntry struct {
 const (
 	EventTypePIIDetected = "pii.detected"
 	EventTypePIIRedacted = "pii.redacted"
+	EventTypePIIBlocked  = "pii.blocked"
 	EventTypeAlertSent   = "alert.sent"
 	EventTypePolicyMatch = "policy.match"
 )
@@ -185,10 +193,14 @@ func NewJSONFileLogger(path string) (*JSONLogger, error) {
 
 // Log logs an audit entry
 func (l *JSONLogger) Log(ctx context.Context, entry *AuditEntry) error {
+	// Enrich a copy, as the entry may be shared with other loggers
+	enriched := *entry
+	enriched.WithContextFields(ctx)
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	data, err := json.Marshal(entry)
+	data, err := json.Marshal(&enriched)
 	if err != nil {
 		return fmt.Errorf("failed to marshal audit entry: %w", err)
 	}
@@ -222,6 +234,8 @@ func NewControllerRuntimeLogger() *ControllerRuntimeLogger {
 // Log logs an audit entry
 func (l *ControllerRuntimeLogger) Log(ctx context.Context, entry *AuditEntry) error {
 	logger := log.FromContext(ctx)
+	enriched := *entry
+	enriched.WithContextFields(ctx)
 
 	logger.Info("audit",
 		"eventType", entry.EventType,
@@ -234,6 +248,9 @@ func (l *ControllerRuntimeLogger) Log(ctx context.Context, entry *AuditEntry) er
 		"action", entry.Action,
 		"matchCount", entry.MatchCount,
 		"source", entry.Source,
+		"traceId", enriched.TraceID,
+		"requestId", enriched.RequestID,
+		"user", enriched.User,
 	)
 
 	return nil
@@ -246,41 +263,109 @@ func (l *ControllerRuntimeLogger) Close() error {
 
 // MultiLogger logs to multiple loggers
 type MultiLogger struct {
-	loggers []AuditLogger
+	loggers    []AuditLogger
+	required   []bool
+	concurrent bool
+	mu         sync.RWMutex
 }
 
 // NewMultiLogger creates a new multi-logger
 func NewMultiLogger(loggers ...AuditLogger) *MultiLogger {
 	return &MultiLogger{
-		loggers: loggers,
+		loggers:  loggers,
+		required: make([]bool, len(loggers)),
 	}
 }
 
 // AddLogger adds a logger
 func (m *MultiLogger) AddLogger(logger AuditLogger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loggers = append(m.loggers, logger)
+	m.required = append(m.required, false)
+}
+
+// AddRequiredLogger adds a logger whose failure always fails the call.
+// Once a required logger is present, failures of the other loggers are
+// best-effort: they are logged but not returned.
+func (m *MultiLogger) AddRequiredLogger(logger AuditLogger) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.loggers = append(m.loggers, logger)
+	m.required = append(m.required, true)
 }
 
-// Log logs to all loggers
+// SetConcurrent enables or disables concurrent fan-out to the loggers, so
+// that a slow logger does not delay the others
+func (m *MultiLogger) SetConcurrent(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.concurrent = enabled
+}
+
+// Log logs to all loggers and returns the combined errors
 func (m *MultiLogger) Log(ctx context.Context, entry *AuditEntry) error {
-	var lastErr error
-	for _, logger := range m.loggers {
-		if err := logger.Log(ctx, entry); err != nil {
-			lastErr = err
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	errs := make([]error, len(m.loggers))
+	if m.concurrent {
+		var wg sync.WaitGroup
+		for i, logger := range m.loggers {
+			wg.Add(1)
+			go func(i int, logger AuditLogger) {
+				defer wg.Done()
+				errs[i] = logger.Log(ctx, entry)
+			}(i, logger)
+		}
+		wg.Wait()
+	} else {
+		for i, logger := range m.loggers {
+			errs[i] = logger.Log(ctx, entry)
+		}
+	}
+
+	return m.combineErrors(ctx, errs)
+}
+
+// combineErrors joins logger errors, dropping best-effort failures when a
+// required logger is configured
+func (m *MultiLogger) combineErrors(ctx context.Context, errs []error) error {
+	hasRequired := false
+	for _, required := range m.required {
+		if required {
+			hasRequired = true
+			break
+		}
+	}
+
+	var failed []error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if hasRequired && !m.required[i] {
+			log.FromContext(ctx).Error(err, "Best-effort audit logger failed")
+			continue
 		}
+		failed = append(failed, err)
 	}
-	return lastErr
+
+	return errors.Join(failed...)
 }
 
-// Close closes all loggers
+// Close closes all loggers and returns the combined errors
 func (m *MultiLogger) Close() error {
-	var lastErr error
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var errs []error
 	for _, logger := range m.loggers {
 		if err := logger.Close(); err != nil {
-			lastErr = err
+			errs = append(errs, err)
 		}
 	}
-	return lastErr
+	return errors.Join(errs...)
 }
 
 // NoOpLogger is a logger that does nothing (for testing or disabled audit)
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewAuditEntry(t *testing.T) {
@@ -97,6 +100,65 @@ func TestJSONLogger_Log(t *testing.T) {
 	}
 }
 
+func TestJSONLogger_LogContextFields(t *testing.T) {
+	ctx := ContextWithFields(context.Background(), ContextFields{TraceID: "trace-1", User: "alice"})
+	ctx = ContextWithFields(ctx, ContextFields{RequestID: "req-42"})
+
+	tests := []struct {
+		name          string
+		entry         *AuditEntry
+		wantTraceID   string
+		wantRequestID string
+		wantUser      string
+	}{
+		{
+			name:          "filled from context",
+			entry:         NewAuditEntry(EventTypePIIDetected, "default", "test-policy", "email"),
+			wantTraceID:   "trace-1",
+			wantRequestID: "req-42",
+			wantUser:      "alice",
+		},
+		{
+			name:          "entry value wins",
+			entry:         &AuditEntry{EventType: EventTypePIIDetected, TraceID: "trace-explicit"},
+			wantTraceID:   "trace-explicit",
+			wantRequestID: "req-42",
+			wantUser:      "alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewJSONLogger(&buf).Log(ctx, tt.entry); err != nil {
+				t.Fatalf("Log() error = %v", err)
+			}
+
+			var logged AuditEntry
+			if err := json.Unmarshal(buf.Bytes(), &logged); err != nil {
+				t.Fatalf("Failed to unmarshal logged entry: %v", err)
+			}
+			if logged.TraceID != tt.wantTraceID || logged.RequestID != tt.wantRequestID || logged.User != tt.wantUser {
+				t.Errorf("logged context = %q/%q/%q, want %q/%q/%q",
+					logged.TraceID, logged.RequestID, logged.User, tt.wantTraceID, tt.wantRequestID, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestJSONLogger_LogDoesNotModifyEntry(t *testing.T) {
+	ctx := ContextWithFields(context.Background(), ContextFields{TraceID: "trace-1"})
+	entry := NewAuditEntry(EventTypePIIDetected, "default", "test-policy", "email")
+
+	var buf bytes.Buffer
+	if err := NewJSONLogger(&buf).Log(ctx, entry); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+	if entry.TraceID != "" {
+		t.Errorf("entry.TraceID = %q, want the caller's entry left unchanged", entry.TraceID)
+	}
+}
+
 func TestJSONLogger_Close(t *testing.T) {
 	var buf bytes.Buffer
 	logger := NewJSONLogger(&buf)
@@ -170,6 +232,113 @@ func TestMultiLogger_AddLogger(t *testing.T) {
 	}
 }
 
+// stubLogger is an AuditLogger with configurable delay and error
+type stubLogger struct {
+	delay  time.Duration
+	err    error
+	mu     sync.Mutex
+	logged int
+}
+
+func (l *stubLogger) Log(ctx context.Context, entry *AuditEntry) error {
+	time.Sleep(l.delay)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.logged++
+	return l.err
+}
+
+func (l *stubLogger) Close() error {
+	return l.err
+}
+
+func (l *stubLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.logged
+}
+
+func TestMultiLogger_ConcurrentCombinedError(t *testing.T) {
+	errFirst := errors.New("first sink down")
+	errSecond := errors.New("second sink down")
+
+	slow := &stubLogger{delay: 200 * time.Millisecond}
+	failing1 := &stubLogger{err: errFirst}
+	failing2 := &stubLogger{err: errSecond}
+	var buf bytes.Buffer
+	fast := NewJSONLogger(&buf)
+
+	multi := NewMultiLogger(slow, failing1, fast, failing2)
+	multi.SetConcurrent(true)
+
+	entry := NewAuditEntry(EventTypePIIDetected, "default", "test-policy", "email")
+
+	start := time.Now()
+	err := multi.Log(context.Background(), entry)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Errorf("Log() error = %v, want both sub-logger errors", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("fast logger should have received the entry")
+	}
+	if slow.count() != 1 {
+		t.Errorf("slow logger received %d entries, want 1", slow.count())
+	}
+	if elapsed > time.Second {
+		t.Errorf("Log() took %v, expected loggers to run concurrently", elapsed)
+	}
+}
+
+func TestMultiLogger_RequiredLogger(t *testing.T) {
+	errBestEffort := errors.New("best-effort sink down")
+	errRequired := errors.New("required sink down")
+
+	tests := []struct {
+		name     string
+		required *stubLogger
+		others   []AuditLogger
+		wantErr  error
+	}{
+		{
+			name:     "best-effort failure is ignored",
+			required: &stubLogger{},
+			others:   []AuditLogger{&stubLogger{err: errBestEffort}},
+			wantErr:  nil,
+		},
+		{
+			name:     "required failure fails the call",
+			required: &stubLogger{err: errRequired},
+			others:   []AuditLogger{&stubLogger{err: errBestEffort}},
+			wantErr:  errRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multi := NewMultiLogger(tt.others...)
+			multi.AddRequiredLogger(tt.required)
+
+			entry := NewAuditEntry(EventTypePIIDetected, "default", "test-policy", "email")
+			err := multi.Log(context.Background(), entry)
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Log() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Log() error = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, errBestEffort) {
+				t.Errorf("Log() error = %v, should not include best-effort failure", err)
+			}
+		})
+	}
+}
+
 func TestNoOpLogger_Log(t *testing.T) {
 	logger := NewNoOpLogger()
 
@@ -220,3 +389,81 @@ func TestActionConstants(t *testing.T) {
 		t.Error("ActionBlock should not be empty")
 	}
 }
+
+func TestBufferedLogger_Shutdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		delay       time.Duration
+		timeout     time.Duration
+		wantErr     bool
+		wantFlushed bool
+	}{
+		{name: "flushes buffered entries", delay: 5 * time.Millisecond, timeout: 5 * time.Second, wantFlushed: true},
+		{name: "gives up after the grace period", delay: 200 * time.Millisecond, timeout: 20 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &stubLogger{delay: tt.delay}
+			logger := NewBufferedLogger(inner, 10)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			for i := 0; i < 5; i++ {
+				if err := logger.Log(ctx, NewAuditEntry(EventTypePIIDetected, "default", "policy", "email")); err != nil {
+					t.Fatalf("Log() error = %v", err)
+				}
+			}
+			// Entries outlive the request that logged them
+			cancel()
+
+			shutdownCtx, stop := context.WithTimeout(context.Background(), tt.timeout)
+			defer stop()
+			err := logger.Shutdown(shutdownCtx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Shutdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantFlushed && inner.count() != 5 {
+				t.Errorf("flushed %d entries, want 5", inner.count())
+			}
+
+			if err := logger.Log(context.Background(), NewAuditEntry(EventTypePIIDetected, "default", "policy", "email")); !errors.Is(err, ErrLoggerClosed) {
+				t.Errorf("Log() after shutdown error = %v, want %v", err, ErrLoggerClosed)
+			}
+		})
+	}
+}
+
+func TestBufferedLogger_BufferFull(t *testing.T) {
+	release := make(chan struct{})
+	inner := &blockingLogger{release: release}
+	logger := NewBufferedLogger(inner, 1)
+	defer logger.Close()
+	defer close(release)
+
+	ctx := context.Background()
+	entry := NewAuditEntry(EventTypePIIDetected, "default", "policy", "email")
+
+	// One entry is being written and one fills the buffer
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = logger.Log(ctx, entry)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !errors.Is(err, ErrBufferFull) {
+		t.Fatalf("Log() error = %v, want %v", err, ErrBufferFull)
+	}
+}
+
+// blockingLogger is an AuditLogger whose Log blocks until released
+type blockingLogger struct {
+	release chan struct{}
+}
+
+func (l *blockingLogger) Log(ctx context.Context, entry *AuditEntry) error {
+	<-l.release
+	return nil
+}
+
+func (l *blockingLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/detector/validator"
+)
+
+// Info describes a build of a pii-redactor binary
+type Info struct {
+	// Version is the release version injected at build time
+	Version string `json:"version"`
+
+	// Commit is the git commit injected at build time
+	Commit string `json:"commit"`
+
+	// Date is the build date injected at build time
+	Date string `json:"date"`
+
+	// BuiltInPatterns is the number of built-in patterns compiled in
+	BuiltInPatterns int `json:"builtInPatterns"`
+
+	// Validators is the number of registered validators
+	Validators int `json:"validators"`
+}
+
+// New creates build info from the values injected via -ldflags
+func New(version, commit, date string) Info {
+	return Info{
+		Version:         version,
+		Commit:          commit,
+		Date:            date,
+		BuiltInPatterns: len(patterns.BuiltInPatterns),
+		Validators:      len(validator.List()),
+	}
+}
+
+// String formats the build info for -version output
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit: %s, built: %s, built-in patterns: %d, validators: %d)",
+		i.Version, i.Commit, i.Date, i.BuiltInPatterns, i.Validators)
+}
+
+// KeysAndValues returns the build info as structured logging key/value pairs
+func (i Info) KeysAndValues() []interface{} {
+	return []interface{}{
+		"version", i.Version,
+		"commit", i.Commit,
+		"date", i.Date,
+		"builtInPatterns", i.BuiltInPatterns,
+		"validators", i.Validators,
+	}
+}
+
+// Handler returns an HTTP handler serving the build info as JSON
+func (i Info) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(i)
+	})
+}
@@ -0,0 +1,63 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/detector/validator"
+)
+
+func TestInfo_String(t *testing.T) {
+	info := New("v1.2.3", "abc1234", "2024-05-01T10:00:00Z")
+
+	want := fmt.Sprintf("v1.2.3 (commit: abc1234, built: 2024-05-01T10:00:00Z, built-in patterns: %d, validators: %d)",
+		len(patterns.BuiltInPatterns), len(validator.List()))
+	if got := info.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// alwaysValid is a validator accepting every input
+type alwaysValid struct{}
+
+func (alwaysValid) Validate(string) bool { return true }
+
+func TestNew_WhileRegistering(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			New("v1.2.3", "abc1234", "2024-05-01T10:00:00Z")
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("test-buildinfo-%d", i)
+		validator.Register(name, alwaysValid{})
+		t.Cleanup(func() { delete(validator.Registry, name) })
+	}
+	<-done
+}
+
+func TestInfo_Handler(t *testing.T) {
+	info := New("v1.2.3", "abc1234", "2024-05-01T10:00:00Z")
+
+	rec := httptest.NewRecorder()
+	info.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var got Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got != info {
+		t.Errorf("response = %+v, want %+v", got, info)
+	}
+}
@@ -3,13 +3,18 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
 	"github.com/bunseokbot/pii-redactor/internal/notifier"
@@ -106,10 +111,17 @@ func (r *PIIAlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, nil
 	}
 
+	// Send the test alert once per ID
+	if test := channel.Spec.TestAlert; test != nil && (channel.Status.LastTestAlert == nil || channel.Status.LastTestAlert.ID != test.ID) {
+		channel.Status.LastTestAlert = sendTestAlert(ctx, n, &channel)
+		logger.Info("Sent test alert", "id", test.ID, "delivered", channel.Status.LastTestAlert.Delivered)
+	}
+
 	// Update status to ready
 	channel.Status.Ready = true
 	channel.Status.LastError = ""
 	r.setCondition(&channel, "Ready", metav1.ConditionTrue, "Configured", "Channel is configured and ready")
+	breakerOpen := r.setBreakerStatus(&channel, req.String())
 
 	if err := r.Status().Update(ctx, &channel); err != nil {
 		logger.Error(err, "Failed to update PIIAlertChannel status")
@@ -117,9 +129,52 @@ func (r *PIIAlertChannelReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	logger.Info("PIIAlertChannel reconciled successfully", "name", channel.Name)
+	if breakerOpen {
+		// Breakers change state between reconciles; keep the status current
+		// until the channel recovers
+		return ctrl.Result{RequeueAfter: breakerStatusInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// breakerStatusInterval is how often the status of a channel whose circuit
+// breaker is not closed is refreshed
+const breakerStatusInterval = 30 * time.Second
+
+// setBreakerStatus records the state of the channel's circuit breaker in its
+// status and Degraded condition. It reports whether the breaker is open or
+// half-open.
+func (r *PIIAlertChannelReconciler) setBreakerStatus(channel *piiv1alpha1.PIIAlertChannel, name string) bool {
+	stats, ok := r.NotifierManager.BreakerStats(name)
+	if !ok {
+		channel.Status.CircuitBreaker = nil
+		return false
+	}
+
+	status := &piiv1alpha1.CircuitBreakerStatus{
+		State:               string(stats.State),
+		ConsecutiveFailures: stats.ConsecutiveFailures,
+	}
+	if !stats.OpenedAt.IsZero() {
+		openedAt := metav1.NewTime(stats.OpenedAt)
+		status.OpenedAt = &openedAt
+	}
+	channel.Status.CircuitBreaker = status
+
+	switch stats.State {
+	case notifier.BreakerOpen:
+		r.setCondition(channel, "Degraded", metav1.ConditionTrue, "CircuitOpen",
+			fmt.Sprintf("Alerts fail fast after %d consecutive send failures", stats.ConsecutiveFailures))
+		return true
+	case notifier.BreakerHalfOpen:
+		r.setCondition(channel, "Degraded", metav1.ConditionTrue, "CircuitHalfOpen", "Probing the channel after send failures")
+		return true
+	default:
+		r.setCondition(channel, "Degraded", metav1.ConditionFalse, "CircuitClosed", "Alerts are being sent")
+		return false
+	}
+}
+
 // createSlackNotifier creates a Slack notifier from the channel spec
 func (r *PIIAlertChannelReconciler) createSlackNotifier(ctx context.Context, channel *piiv1alpha1.PIIAlertChannel) (notifier.Notifier, error) {
 	if channel.Spec.Slack == nil {
@@ -139,11 +194,19 @@ func (r *PIIAlertChannelReconciler) createSlackNotifier(ctx context.Context, cha
 		return nil, fmt.Errorf("either webhookURL or webhookURLValue must be specified")
 	}
 
+	timeout, err := channelTimeout(channel)
+	if err != nil {
+		return nil, err
+	}
+
 	config := notifier.SlackConfig{
-		WebhookURL: webhookURL,
-		Channel:    channel.Spec.Slack.Channel,
-		Username:   channel.Spec.Slack.Username,
-		IconEmoji:  channel.Spec.Slack.IconEmoji,
+		WebhookURL:      webhookURL,
+		Channel:         channel.Spec.Slack.Channel,
+		Username:        channel.Spec.Slack.Username,
+		IconEmoji:       channel.Spec.Slack.IconEmoji,
+		MessageTemplate: channel.Spec.MessageTemplate,
+		Timeout:         timeout,
+		SeverityScale:   r.NotifierManager.SeverityScale(),
 	}
 
 	return notifier.NewSlackNotifier(config), nil
@@ -164,9 +227,15 @@ func (r *PIIAlertChannelReconciler) createPagerDutyNotifier(ctx context.Context,
 		return nil, fmt.Errorf("failed to get service key from secret: %w", err)
 	}
 
+	timeout, err := channelTimeout(channel)
+	if err != nil {
+		return nil, err
+	}
+
 	config := notifier.PagerDutyConfig{
 		RoutingKey: routingKey,
 		Severity:   channel.Spec.PagerDuty.Severity,
+		Timeout:    timeout,
 	}
 
 	return notifier.NewPagerDutyNotifier(config), nil
@@ -205,10 +274,17 @@ func (r *PIIAlertChannelReconciler) createWebhookNotifier(ctx context.Context, c
 		headers[headerName] = value
 	}
 
+	timeout, err := channelTimeout(channel)
+	if err != nil {
+		return nil, err
+	}
+
 	config := notifier.WebhookConfig{
-		URL:     url,
-		Method:  channel.Spec.Webhook.Method,
-		Headers: headers,
+		URL:             url,
+		Method:          channel.Spec.Webhook.Method,
+		Headers:         headers,
+		MessageTemplate: channel.Spec.MessageTemplate,
+		Timeout:         timeout,
 	}
 
 	return notifier.NewWebhookNotifier(config), nil
@@ -230,10 +306,20 @@ func (r *PIIAlertChannelReconciler) createEmailNotifier(ctx context.Context, cha
 			return nil, fmt.Errorf("failed to get auth secret: %w", err)
 		}
 
+		for _, key := range []string{"username", "password"} {
+			if _, exists := secret.Data[key]; !exists {
+				return nil, fmt.Errorf("key %s not found in auth secret %s", key, secret.Name)
+			}
+		}
 		username = string(secret.Data["username"])
 		password = string(secret.Data["password"])
 	}
 
+	timeout, err := channelTimeout(channel)
+	if err != nil {
+		return nil, err
+	}
+
 	config := notifier.EmailConfig{
 		SMTPHost: channel.Spec.Email.SMTPHost,
 		SMTPPort: channel.Spec.Email.SMTPPort,
@@ -242,11 +328,68 @@ func (r *PIIAlertChannelReconciler) createEmailNotifier(ctx context.Context, cha
 		Username: username,
 		Password: password,
 		UseTLS:   channel.Spec.Email.UseTLS,
+		Timeout:  timeout,
 	}
 
 	return notifier.NewEmailNotifier(config), nil
 }
 
+// channelTimeout parses the channel's delivery timeout. Zero means the
+// notifier's default.
+func channelTimeout(channel *piiv1alpha1.PIIAlertChannel) (time.Duration, error) {
+	if channel.Spec.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(channel.Spec.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q: %w", channel.Spec.Timeout, err)
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be positive", channel.Spec.Timeout)
+	}
+	return timeout, nil
+}
+
+// sendTestAlert builds the channel's sample alert and sends it straight
+// through the notifier, bypassing the severity filter and rate limit, so the
+// full build and send path is exercised. It returns the delivery result.
+func sendTestAlert(ctx context.Context, n notifier.Notifier, channel *piiv1alpha1.PIIAlertChannel) *piiv1alpha1.TestAlertResult {
+	test := channel.Spec.TestAlert
+
+	patternName := test.PatternName
+	if patternName == "" {
+		patternName = "email"
+	}
+	severity := test.Severity
+	if severity == "" {
+		severity = notifier.SeverityHigh
+	}
+
+	alert := notifier.NewAlert(patternName, channel.Namespace,
+		fmt.Sprintf("Test alert %s for channel %s", test.ID, channel.Name)).
+		WithSeverity(severity).
+		WithPod(test.Pod, test.Container).
+		WithSource("test").
+		AddLabel("test", test.ID)
+	alert.RedactedText = test.RedactedText
+	alert.MatchCount = 1
+	for key, value := range test.Labels {
+		alert.AddLabel(key, value)
+	}
+
+	now := metav1.Now()
+	result := &piiv1alpha1.TestAlertResult{
+		ID:     test.ID,
+		SentAt: &now,
+	}
+	if err := n.Send(ctx, alert); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Delivered = true
+	return result
+}
+
 // getSecretValue retrieves a value from a secret
 func (r *PIIAlertChannelReconciler) getSecretValue(ctx context.Context, namespace string, ref *piiv1alpha1.SecretKeyRef) (string, error) {
 	secret := &corev1.Secret{}
@@ -289,9 +432,80 @@ func (r *PIIAlertChannelReconciler) setCondition(channel *piiv1alpha1.PIIAlertCh
 	channel.Status.Conditions = append(channel.Status.Conditions, condition)
 }
 
+// channelSecretIndex indexes PIIAlertChannels by the names of the Secrets
+// they reference
+const channelSecretIndex = "spec.secretNames"
+
+// referencedSecrets returns the names of the Secrets in the channel's
+// namespace that building its notifier reads
+func referencedSecrets(channel *piiv1alpha1.PIIAlertChannel) []string {
+	var names []string
+	add := func(ref *piiv1alpha1.SecretKeyRef) {
+		if ref != nil && ref.Name != "" {
+			names = append(names, ref.Name)
+		}
+	}
+
+	if channel.Spec.Slack != nil {
+		add(channel.Spec.Slack.WebhookURL)
+	}
+	if channel.Spec.PagerDuty != nil {
+		add(channel.Spec.PagerDuty.ServiceKey)
+	}
+	if channel.Spec.Webhook != nil {
+		add(channel.Spec.Webhook.URLFrom)
+		for _, ref := range channel.Spec.Webhook.SecretHeaders {
+			add(&ref)
+		}
+	}
+	if channel.Spec.Email != nil && channel.Spec.Email.AuthSecret != nil && channel.Spec.Email.AuthSecret.Name != "" {
+		names = append(names, channel.Spec.Email.AuthSecret.Name)
+	}
+
+	sort.Strings(names)
+	return slices.Compact(names)
+}
+
+// indexChannelSecrets is the index function of channelSecretIndex
+func indexChannelSecrets(obj client.Object) []string {
+	channel, ok := obj.(*piiv1alpha1.PIIAlertChannel)
+	if !ok {
+		return nil
+	}
+	return referencedSecrets(channel)
+}
+
+// channelsForSecret returns a request for every channel referencing the
+// secret, so that a channel waiting for its secret recovers once it is
+// created and a rotated secret is picked up
+func (r *PIIAlertChannelReconciler) channelsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	var channels piiv1alpha1.PIIAlertChannelList
+	if err := r.List(ctx, &channels,
+		client.InNamespace(secret.GetNamespace()),
+		client.MatchingFields{channelSecretIndex: secret.GetName()},
+	); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list PIIAlertChannels for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(channels.Items))
+	for _, channel := range channels.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: channel.Namespace, Name: channel.Name},
+		})
+	}
+	return requests
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *PIIAlertChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &piiv1alpha1.PIIAlertChannel{},
+		channelSecretIndex, indexChannelSecrets); err != nil {
+		return fmt.Errorf("failed to index PIIAlertChannel secrets: %w", err)
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&piiv1alpha1.PIIAlertChannel{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.channelsForSecret)).
 		Complete(r)
 }
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/notifier"
+)
+
+func TestSendTestAlert(t *testing.T) {
+	type slackPayload struct {
+		Attachments []struct {
+			Title  string `json:"title"`
+			Text   string `json:"text"`
+			Fields []struct {
+				Title string `json:"title"`
+				Value string `json:"value"`
+			} `json:"fields"`
+		} `json:"attachments"`
+	}
+
+	tests := []struct {
+		name          string
+		status        int
+		wantDelivered bool
+	}{
+		{name: "delivered", status: http.StatusOK, wantDelivered: true},
+		{name: "rejected by slack", status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received slackPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+					t.Errorf("failed to decode body: %v", err)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			channel := &piiv1alpha1.PIIAlertChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "security-slack", Namespace: "payments"},
+				Spec: piiv1alpha1.PIIAlertChannelSpec{
+					Type:            "slack",
+					MessageTemplate: "{{ .PatternName }} in {{ .Namespace }}/{{ .Pod }}: {{ .RedactedText }}",
+					TestAlert: &piiv1alpha1.TestAlert{
+						ID:           "format-check-1",
+						Severity:     "critical",
+						PatternName:  "credit-card",
+						Pod:          "checkout-0",
+						RedactedText: "card ****-****-****-1111",
+					},
+				},
+			}
+			n := notifier.NewSlackNotifier(notifier.SlackConfig{
+				WebhookURL:      server.URL,
+				MessageTemplate: channel.Spec.MessageTemplate,
+			})
+
+			result := sendTestAlert(context.Background(), n, channel)
+			if result.ID != "format-check-1" || result.SentAt == nil {
+				t.Errorf("result = %+v, want ID format-check-1 with SentAt set", result)
+			}
+			if result.Delivered != tt.wantDelivered || (result.Error == "") != tt.wantDelivered {
+				t.Fatalf("Delivered = %v, Error = %q, want delivered %v", result.Delivered, result.Error, tt.wantDelivered)
+			}
+
+			if len(received.Attachments) != 1 {
+				t.Fatalf("received %d attachments, want 1", len(received.Attachments))
+			}
+			attachment := received.Attachments[0]
+			if want := "credit-card in payments/checkout-0: card ****-****-****-1111"; attachment.Text != want {
+				t.Errorf("text = %q, want %q", attachment.Text, want)
+			}
+
+			fields := make(map[string]string)
+			for _, f := range attachment.Fields {
+				fields[f.Title] = f.Value
+			}
+			want := map[string]string{
+				"Severity":  "CRITICAL",
+				"Pattern":   "credit-card",
+				"Namespace": "payments",
+				"Pod":       "checkout-0",
+				"Source":    "test",
+			}
+			for title, value := range want {
+				if fields[title] != value {
+					t.Errorf("field %s = %q, want %q", title, fields[title], value)
+				}
+			}
+		})
+	}
+}
+
+func TestChannelTimeout(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "unset uses the notifier default", timeout: "", want: 0},
+		{name: "seconds", timeout: "5s", want: 5 * time.Second},
+		{name: "minutes", timeout: "2m", want: 2 * time.Minute},
+		{name: "not a duration", timeout: "fast", wantErr: true},
+		{name: "zero", timeout: "0s", wantErr: true},
+		{name: "negative", timeout: "-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel := &piiv1alpha1.PIIAlertChannel{Spec: piiv1alpha1.PIIAlertChannelSpec{Timeout: tt.timeout}}
+			got, err := channelTimeout(channel)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("channelTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("channelTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPIIAlertChannelReconciler_RecoversWhenSecretIsCreated(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := piiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	channel := &piiv1alpha1.PIIAlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "security-slack", Namespace: "payments"},
+		Spec: piiv1alpha1.PIIAlertChannelSpec{
+			Type: "slack",
+			Slack: &piiv1alpha1.SlackConfig{
+				WebhookURL: &piiv1alpha1.SecretKeyRef{Name: "slack-webhook", Key: "url"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(channel).
+		WithStatusSubresource(channel).
+		WithIndex(&piiv1alpha1.PIIAlertChannel{}, channelSecretIndex, indexChannelSecrets).
+		Build()
+	r := &PIIAlertChannelReconciler{Client: c, Scheme: scheme, NotifierManager: notifier.NewManager()}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "payments", Name: "security-slack"}}
+
+	ready := func() bool {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got piiv1alpha1.PIIAlertChannel
+		if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return got.Status.Ready
+	}
+
+	if ready() {
+		t.Fatal("channel is Ready before its secret exists")
+	}
+	if _, registered := r.NotifierManager.Get(req.String()); registered {
+		t.Fatal("notifier registered before its secret exists")
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "slack-webhook", Namespace: "payments"},
+		Data:       map[string][]byte{"url": []byte("https://hooks.slack.com/services/T000/B000/XXXX")},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Creating the secret requeues the channel that references it, and only that one
+	other := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "slack-webhook", Namespace: "other"}}
+	if requests := r.channelsForSecret(ctx, other); len(requests) != 0 {
+		t.Errorf("channelsForSecret(other namespace) = %v, want none", requests)
+	}
+	requests := r.channelsForSecret(ctx, secret)
+	if !reflect.DeepEqual(requests, []ctrl.Request{req}) {
+		t.Fatalf("channelsForSecret() = %v, want %v", requests, []ctrl.Request{req})
+	}
+
+	if !ready() {
+		t.Error("channel is not Ready after its secret was created")
+	}
+	if _, registered := r.NotifierManager.Get(req.String()); !registered {
+		t.Error("notifier not registered after its secret was created")
+	}
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	channel := &piiv1alpha1.PIIAlertChannel{
+		Spec: piiv1alpha1.PIIAlertChannelSpec{
+			Webhook: &piiv1alpha1.WebhookConfig{
+				URLFrom: &piiv1alpha1.SecretKeyRef{Name: "hook", Key: "url"},
+				SecretHeaders: map[string]piiv1alpha1.SecretKeyRef{
+					"Authorization": {Name: "hook", Key: "token"},
+					"X-Tenant":      {Name: "tenant", Key: "id"},
+				},
+			},
+			Email: &piiv1alpha1.EmailConfig{AuthSecret: &corev1.LocalObjectReference{Name: "smtp"}},
+		},
+	}
+
+	if got, want := referencedSecrets(channel), []string{"hook", "smtp", "tenant"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("referencedSecrets() = %v, want %v", got, want)
+	}
+}
+
+func TestPIIAlertChannelReconciler_ReportsCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := piiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	// The channel's endpoint is down
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	channel := &piiv1alpha1.PIIAlertChannel{
+		ObjectMeta: metav1.ObjectMeta{Name: "security-webhook", Namespace: "payments"},
+		Spec: piiv1alpha1.PIIAlertChannelSpec{
+			Type:    "webhook",
+			Webhook: &piiv1alpha1.WebhookConfig{URL: server.URL},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(channel).
+		WithStatusSubresource(channel).
+		WithIndex(&piiv1alpha1.PIIAlertChannel{}, channelSecretIndex, indexChannelSecrets).
+		Build()
+	manager := notifier.NewManager()
+	r := &PIIAlertChannelReconciler{Client: c, Scheme: scheme, NotifierManager: manager}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "payments", Name: "security-webhook"}}
+
+	reconcile := func() (ctrl.Result, *piiv1alpha1.PIIAlertChannel) {
+		t.Helper()
+		result, err := r.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got piiv1alpha1.PIIAlertChannel
+		if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return result, &got
+	}
+
+	if result, got := reconcile(); result.RequeueAfter != 0 || got.Status.CircuitBreaker == nil || got.Status.CircuitBreaker.State != "closed" {
+		t.Fatalf("new channel: requeue %v, breaker %+v, want closed without requeue", result.RequeueAfter, got.Status.CircuitBreaker)
+	}
+
+	alert := notifier.NewAlert("email", "payments", "PII detected")
+	for i := 0; i < notifier.DefaultFailureThreshold; i++ {
+		_ = manager.SendAlert(ctx, req.String(), alert)
+	}
+
+	// Reconciling registers the channel again without resetting its breaker
+	result, got := reconcile()
+	if got.Status.CircuitBreaker == nil || got.Status.CircuitBreaker.State != "open" || got.Status.CircuitBreaker.OpenedAt == nil {
+		t.Fatalf("CircuitBreaker = %+v, want open", got.Status.CircuitBreaker)
+	}
+	if result.RequeueAfter == 0 {
+		t.Error("channel with an open breaker is not requeued")
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, "Degraded") {
+		t.Errorf("Conditions = %+v, want Degraded", got.Status.Conditions)
+	}
+}
@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -22,8 +23,19 @@ type PIICommunitySourceReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Cache  *source.Cache
+
+	// FetchLimiter bounds concurrent fetches across all sources; nil means unlimited
+	FetchLimiter *source.FetchLimiter
 }
 
+// fetchLimitRequeueDelay is how long a reconcile waits when the concurrent
+// fetch limit is reached
+const fetchLimitRequeueDelay = 10 * time.Second
+
+// maxSyncJitterFraction caps the per-source jitter added to the sync
+// interval, as a fraction of the interval
+const maxSyncJitterFraction = 0.1
+
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piicommunitysources,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piicommunitysources/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piicommunitysources/finalizers,verbs=update
@@ -41,6 +53,14 @@ func (r *PIICommunitySourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Wait for a fetch slot so that many sources syncing at once do not
+	// overwhelm upstreams
+	if !r.FetchLimiter.TryAcquire() {
+		logger.V(1).Info("Concurrent fetch limit reached, requeueing", "name", communitySource.Name)
+		return ctrl.Result{RequeueAfter: fetchLimitRequeueDelay}, nil
+	}
+	defer r.FetchLimiter.Release()
+
 	logger.Info("Reconciling PIICommunitySource", "name", communitySource.Name, "type", communitySource.Spec.Type)
 
 	// Update status to syncing
@@ -84,15 +104,25 @@ func (r *PIICommunitySourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{RequeueAfter: time.Minute}, nil
 	}
 
-	// Update cache
+	// Update cache; patterns beyond the per-source limit are dropped
 	r.Cache.SetSource(req.String(), []*source.RuleSet{ruleSet})
+	totalPatterns := len(ruleSet.Patterns)
+	var warnings []string
+	if cached, ok := r.Cache.GetSource(req.String()); ok {
+		totalPatterns = cached.TotalPatterns
+		if cached.Warning != "" {
+			logger.Info("Source exceeds the pattern limit", "warning", cached.Warning)
+			warnings = append(warnings, cached.Warning)
+		}
+	}
 
 	// Update status
 	now := metav1.Now()
 	communitySource.Status.LastSyncTime = &now
 	communitySource.Status.SyncStatus = "Synced"
 	communitySource.Status.LastSyncError = ""
-	communitySource.Status.TotalPatterns = len(ruleSet.Patterns)
+	communitySource.Status.TotalPatterns = totalPatterns
+	communitySource.Status.Warnings = warnings
 
 	// Build available rule sets info
 	communitySource.Status.AvailableRuleSets = []piiv1alpha1.RuleSetInfo{
@@ -114,7 +144,7 @@ func (r *PIICommunitySourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	logger.Info("PIICommunitySource reconciled successfully",
 		"name", communitySource.Name,
-		"patterns", len(ruleSet.Patterns),
+		"patterns", totalPatterns,
 	)
 
 	// Calculate requeue interval
@@ -125,7 +155,19 @@ func (r *PIICommunitySourceReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 	}
 
-	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	return ctrl.Result{RequeueAfter: withSyncJitter(req.String(), requeueAfter)}, nil
+}
+
+// withSyncJitter adds a deterministic delay derived from the source key to the
+// sync interval, so sources with the same interval spread their syncs
+// instead of firing together. The delay is below maxSyncJitterFraction of
+// the interval.
+func withSyncJitter(key string, interval time.Duration) time.Duration {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	fraction := float64(h.Sum64()%1000) / 1000
+
+	return interval + time.Duration(fraction*maxSyncJitterFraction*float64(interval))
 }
 
 // createFetcher creates the appropriate fetcher based on source type
@@ -187,6 +229,10 @@ func (r *PIICommunitySourceReconciler) createOCIFetcher(ctx context.Context, com
 		Registry:   communitySource.Spec.OCI.Registry,
 		Repository: communitySource.Spec.OCI.Repository,
 		Tag:        communitySource.Spec.OCI.Tag,
+		Path:       communitySource.Spec.OCI.Path,
+
+		Subject:      communitySource.Spec.OCI.Subject,
+		ArtifactType: communitySource.Spec.OCI.ArtifactType,
 	}
 
 	// Get auth credentials if provided
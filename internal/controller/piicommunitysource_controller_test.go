@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWithSyncJitter(t *testing.T) {
+	interval := time.Hour
+	maxJitter := time.Duration(maxSyncJitterFraction * float64(interval))
+
+	first := withSyncJitter("default/community-rules", interval)
+	second := withSyncJitter("default/team-rules", interval)
+
+	if first == second {
+		t.Errorf("sources with the same interval got the same requeue %v", first)
+	}
+	for _, got := range []time.Duration{first, second} {
+		if got < interval || got >= interval+maxJitter {
+			t.Errorf("requeue = %v, want within [%v, %v)", got, interval, interval+maxJitter)
+		}
+	}
+
+	if again := withSyncJitter("default/community-rules", interval); again != first {
+		t.Errorf("requeue for the same source = %v, want deterministic %v", again, first)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -14,6 +17,9 @@ import (
 	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
 	"github.com/bunseokbot/pii-redactor/internal/detector"
 	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/detector/validator"
+	"github.com/bunseokbot/pii-redactor/internal/policy"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -22,6 +28,10 @@ type PIIPatternReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Engine *detector.Engine
+
+	// MaxRevealRatio is the largest share of a critical or high severity test
+	// value that masking may reveal; zero uses redactor.DefaultMaxRevealRatio
+	MaxRevealRatio float64
 }
 
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piipatterns,verbs=get;list;watch;create;update;patch;delete
@@ -43,7 +53,11 @@ func (r *PIIPatternReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	logger.Info("Reconciling PIIPattern", "name", pattern.Name)
 
 	// Validate and compile pattern
-	validationErrors := r.validatePattern(&pattern)
+	validationErrors, revealWarnings := validatePatternSpec(&pattern, r.maxRevealRatio())
+	pattern.Status.ValidationWarnings = revealWarnings
+	if len(revealWarnings) > 0 {
+		logger.Info("Masking reveals a large share of matched values", "name", pattern.Name, "warnings", revealWarnings)
+	}
 
 	if len(validationErrors) > 0 {
 		// Update status with errors
@@ -92,10 +106,27 @@ func (r *PIIPatternReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	return ctrl.Result{}, nil
 }
 
+// validatePatternSpec validates the pattern specification and checks the
+// reveal ratio of its masking. It is shared by the reconciler and the
+// admission webhook.
+func validatePatternSpec(pattern *piiv1alpha1.PIIPattern, maxRatio float64) (errors, warnings []string) {
+	errors = validatePattern(pattern)
+	revealErrors, warnings := checkRevealRatio(pattern, maxRatio)
+	return append(errors, revealErrors...), warnings
+}
+
 // validatePattern validates the pattern specification
-func (r *PIIPatternReconciler) validatePattern(pattern *piiv1alpha1.PIIPattern) []string {
+func validatePattern(pattern *piiv1alpha1.PIIPattern) []string {
 	var errors []string
 
+	// Validate composite components
+	switch {
+	case len(pattern.Spec.RequiresAll) == 0 && len(pattern.Spec.Patterns) == 0:
+		errors = append(errors, "patterns: at least one pattern is required unless requiresAll is set")
+	case len(pattern.Spec.RequiresAll) == 1:
+		errors = append(errors, "requiresAll: a composite pattern needs at least two components")
+	}
+
 	// Validate regex patterns
 	for i, p := range pattern.Spec.Patterns {
 		_, err := regexp.Compile(p.Regex)
@@ -104,6 +135,40 @@ func (r *PIIPatternReconciler) validatePattern(pattern *piiv1alpha1.PIIPattern)
 		}
 	}
 
+	// Validate the validator name, which would otherwise be ignored
+	if name := pattern.Spec.Validator; name != "" {
+		if _, ok := validator.GetValidator(name); !ok {
+			errors = append(errors, fmt.Sprintf("validator: unknown validator %q (known: %s)", name, strings.Join(validator.List(), ", ")))
+		}
+	}
+
+	// Validate masking reveal amounts
+	if _, _, err := patterns.ParseRevealAmount(pattern.Spec.MaskingStrategy.ShowFirst); err != nil {
+		errors = append(errors, fmt.Sprintf("maskingStrategy.showFirst: %s", err.Error()))
+	}
+	if _, _, err := patterns.ParseRevealAmount(pattern.Spec.MaskingStrategy.ShowLast); err != nil {
+		errors = append(errors, fmt.Sprintf("maskingStrategy.showLast: %s", err.Error()))
+	}
+	errors = append(errors, validateDelimiter("maskingStrategy", pattern.Spec.MaskingStrategy.Type, pattern.Spec.MaskingStrategy.Delimiter)...)
+	errors = append(errors, validateGroup("maskingStrategy", pattern.Spec.MaskingStrategy.Group, pattern.Spec.Patterns)...)
+	errors = append(errors, validateMaskingChain("maskingStrategy", pattern.Spec.MaskingStrategy.Chain)...)
+	for confidence, strategy := range pattern.Spec.ConfidenceMasking {
+		switch confidence {
+		case "high", "medium", "low":
+		default:
+			errors = append(errors, fmt.Sprintf("confidenceMasking: unknown confidence level %q", confidence))
+		}
+		if _, _, err := patterns.ParseRevealAmount(strategy.ShowFirst); err != nil {
+			errors = append(errors, fmt.Sprintf("confidenceMasking.%s.showFirst: %s", confidence, err.Error()))
+		}
+		if _, _, err := patterns.ParseRevealAmount(strategy.ShowLast); err != nil {
+			errors = append(errors, fmt.Sprintf("confidenceMasking.%s.showLast: %s", confidence, err.Error()))
+		}
+		errors = append(errors, validateDelimiter("confidenceMasking."+confidence, strategy.Type, strategy.Delimiter)...)
+		errors = append(errors, validateGroup("confidenceMasking."+confidence, strategy.Group, pattern.Spec.Patterns)...)
+		errors = append(errors, validateMaskingChain("confidenceMasking."+confidence, strategy.Chain)...)
+	}
+
 	// Validate test cases if provided
 	if pattern.Spec.TestCases != nil {
 		for _, p := range pattern.Spec.Patterns {
@@ -131,32 +196,179 @@ func (r *PIIPatternReconciler) validatePattern(pattern *piiv1alpha1.PIIPattern)
 	return errors
 }
 
-// convertToPatternSpec converts CRD spec to internal pattern spec
+// maxRevealRatio returns the configured reveal ratio limit
+func (r *PIIPatternReconciler) maxRevealRatio() float64 {
+	return revealRatioOrDefault(r.MaxRevealRatio)
+}
+
+// revealRatioOrDefault returns ratio, or redactor.DefaultMaxRevealRatio if it is not positive
+func revealRatioOrDefault(ratio float64) float64 {
+	if ratio > 0 {
+		return ratio
+	}
+	return redactor.DefaultMaxRevealRatio
+}
+
+// checkRevealRatio checks how much of the pattern's shouldMatch test values its
+// masking strategies reveal. Violations are errors for critical patterns and
+// warnings for high severity patterns; other severities are not checked.
+func checkRevealRatio(pattern *piiv1alpha1.PIIPattern, maxRatio float64) (errors, warnings []string) {
+	if pattern.Spec.TestCases == nil || len(pattern.Spec.TestCases.ShouldMatch) == 0 {
+		return nil, nil
+	}
+
+	var findings *[]string
+	switch pattern.Spec.Severity {
+	case "critical":
+		findings = &errors
+	case "high":
+		findings = &warnings
+	default:
+		return nil, nil
+	}
+
+	samples := matchedSamples(pattern)
+
+	// Strategies that do not convert are already reported by validatePattern
+	if strategy, err := policy.ConvertMaskingStrategy(pattern.Spec.MaskingStrategy); err == nil {
+		for _, v := range redactor.RevealViolations(strategy, samples, maxRatio) {
+			*findings = append(*findings, "maskingStrategy: "+v)
+		}
+	}
+
+	confidences := make([]string, 0, len(pattern.Spec.ConfidenceMasking))
+	for confidence := range pattern.Spec.ConfidenceMasking {
+		confidences = append(confidences, confidence)
+	}
+	sort.Strings(confidences)
+	for _, confidence := range confidences {
+		strategy, err := policy.ConvertMaskingStrategy(pattern.Spec.ConfidenceMasking[confidence])
+		if err != nil {
+			continue
+		}
+		for _, v := range redactor.RevealViolations(strategy, samples, maxRatio) {
+			*findings = append(*findings, fmt.Sprintf("confidenceMasking.%s: %s", confidence, v))
+		}
+	}
+
+	return errors, warnings
+}
+
+// matchedSamples returns the part of each shouldMatch test case matched by the
+// pattern, so that surrounding context does not dilute the reveal ratio
+func matchedSamples(pattern *piiv1alpha1.PIIPattern) []string {
+	var compiled []*regexp.Regexp
+	for _, p := range pattern.Spec.Patterns {
+		if re, err := regexp.Compile(p.Regex); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+
+	samples := make([]string, 0, len(pattern.Spec.TestCases.ShouldMatch))
+	for _, testCase := range pattern.Spec.TestCases.ShouldMatch {
+		sample := testCase
+		for _, re := range compiled {
+			if match := re.FindString(testCase); match != "" {
+				sample = match
+				break
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// convertToPatternSpec converts CRD spec to internal pattern spec. The
+// pattern must have passed validatePattern, which rejects masking strategies
+// that do not convert.
 func convertToPatternSpec(pattern *piiv1alpha1.PIIPattern) patterns.PIIPatternSpec {
+	masking, _ := policy.ConvertMaskingStrategy(pattern.Spec.MaskingStrategy)
 	spec := patterns.PIIPatternSpec{
-		DisplayName: pattern.Spec.DisplayName,
-		Description: pattern.Spec.Description,
-		Validator:   pattern.Spec.Validator,
-		Severity:    pattern.Spec.Severity,
-		MaskingStrategy: patterns.MaskingStrategy{
-			Type:        pattern.Spec.MaskingStrategy.Type,
-			ShowFirst:   pattern.Spec.MaskingStrategy.ShowFirst,
-			ShowLast:    pattern.Spec.MaskingStrategy.ShowLast,
-			MaskChar:    pattern.Spec.MaskingStrategy.MaskChar,
-			Replacement: pattern.Spec.MaskingStrategy.Replacement,
-		},
+		DisplayName:          pattern.Spec.DisplayName,
+		Description:          pattern.Spec.Description,
+		Tags:                 pattern.Spec.Tags,
+		Validator:            pattern.Spec.Validator,
+		Severity:             pattern.Spec.Severity,
+		MaskingStrategy:      masking,
+		SeparatorInsensitive: pattern.Spec.SeparatorInsensitive,
+		MinLength:            pattern.Spec.MinLength,
+		RequiresAll:          pattern.Spec.RequiresAll,
+		Window:               pattern.Spec.Window,
+		MaxMatchLength:       pattern.Spec.MaxMatchLength,
+		ContextKeywords:      pattern.Spec.ContextKeywords,
+		ContextWindow:        pattern.Spec.ContextWindow,
+		SensitiveGroups:      pattern.Spec.SensitiveGroups,
+	}
+
+	if len(pattern.Spec.ConfidenceMasking) > 0 {
+		spec.ConfidenceMasking = make(map[string]patterns.MaskingStrategy, len(pattern.Spec.ConfidenceMasking))
+		for confidence, strategy := range pattern.Spec.ConfidenceMasking {
+			spec.ConfidenceMasking[confidence], _ = policy.ConvertMaskingStrategy(strategy)
+		}
 	}
 
 	for _, p := range pattern.Spec.Patterns {
 		spec.Patterns = append(spec.Patterns, patterns.PatternRule{
-			Regex:      p.Regex,
-			Confidence: p.Confidence,
+			Regex:           p.Regex,
+			Confidence:      p.Confidence,
+			ContextKeywords: p.ContextKeywords,
+			ContextWindow:   p.ContextWindow,
 		})
 	}
 
 	return spec
 }
 
+// validateDelimiter checks that delimiter-anchored masking types set a delimiter
+func validateDelimiter(field, maskType, delimiter string) []string {
+	if (maskType == "maskBefore" || maskType == "maskAfter") && delimiter == "" {
+		return []string{fmt.Sprintf("%s.delimiter: required for masking type %s", field, maskType)}
+	}
+	return nil
+}
+
+// validateGroup checks that every regex of the pattern has the capture group
+// a masking strategy targets, by name or index
+func validateGroup(field, group string, rules []piiv1alpha1.PatternRule) []string {
+	if group == "" {
+		return nil
+	}
+	var errors []string
+	for i, p := range rules {
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			continue
+		}
+		if re.SubexpIndex(group) >= 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(group); err == nil && n >= 1 && n <= re.NumSubexp() {
+			continue
+		}
+		errors = append(errors, fmt.Sprintf("%s.group: pattern[%d] has no capture group %q", field, i, group))
+	}
+	return errors
+}
+
+// validateMaskingChain validates each step of a masking chain
+func validateMaskingChain(field string, chain []piiv1alpha1.MaskingStep) []string {
+	var errors []string
+	for i, step := range chain {
+		prefix := fmt.Sprintf("%s.chain[%d]", field, i)
+		if !patterns.IsMaskingType(step.Type) {
+			errors = append(errors, fmt.Sprintf("%s.type: unknown masking type %q", prefix, step.Type))
+		}
+		errors = append(errors, validateDelimiter(prefix, step.Type, step.Delimiter)...)
+		if _, _, err := patterns.ParseRevealAmount(step.ShowFirst); err != nil {
+			errors = append(errors, fmt.Sprintf("%s.showFirst: %s", prefix, err.Error()))
+		}
+		if _, _, err := patterns.ParseRevealAmount(step.ShowLast); err != nil {
+			errors = append(errors, fmt.Sprintf("%s.showLast: %s", prefix, err.Error()))
+		}
+	}
+	return errors
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *PIIPatternReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
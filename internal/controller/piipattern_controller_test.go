@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+)
+
+func TestCheckRevealRatio(t *testing.T) {
+	newPattern := func(severity string, showFirst, showLast intstr.IntOrString) *piiv1alpha1.PIIPattern {
+		return &piiv1alpha1.PIIPattern{
+			Spec: piiv1alpha1.PIIPatternSpec{
+				Patterns: []piiv1alpha1.PatternRule{{Regex: `EMP-\d{6}`, Confidence: "high"}},
+				MaskingStrategy: piiv1alpha1.MaskingStrategy{
+					Type:      "partial",
+					ShowFirst: showFirst,
+					ShowLast:  showLast,
+				},
+				Severity: severity,
+				TestCases: &piiv1alpha1.TestCases{
+					ShouldMatch: []string{"employee EMP-123456 logged in"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		pattern      *piiv1alpha1.PIIPattern
+		wantErrors   int
+		wantWarnings int
+	}{
+		{
+			name:       "critical revealing 80% is rejected",
+			pattern:    newPattern("critical", intstr.FromInt32(5), intstr.FromInt32(3)),
+			wantErrors: 1,
+		},
+		{
+			name:    "critical revealing 20% is allowed",
+			pattern: newPattern("critical", intstr.FromString("10%"), intstr.FromString("10%")),
+		},
+		{
+			name:         "high revealing 80% is a warning",
+			pattern:      newPattern("high", intstr.FromInt32(5), intstr.FromInt32(3)),
+			wantWarnings: 1,
+		},
+		{
+			name:    "medium is not checked",
+			pattern: newPattern("medium", intstr.FromInt32(5), intstr.FromInt32(3)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := checkRevealRatio(tt.pattern, 0.5)
+			if len(errs) != tt.wantErrors {
+				t.Errorf("errors = %v, want %d", errs, tt.wantErrors)
+			}
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestValidateMaskingChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		chain      []piiv1alpha1.MaskingStep
+		wantErrors int
+	}{
+		{
+			name:  "valid chain",
+			chain: []piiv1alpha1.MaskingStep{{Type: "partial", ShowFirst: intstr.FromInt32(2)}, {Type: "hash"}},
+		},
+		{
+			name:       "unknown type",
+			chain:      []piiv1alpha1.MaskingStep{{Type: "encrypt"}},
+			wantErrors: 1,
+		},
+		{
+			name:       "invalid reveal amount",
+			chain:      []piiv1alpha1.MaskingStep{{Type: "partial", ShowFirst: intstr.FromString("150%")}},
+			wantErrors: 1,
+		},
+		{
+			name:  "delimiter step",
+			chain: []piiv1alpha1.MaskingStep{{Type: "maskAfter", Delimiter: "@"}},
+		},
+		{
+			name:       "delimiter step without delimiter",
+			chain:      []piiv1alpha1.MaskingStep{{Type: "maskBefore"}},
+			wantErrors: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateMaskingChain("maskingStrategy", tt.chain)
+			if len(got) != tt.wantErrors {
+				t.Errorf("validateMaskingChain() = %v, want %d errors", got, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestValidateGroup(t *testing.T) {
+	rules := []piiv1alpha1.PatternRule{
+		{Regex: `postgres://[^:]+:(?P<password>[^@]+)@`},
+		{Regex: `mysql://[^:]+:(?P<password>[^@]+)@(\w+)`},
+	}
+
+	tests := []struct {
+		name       string
+		group      string
+		wantErrors int
+	}{
+		{name: "no group"},
+		{name: "named group in every rule", group: "password"},
+		{name: "index in every rule", group: "1"},
+		{name: "index missing from one rule", group: "2", wantErrors: 1},
+		{name: "unknown name", group: "secret", wantErrors: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateGroup("maskingStrategy", tt.group, rules)
+			if len(got) != tt.wantErrors {
+				t.Errorf("validateGroup() = %v, want %d errors", got, tt.wantErrors)
+			}
+		})
+	}
+}
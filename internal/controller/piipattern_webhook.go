@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+)
+
+// PIIPatternValidator rejects invalid PIIPatterns at admission time, running
+// the same checks as the reconciler before the object is stored
+type PIIPatternValidator struct {
+	// MaxRevealRatio is the largest share of a critical or high severity test
+	// value that masking may reveal; zero uses redactor.DefaultMaxRevealRatio
+	MaxRevealRatio float64
+}
+
+// +kubebuilder:webhook:path=/validate-pii-namjun-kim-v1alpha1-piipattern,mutating=false,failurePolicy=fail,sideEffects=None,groups=pii.namjun.kim,resources=piipatterns,verbs=create;update,versions=v1alpha1,name=vpiipattern.pii.namjun.kim,admissionReviewVersions=v1
+
+var _ admission.CustomValidator = &PIIPatternValidator{}
+
+// ValidateCreate validates a new PIIPattern
+func (v *PIIPatternValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(obj)
+}
+
+// ValidateUpdate validates an updated PIIPattern
+func (v *PIIPatternValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(newObj)
+}
+
+// ValidateDelete allows every deletion
+func (v *PIIPatternValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate compiles the regexes, runs the test cases and checks the masking
+// configuration. Reveal ratio warnings are returned as admission warnings.
+func (v *PIIPatternValidator) validate(obj runtime.Object) (admission.Warnings, error) {
+	pattern, ok := obj.(*piiv1alpha1.PIIPattern)
+	if !ok {
+		return nil, fmt.Errorf("expected a PIIPattern but got %T", obj)
+	}
+
+	errors, warnings := validatePatternSpec(pattern, revealRatioOrDefault(v.MaxRevealRatio))
+	if len(errors) > 0 {
+		return warnings, fmt.Errorf("invalid PIIPattern %s: %s", pattern.Name, strings.Join(errors, "; "))
+	}
+	return warnings, nil
+}
+
+// SetupWebhookWithManager registers the validating webhook with the Manager
+func (v *PIIPatternValidator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&piiv1alpha1.PIIPattern{}).
+		WithValidator(v).
+		Complete()
+}
@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+)
+
+func TestPIIPatternValidator(t *testing.T) {
+	newPattern := func(regex, shouldMatch string) *piiv1alpha1.PIIPattern {
+		return &piiv1alpha1.PIIPattern{
+			ObjectMeta: metav1.ObjectMeta{Name: "employee-id", Namespace: "default"},
+			Spec: piiv1alpha1.PIIPatternSpec{
+				Patterns:        []piiv1alpha1.PatternRule{{Regex: regex, Confidence: "high"}},
+				MaskingStrategy: piiv1alpha1.MaskingStrategy{Type: "full"},
+				Severity:        "medium",
+				TestCases: &piiv1alpha1.TestCases{
+					ShouldMatch: []string{shouldMatch},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pattern *piiv1alpha1.PIIPattern
+		wantErr bool
+	}{
+		{
+			name:    "valid pattern is admitted",
+			pattern: newPattern(`EMP-\d{6}`, "employee EMP-123456 logged in"),
+		},
+		{
+			name:    "bad regex is rejected",
+			pattern: newPattern(`EMP-(\d{6}`, "employee EMP-123456 logged in"),
+			wantErr: true,
+		},
+		{
+			name:    "failing test case is rejected",
+			pattern: newPattern(`EMP-\d{6}`, "employee EMP-12 logged in"),
+			wantErr: true,
+		},
+		{
+			name: "registered validator is admitted",
+			pattern: func() *piiv1alpha1.PIIPattern {
+				p := newPattern(`\b\d{16}\b`, "card 4111111111111111")
+				p.Spec.Validator = "luhn"
+				return p
+			}(),
+		},
+		{
+			name: "unknown validator is rejected",
+			pattern: func() *piiv1alpha1.PIIPattern {
+				p := newPattern(`\b\d{16}\b`, "card 4111111111111111")
+				p.Spec.Validator = "luhn-v2"
+				return p
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "composite pattern without rules is admitted",
+			pattern: &piiv1alpha1.PIIPattern{
+				ObjectMeta: metav1.ObjectMeta{Name: "name-with-dob", Namespace: "default"},
+				Spec: piiv1alpha1.PIIPatternSpec{
+					RequiresAll: []string{"default/person-name", "default/date-of-birth"},
+					Severity:    "high",
+				},
+			},
+		},
+		{
+			name: "pattern without rules or components is rejected",
+			pattern: &piiv1alpha1.PIIPattern{
+				ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"},
+				Spec:       piiv1alpha1.PIIPatternSpec{Severity: "low"},
+			},
+			wantErr: true,
+		},
+	}
+
+	validator := &PIIPatternValidator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(context.Background(), tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			_, err = validator.ValidateUpdate(context.Background(), tt.pattern, tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,13 +27,35 @@ type PIIPolicyReconciler struct {
 	AuditLogger     audit.AuditLogger
 	Matcher         *policy.Matcher
 	Aggregator      *policy.Aggregator
+
+	// AuditDestinations resolves the named sink a policy's audit action
+	// directs entries to. Entries go to AuditLogger when nil.
+	AuditDestinations *audit.Destinations
+
+	// RedactDestinations resolves the named sink a policy's redact action
+	// forwards redacted logs to. Redacted logs go to stdout when nil.
+	RedactDestinations *policy.RedactDestinations
+
+	// ConfigScanner scans ConfigMap and Secret data for policies that opt in.
+	// Config scanning is disabled when nil.
+	ConfigScanner *policy.ConfigScanner
+
+	// ConfigScanInterval is how often policies that scan configuration data
+	// rescan it. Zero uses DefaultConfigScanInterval.
+	ConfigScanInterval time.Duration
 }
 
+// DefaultConfigScanInterval is how often configuration data is rescanned when
+// the reconciler does not set an interval
+const DefaultConfigScanInterval = 10 * time.Minute
+
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piipolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piipolicies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piipolicies/finalizers,verbs=update
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 
 // Reconcile handles PIIPolicy reconciliation
 func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -40,6 +64,9 @@ func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Fetch the PIIPolicy
 	var piiPolicy piiv1alpha1.PIIPolicy
 	if err := r.Get(ctx, req.NamespacedName, &piiPolicy); err != nil {
+		if apierrors.IsNotFound(err) && r.ConfigScanner != nil {
+			r.ConfigScanner.Forget(req.String())
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
@@ -93,6 +120,19 @@ func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}
 	}
 
+	// Validate the deduplication key template
+	_, dedupKeyErr := dedupKeyTemplate(&piiPolicy)
+	if dedupKeyErr != nil {
+		logger.Info("Invalid deduplication key template", "error", dedupKeyErr.Error())
+	}
+
+	// Validate the redact destination if redaction is enabled
+	if action := piiPolicy.Spec.Actions.Redact; action != nil && action.Enabled && r.RedactDestinations != nil {
+		if _, err := r.RedactDestinations.Resolve(action.Destination); err != nil {
+			logger.Info("Redact destination not configured", "destination", action.Destination)
+		}
+	}
+
 	// Update status
 	now := metav1.Now()
 	piiPolicy.Status.Active = true
@@ -100,7 +140,10 @@ func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	piiPolicy.Status.LoadedPatterns = aggregationResult.TotalPatterns
 	piiPolicy.Status.LastUpdated = &now
 
-	if aggregationResult.TotalPatterns == 0 {
+	if dedupKeyErr != nil {
+		r.setCondition(&piiPolicy, "Ready", metav1.ConditionFalse, "InvalidDeduplicationKey", dedupKeyErr.Error())
+		piiPolicy.Status.Active = false
+	} else if aggregationResult.TotalPatterns == 0 {
 		r.setCondition(&piiPolicy, "Ready", metav1.ConditionFalse, "NoPatterns", "No patterns were loaded")
 		piiPolicy.Status.Active = false
 	} else if len(matchedNamespaces) == 0 {
@@ -116,7 +159,8 @@ func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Log audit entry for policy update
-	if r.AuditLogger != nil {
+	auditLogger := r.auditLogger(ctx, &piiPolicy)
+	if auditLogger != nil {
 		entry := audit.NewAuditEntry(
 			audit.EventTypePolicyMatch,
 			piiPolicy.Namespace,
@@ -127,20 +171,148 @@ func (r *PIIPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 			AddLabel("matchedNamespaces", joinStrings(matchedNamespaces)).
 			AddLabel("validAlertChannels", joinStrings(validChannels))
 
-		if err := r.AuditLogger.Log(ctx, entry); err != nil {
+		if err := auditLogger.Log(ctx, entry); err != nil {
 			logger.Error(err, "Failed to log audit entry")
 		}
 	}
 
+	scanned := r.scanConfig(ctx, &piiPolicy, matchedNamespaces, aggregationResult, validChannels, auditLogger)
+
 	logger.Info("PIIPolicy reconciled successfully",
 		"name", piiPolicy.Name,
 		"matchedNamespaces", len(matchedNamespaces),
 		"loadedPatterns", aggregationResult.TotalPatterns,
 	)
 
+	if scanned {
+		// Configuration objects are not watched; rescan them periodically
+		return ctrl.Result{RequeueAfter: r.configScanInterval()}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// configScanInterval returns how often configuration data is rescanned
+func (r *PIIPolicyReconciler) configScanInterval() time.Duration {
+	if r.ConfigScanInterval > 0 {
+		return r.ConfigScanInterval
+	}
+	return DefaultConfigScanInterval
+}
+
+// scanConfig scans ConfigMap and Secret data in the matched namespaces when the
+// policy opts in, recording each finding the policy's previous scan did not
+// report in the audit log and alerting the policy's channels. It reports
+// whether the policy scans configuration data.
+func (r *PIIPolicyReconciler) scanConfig(ctx context.Context, piiPolicy *piiv1alpha1.PIIPolicy, namespaces []string, aggregationResult *policy.AggregationResult, channels []string, auditLogger audit.AuditLogger) bool {
+	if r.ConfigScanner == nil {
+		return false
+	}
+	policyKey := client.ObjectKeyFromObject(piiPolicy).String()
+	action := piiPolicy.Spec.Actions.ScanConfig
+	if action == nil || !action.Enabled {
+		r.ConfigScanner.Forget(policyKey)
+		return false
+	}
+
+	logger := log.FromContext(ctx)
+
+	dedupKey, _ := dedupKeyTemplate(piiPolicy)
+
+	engine := r.Aggregator.ScopedEngine(aggregationResult)
+	findings, err := r.ConfigScanner.Scan(ctx, engine, action, piiPolicy.Spec.Performance, namespaces, aggregationResult.AllPatterns())
+	if err != nil {
+		logger.Error(err, "Failed to scan configuration data")
+		return true
+	}
+
+	blocker := policy.NewBlocker(piiPolicy.Spec.Actions.Block)
+	for _, finding := range r.ConfigScanner.Unreported(policyKey, findings) {
+		logger.Info("PII found in configuration data",
+			"source", finding.Source,
+			"namespace", finding.Namespace,
+			"name", finding.Name,
+			"key", finding.Key,
+		)
+
+		if auditLogger != nil {
+			if err := auditLogger.Log(ctx, finding.AuditEntry(piiPolicy.Name, blocker)); err != nil {
+				logger.Error(err, "Failed to log audit entry")
+			}
+		}
+
+		if len(channels) > 0 {
+			results, _ := r.NotifierManager.SendAlertToChannels(ctx, channels, finding.Alert(piiPolicy.Name).WithDedupKey(dedupKey))
+			for _, result := range results {
+				if result.Err != nil {
+					logger.Error(result.Err, "Failed to send alert", "channel", result.Channel)
+				}
+			}
+		}
+	}
+	return true
+}
+
+// auditLogger returns the logger for the policy's audit destination, falling
+// back to the default logger when the destination is not configured
+func (r *PIIPolicyReconciler) auditLogger(ctx context.Context, piiPolicy *piiv1alpha1.PIIPolicy) audit.AuditLogger {
+	action := piiPolicy.Spec.Actions.Audit
+	if r.AuditDestinations == nil || action == nil || action.Destination == "" {
+		return r.AuditLogger
+	}
+
+	auditLogger, err := r.AuditDestinations.Resolve(action.Destination)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve audit destination, using the default audit logger")
+		return r.AuditLogger
+	}
+	return auditLogger
+}
+
+// RedactForwarder returns the forwarder that routes a policy's redacted logs
+// to its redact destination and its detections to the audit log and the given
+// alert channels, enforcing the policy's block action and sampling rate. It
+// returns nil when the policy neither redacts nor blocks.
+func (r *PIIPolicyReconciler) RedactForwarder(ctx context.Context, piiPolicy *piiv1alpha1.PIIPolicy, channels []string) *policy.Forwarder {
+	action := piiPolicy.Spec.Actions.Redact
+	redacts := action != nil && action.Enabled
+	blocker := policy.NewBlocker(piiPolicy.Spec.Actions.Block)
+	if !redacts && blocker == nil {
+		return nil
+	}
+
+	var destination policy.RedactDestination
+	if redacts && r.RedactDestinations != nil {
+		var err error
+		if destination, err = r.RedactDestinations.Resolve(action.Destination); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to resolve redact destination, redacted logs are not forwarded")
+		}
+	}
+
+	forwarder := policy.NewForwarder(piiPolicy.Name, destination, r.auditLogger(ctx, piiPolicy), r.NotifierManager, channels)
+	if dedupKey, err := dedupKeyTemplate(piiPolicy); err != nil {
+		log.FromContext(ctx).Error(err, "Invalid deduplication key template, using the default key")
+	} else {
+		forwarder.SetDedupKeyTemplate(dedupKey)
+	}
+	forwarder.SetBlocker(blocker)
+	if sampler, err := policy.NewPolicySampler(piiPolicy.Spec.Performance); err != nil {
+		log.FromContext(ctx).Error(err, "Invalid sampling settings, processing every log")
+	} else {
+		forwarder.SetSampler(sampler, r.Engine)
+	}
+	return forwarder
+}
+
+// dedupKeyTemplate parses the policy's alert deduplication key template. It
+// returns nil when the policy does not set one.
+func dedupKeyTemplate(piiPolicy *piiv1alpha1.PIIPolicy) (*notifier.DedupKeyTemplate, error) {
+	alert := piiPolicy.Spec.Actions.Alert
+	if alert == nil || alert.Deduplication == nil {
+		return nil, nil
+	}
+	return notifier.ParseDedupKeyTemplate(alert.Deduplication.Key)
+}
+
 // setCondition sets a condition on the policy status
 func (r *PIIPolicyReconciler) setCondition(piiPolicy *piiv1alpha1.PIIPolicy, condType string, status metav1.ConditionStatus, reason, message string) {
 	now := metav1.Now()
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+)
+
+func TestPIIPolicyReconciler_AuditDestination(t *testing.T) {
+	var fallback bytes.Buffer
+	defaultLogger := audit.NewJSONLogger(&fallback)
+	destinations := audit.NewDestinations(defaultLogger)
+	defer destinations.Close()
+
+	path := filepath.Join(t.TempDir(), "team-a.jsonl")
+	if err := destinations.Register("team-a", path); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	r := &PIIPolicyReconciler{
+		AuditLogger:       defaultLogger,
+		AuditDestinations: destinations,
+	}
+
+	newPolicy := func(name, destination string) *piiv1alpha1.PIIPolicy {
+		p := &piiv1alpha1.PIIPolicy{}
+		p.Name = name
+		p.Spec.Actions.Audit = &piiv1alpha1.AuditAction{Enabled: true, Destination: destination}
+		return p
+	}
+
+	ctx := context.Background()
+	for _, p := range []*piiv1alpha1.PIIPolicy{
+		newPolicy("team-a-policy", "team-a"),
+		newPolicy("default-policy", ""),
+		newPolicy("unknown-policy", "team-b"),
+	} {
+		entry := audit.NewAuditEntry(audit.EventTypePolicyMatch, "default", p.Name, "")
+		if err := r.auditLogger(ctx, p).Log(ctx, entry); err != nil {
+			t.Fatalf("Log() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got := string(data); !strings.Contains(got, "team-a-policy") || strings.Count(got, "\n") != 1 {
+		t.Errorf("team-a sink = %s, want only the team-a-policy entry", got)
+	}
+
+	got := fallback.String()
+	if strings.Contains(got, "team-a-policy") {
+		t.Errorf("default logger received the team-a-policy entry: %s", got)
+	}
+	if !strings.Contains(got, "default-policy") || !strings.Contains(got, "unknown-policy") {
+		t.Errorf("default logger = %s, want default-policy and unknown-policy entries", got)
+	}
+}
+
+func TestPIIPolicyReconciler_RedactForwarder(t *testing.T) {
+	r := &PIIPolicyReconciler{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		actions piiv1alpha1.PolicyActions
+		wantNil bool
+	}{
+		{name: "no actions", wantNil: true},
+		{name: "redact", actions: piiv1alpha1.PolicyActions{Redact: &piiv1alpha1.RedactAction{Enabled: true}}},
+		{name: "block only", actions: piiv1alpha1.PolicyActions{Block: &piiv1alpha1.BlockAction{Enabled: true}}},
+		{name: "disabled block", actions: piiv1alpha1.PolicyActions{Block: &piiv1alpha1.BlockAction{}}, wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &piiv1alpha1.PIIPolicy{}
+			p.Name = "policy"
+			p.Spec.Actions = tt.actions
+			if got := r.RedactForwarder(ctx, p, nil); (got == nil) != tt.wantNil {
+				t.Errorf("RedactForwarder() = %v, wantNil %v", got, tt.wantNil)
+			}
+		})
+	}
+}
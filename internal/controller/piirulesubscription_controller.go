@@ -2,9 +2,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -32,6 +36,7 @@ type PIIRuleSubscriptionReconciler struct {
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piirulesubscriptions/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piirulesubscriptions/finalizers,verbs=update
 // +kubebuilder:rbac:groups=pii.namjun.kim,resources=piicommunitysources,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 
 // Reconcile handles PIIRuleSubscription reconciliation
 func (r *PIIRuleSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -76,8 +81,38 @@ func (r *PIIRuleSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
+	// Dry run the patterns against the validation corpus before activating them
+	corpusValidation, err := r.validateCorpus(ctx, &ruleSubscription)
+	ruleSubscription.Status.CorpusValidation = corpusValidation
+	if err != nil {
+		logger.Info("Corpus validation failed, patterns are not activated", "error", err.Error())
+		ruleSubscription.Status.SyncStatus = "Error"
+		ruleSubscription.Status.LastError = err.Error()
+		reason := "CorpusValidationFailed"
+		if errors.Is(err, subscription.ErrInvalidOverrides) {
+			reason = "InvalidOverrides"
+		}
+		r.setCondition(&ruleSubscription, "Ready", metav1.ConditionFalse, reason, err.Error())
+
+		if err := r.Status().Update(ctx, &ruleSubscription); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
+
 	// Process subscription
 	result, err := r.SubscriptionManager.Subscribe(ctx, ruleSubscription.Spec)
+	if errors.Is(err, subscription.ErrInvalidOverrides) {
+		logger.Info("Subscription has invalid overrides, patterns are not activated", "error", err.Error())
+		ruleSubscription.Status.SyncStatus = "Error"
+		ruleSubscription.Status.LastError = err.Error()
+		r.setCondition(&ruleSubscription, "Ready", metav1.ConditionFalse, "InvalidOverrides", err.Error())
+
+		if err := r.Status().Update(ctx, &ruleSubscription); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: time.Minute}, nil
+	}
 	if err != nil {
 		r.setErrorStatus(ctx, &ruleSubscription, err)
 		return ctrl.Result{RequeueAfter: time.Minute}, nil
@@ -118,6 +153,8 @@ func (r *PIIRuleSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.
 	ruleSubscription.Status.PendingUpdates = pendingUpdates
 	ruleSubscription.Status.SyncStatus = "Synced"
 	ruleSubscription.Status.LastError = ""
+	ruleSubscription.Status.Warnings = result.Errors
+	ruleSubscription.Status.RetryingPatterns = result.Retrying
 
 	if result.TotalPatterns == 0 {
 		r.setCondition(&ruleSubscription, "Ready", metav1.ConditionFalse, "NoPatterns", "No patterns matched the subscription criteria")
@@ -133,13 +170,71 @@ func (r *PIIRuleSubscriptionReconciler) Reconcile(ctx context.Context, req ctrl.
 	logger.Info("PIIRuleSubscription reconciled successfully",
 		"name", ruleSubscription.Name,
 		"subscribedPatterns", result.TotalPatterns,
+		"retryingPatterns", len(result.Retrying),
 		"pendingUpdates", len(pendingUpdates),
 	)
 
+	// Requeue early to retry patterns that failed to register
+	if len(result.Retrying) > 0 && result.RetryAfter < 15*time.Minute {
+		return ctrl.Result{RequeueAfter: max(result.RetryAfter, time.Second)}, nil
+	}
+
 	// Requeue to check for updates periodically
 	return ctrl.Result{RequeueAfter: 15 * time.Minute}, nil
 }
 
+// validateCorpus dry runs the subscribed patterns, in an engine of their own,
+// against the corpus named by the subscription's validate-corpus annotation.
+// It returns a nil status when the annotation is not set, and an error when
+// the corpus cannot be read or scanned or the dry run reports errors, in which
+// case the patterns must not be activated.
+func (r *PIIRuleSubscriptionReconciler) validateCorpus(ctx context.Context, ruleSubscription *piiv1alpha1.PIIRuleSubscription) (*piiv1alpha1.CorpusValidationStatus, error) {
+	name := ruleSubscription.Annotations[piiv1alpha1.ValidateCorpusAnnotation]
+	if name == "" {
+		return nil, nil
+	}
+	status := &piiv1alpha1.CorpusValidationStatus{ConfigMap: name, ValidatedAt: metav1.Now()}
+
+	var configMap corev1.ConfigMap
+	if err := r.Get(ctx, types.NamespacedName{Namespace: ruleSubscription.Namespace, Name: name}, &configMap); err != nil {
+		status.Error = fmt.Sprintf("failed to get corpus ConfigMap: %v", err)
+		return status, fmt.Errorf("corpus validation: %s", status.Error)
+	}
+
+	report, err := r.SubscriptionManager.DryRunAgainstCorpus(ctx, ruleSubscription.Spec, corpusLines(&configMap))
+	if err != nil {
+		status.Error = err.Error()
+		return status, fmt.Errorf("corpus validation: %w", err)
+	}
+	status.Lines = report.Lines
+	status.Patterns = report.Patterns
+	if len(report.Errors) > 0 {
+		status.Error = strings.Join(report.Errors, "; ")
+		return status, fmt.Errorf("corpus validation: %s", status.Error)
+	}
+	return status, nil
+}
+
+// corpusLines returns the non-empty lines of the ConfigMap's data values,
+// in key order
+func corpusLines(configMap *corev1.ConfigMap) []string {
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		for _, line := range strings.Split(configMap.Data[key], "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
 // setErrorStatus sets error status on the subscription
 func (r *PIIRuleSubscriptionReconciler) setErrorStatus(ctx context.Context, subscription *piiv1alpha1.PIIRuleSubscription, err error) {
 	subscription.Status.SyncStatus = "Error"
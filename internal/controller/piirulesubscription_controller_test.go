@@ -0,0 +1,242 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/source"
+	"github.com/bunseokbot/pii-redactor/internal/subscription"
+)
+
+func TestPIIRuleSubscriptionReconciler_CorpusValidation(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := piiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		patterns       []source.PatternDefinition
+		corpus         *corev1.ConfigMap
+		wantActivated  bool
+		wantReason     string
+		wantCorpusScan bool
+	}{
+		{
+			name: "valid patterns",
+			patterns: []source.PatternDefinition{
+				{Name: "precise-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\b\d{6}-[1-4]\d{6}\b`, Confidence: "high"}}, Severity: "critical"},
+			},
+			corpus:         &corev1.ConfigMap{Data: map[string]string{"logs": "rrn=920101-1234567\nno numbers here"}},
+			wantActivated:  true,
+			wantReason:     "Subscribed",
+			wantCorpusScan: true,
+		},
+		{
+			name: "pattern failing the dry run",
+			patterns: []source.PatternDefinition{
+				{Name: "precise-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\b\d{6}-[1-4]\d{6}\b`, Confidence: "high"}}, Severity: "critical"},
+				{Name: "broken", Category: "korea", Patterns: []source.PatternRule{{Regex: `(`}}, Severity: "low"},
+			},
+			corpus:         &corev1.ConfigMap{Data: map[string]string{"logs": "rrn=920101-1234567"}},
+			wantReason:     "CorpusValidationFailed",
+			wantCorpusScan: true,
+		},
+		{
+			name: "missing corpus",
+			patterns: []source.PatternDefinition{
+				{Name: "precise-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\b\d{6}-[1-4]\d{6}\b`, Confidence: "high"}}, Severity: "critical"},
+			},
+			wantReason: "CorpusValidationFailed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			communitySource := &piiv1alpha1.PIICommunitySource{
+				ObjectMeta: metav1.ObjectMeta{Name: "community", Namespace: "default"},
+				Status:     piiv1alpha1.PIICommunitySourceStatus{SyncStatus: "Synced"},
+			}
+			ruleSubscription := &piiv1alpha1.PIIRuleSubscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "korea-rules",
+					Namespace:   "default",
+					Annotations: map[string]string{piiv1alpha1.ValidateCorpusAnnotation: "corpus"},
+				},
+				Spec: piiv1alpha1.PIIRuleSubscriptionSpec{
+					SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+					Subscribe: []piiv1alpha1.CategorySubscription{{Category: "korea"}},
+				},
+			}
+			builder := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(communitySource, ruleSubscription).
+				WithStatusSubresource(communitySource, ruleSubscription)
+			if tt.corpus != nil {
+				tt.corpus.ObjectMeta = metav1.ObjectMeta{Name: "corpus", Namespace: "default"}
+				builder = builder.WithObjects(tt.corpus)
+			}
+			c := builder.Build()
+
+			cache := source.NewCache()
+			cache.SetSource("community", []*source.RuleSet{{
+				Name:     "korea",
+				Version:  "1.0.0",
+				Maturity: "stable",
+				Patterns: tt.patterns,
+			}})
+			engine := detector.NewEngine()
+			before := len(engine.ListPatterns())
+			r := &PIIRuleSubscriptionReconciler{
+				Client:              c,
+				Scheme:              scheme,
+				Engine:              engine,
+				Cache:               cache,
+				SubscriptionManager: subscription.NewManager(cache, engine),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "korea-rules"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if activated := len(engine.ListPatterns()) > before; activated != tt.wantActivated {
+				t.Errorf("patterns activated = %v, want %v", activated, tt.wantActivated)
+			}
+
+			var got piiv1alpha1.PIIRuleSubscription
+			if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			ready := meta.FindStatusCondition(got.Status.Conditions, "Ready")
+			if ready == nil || ready.Reason != tt.wantReason {
+				t.Fatalf("Ready condition = %+v, want reason %s", ready, tt.wantReason)
+			}
+			if !tt.wantActivated && ready.Message == "" {
+				t.Error("Ready condition does not record the validation errors")
+			}
+			if validation := got.Status.CorpusValidation; validation == nil || (validation.Lines > 0) != tt.wantCorpusScan {
+				t.Errorf("CorpusValidation = %+v, want scanned %v", validation, tt.wantCorpusScan)
+			}
+		})
+	}
+}
+
+func TestPIIRuleSubscriptionReconciler_Overrides(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := piiv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		override      piiv1alpha1.PatternOverride
+		wantActivated bool
+		wantStatus    string
+		wantReason    string
+		wantWarnings  []string
+	}{
+		{
+			name:          "misspelled override",
+			override:      piiv1alpha1.PatternOverride{Pattern: "kr-phnoe", Severity: "low"},
+			wantActivated: true,
+			wantStatus:    "Synced",
+			wantReason:    "Subscribed",
+			wantWarnings:  []string{"override does not match any subscribed pattern: kr-phnoe"},
+		},
+		{
+			name: "invalid masking override",
+			override: piiv1alpha1.PatternOverride{Pattern: "kr-phone", MaskingStrategy: &piiv1alpha1.MaskingStrategy{
+				Type:     "partial",
+				ShowLast: intstr.FromString("150%"),
+			}},
+			wantStatus: "Error",
+			wantReason: "InvalidOverrides",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			communitySource := &piiv1alpha1.PIICommunitySource{
+				ObjectMeta: metav1.ObjectMeta{Name: "community", Namespace: "default"},
+				Status:     piiv1alpha1.PIICommunitySourceStatus{SyncStatus: "Synced"},
+			}
+			ruleSubscription := &piiv1alpha1.PIIRuleSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "korea-rules", Namespace: "default"},
+				Spec: piiv1alpha1.PIIRuleSubscriptionSpec{
+					SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+					Subscribe: []piiv1alpha1.CategorySubscription{{Category: "korea"}},
+					Overrides: []piiv1alpha1.PatternOverride{tt.override},
+				},
+			}
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(communitySource, ruleSubscription).
+				WithStatusSubresource(communitySource, ruleSubscription).
+				Build()
+
+			cache := source.NewCache()
+			cache.SetSource("community", []*source.RuleSet{{
+				Name:     "korea",
+				Version:  "1.0.0",
+				Maturity: "stable",
+				Patterns: []source.PatternDefinition{
+					{Name: "kr-phone", Category: "korea", Patterns: []source.PatternRule{{Regex: `010-\d{4}-\d{4}`, Confidence: "high"}}, Severity: "high"},
+				},
+			}})
+			engine := detector.NewEngine()
+			before := len(engine.ListPatterns())
+			r := &PIIRuleSubscriptionReconciler{
+				Client:              c,
+				Scheme:              scheme,
+				Engine:              engine,
+				Cache:               cache,
+				SubscriptionManager: subscription.NewManager(cache, engine),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "korea-rules"}}
+			if _, err := r.Reconcile(ctx, req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if activated := len(engine.ListPatterns()) > before; activated != tt.wantActivated {
+				t.Errorf("patterns activated = %v, want %v", activated, tt.wantActivated)
+			}
+
+			var got piiv1alpha1.PIIRuleSubscription
+			if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Status.SyncStatus != tt.wantStatus {
+				t.Errorf("SyncStatus = %q, want %q", got.Status.SyncStatus, tt.wantStatus)
+			}
+			ready := meta.FindStatusCondition(got.Status.Conditions, "Ready")
+			if ready == nil || ready.Reason != tt.wantReason {
+				t.Fatalf("Ready condition = %+v, want reason %s", ready, tt.wantReason)
+			}
+			if !reflect.DeepEqual(got.Status.Warnings, tt.wantWarnings) {
+				t.Errorf("Warnings = %v, want %v", got.Status.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
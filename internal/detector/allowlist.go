@@ -0,0 +1,184 @@
+package detector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// allowlistRegexPrefix marks an allowlist line as a regular expression
+const allowlistRegexPrefix = "re:"
+
+// DefaultAllowlistPollInterval is how often WatchAllowlistFile checks the
+// allowlist file for changes when no interval is given
+const DefaultAllowlistPollInterval = 2 * time.Second
+
+// Allowlist suppresses detections of known false positives, such as test
+// card numbers or example addresses. A detection is suppressed when its
+// matched text equals a listed value or fully matches a listed regular
+// expression.
+type Allowlist struct {
+	values  map[string]bool
+	regexes []*regexp.Regexp
+}
+
+// ParseAllowlist reads an allowlist with one entry per line. Lines starting
+// with "re:" are regular expressions that must match the whole detection;
+// other lines are literal values. Surrounding whitespace, blank lines and
+// lines starting with # are ignored.
+func ParseAllowlist(r io.Reader) (*Allowlist, error) {
+	allowlist := &Allowlist{values: make(map[string]bool)}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		expr, isRegex := strings.CutPrefix(entry, allowlistRegexPrefix)
+		if !isRegex {
+			allowlist.values[entry] = true
+			continue
+		}
+
+		re, err := regexp.Compile(`^(?:` + strings.TrimSpace(expr) + `)$`)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid regex: %w", line, err)
+		}
+		allowlist.regexes = append(allowlist.regexes, re)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return allowlist, nil
+}
+
+// LoadAllowlistFile reads an allowlist from a file
+func LoadAllowlistFile(path string) (*Allowlist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	allowlist, err := ParseAllowlist(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return allowlist, nil
+}
+
+// Allows reports whether detections of text are suppressed. A nil
+// allowlist allows nothing.
+func (a *Allowlist) Allows(text string) bool {
+	if a == nil {
+		return false
+	}
+	if a.values[text] {
+		return true
+	}
+	for _, re := range a.regexes {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of entries in the allowlist
+func (a *Allowlist) Len() int {
+	if a == nil {
+		return 0
+	}
+	return len(a.values) + len(a.regexes)
+}
+
+// SetAllowlist replaces the allowlist applied to all detections. Scans in
+// progress finish with the previous allowlist. A nil allowlist suppresses
+// nothing.
+func (e *Engine) SetAllowlist(allowlist *Allowlist) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.allowlist = allowlist
+}
+
+// filterAllowed drops the detections the allowlist suppresses
+func (e *Engine) filterAllowed(results []DetectionResult) []DetectionResult {
+	if e.allowlist.Len() == 0 {
+		return results
+	}
+
+	kept := results[:0]
+	for _, r := range results {
+		if !e.allowlist.Allows(r.MatchedText) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// WatchAllowlistFile loads the allowlist file into the engine and reloads it
+// whenever the file's size or modification time changes, polling every
+// interval (DefaultAllowlistPollInterval when zero) until ctx is done.
+// Polling avoids platform-specific file notification APIs. The initial load
+// must succeed; when a reload fails, the previous allowlist stays in effect
+// and onError, if not nil, is called with the error.
+func (e *Engine) WatchAllowlistFile(ctx context.Context, path string, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		interval = DefaultAllowlistPollInterval
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	allowlist, err := LoadAllowlistFile(path)
+	if err != nil {
+		return err
+	}
+	e.SetAllowlist(allowlist)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// reported is the last error passed to onError, so that a file that
+		// stays missing or invalid is reported once rather than every poll
+		var reported string
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := os.Stat(path)
+			if err == nil && current.Size() == info.Size() && current.ModTime().Equal(info.ModTime()) {
+				continue
+			}
+			if err == nil {
+				info = current
+				allowlist, err = LoadAllowlistFile(path)
+			}
+			if err != nil {
+				if onError != nil && err.Error() != reported {
+					onError(err)
+				}
+				reported = err.Error()
+				continue
+			}
+			reported = ""
+			e.SetAllowlist(allowlist)
+		}
+	}()
+
+	return nil
+}
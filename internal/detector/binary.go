@@ -0,0 +1,86 @@
+package detector
+
+import (
+	"context"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minTextRunLength is the minimum number of bytes a printable run must have
+// to be scanned; shorter runs are almost always binary noise
+const minTextRunLength = 6
+
+// textRun is a printable UTF-8 run extracted from binary data
+type textRun struct {
+	// Offset is the byte offset of the run in the binary data
+	Offset int
+
+	// Text is the run content
+	Text string
+}
+
+// extractTextRuns returns the printable UTF-8 runs in data that are at least
+// minLength bytes long. Invalid UTF-8, control characters and line breaks end a run.
+func extractTextRuns(data []byte, minLength int) []textRun {
+	var runs []textRun
+
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end-start >= minLength {
+			runs = append(runs, textRun{Offset: start, Text: string(data[start:end])})
+		}
+		start = -1
+	}
+
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		printable := !(r == utf8.RuneError && size <= 1) && (unicode.IsPrint(r) || r == '\t')
+
+		if printable {
+			if start < 0 {
+				start = i
+			}
+		} else {
+			flush(i)
+		}
+		i += size
+	}
+	flush(len(data))
+
+	return runs
+}
+
+// DetectInBinary scans binary data, such as protobuf or other length-prefixed
+// logs, for PII using only enabled patterns. Detection runs on the printable text
+// runs embedded in data, and positions are byte offsets into data.
+func (e *Engine) DetectInBinary(ctx context.Context, data []byte) ([]DetectionResult, error) {
+	return e.detectInRuns(ctx, data, e.DetectInText)
+}
+
+// DetectInBinaryWithPatterns scans binary data using only specified patterns
+func (e *Engine) DetectInBinaryWithPatterns(ctx context.Context, data []byte, patternNames []string) ([]DetectionResult, error) {
+	return e.detectInRuns(ctx, data, func(ctx context.Context, text string) ([]DetectionResult, error) {
+		return e.DetectWithPatterns(ctx, text, patternNames)
+	})
+}
+
+// detectInRuns applies detect to every text run in data and shifts the
+// resulting positions to binary offsets
+func (e *Engine) detectInRuns(ctx context.Context, data []byte, detect func(context.Context, string) ([]DetectionResult, error)) ([]DetectionResult, error) {
+	var results []DetectionResult
+
+	for _, run := range extractTextRuns(data, minTextRunLength) {
+		detections, err := detect(ctx, run.Text)
+		if err != nil {
+			return results, err
+		}
+
+		for _, d := range detections {
+			d.Position.Start += run.Offset
+			d.Position.End += run.Offset
+			results = append(results, d)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,124 @@
+package detector
+
+import (
+	"sort"
+	"strings"
+)
+
+// CommentAction is what happens to matches on comment lines
+type CommentAction string
+
+const (
+	// CommentSkip drops matches on comment lines
+	CommentSkip CommentAction = "skip"
+
+	// CommentDowngrade lowers the confidence of matches on comment lines by
+	// one level, so that a minimum confidence or redaction confidence can
+	// tell them apart
+	CommentDowngrade CommentAction = "downgrade"
+)
+
+// CommentFilter treats matches on comment lines of source or configuration
+// files, which are often intentional examples, as noise. A line is a comment
+// line when it starts with one of the prefixes after leading whitespace.
+type CommentFilter struct {
+	// Prefixes start comment lines, e.g. "#" and "//". Empty prefixes are
+	// ignored.
+	Prefixes []string
+
+	// Action is what happens to matches on comment lines; empty means
+	// CommentSkip
+	Action CommentAction
+}
+
+// SetCommentFilter sets the filter applied to matches on comment lines. A
+// nil filter, or one without prefixes, treats every line alike.
+func (e *Engine) SetCommentFilter(filter *CommentFilter) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.comments = nil
+	if filter == nil {
+		return
+	}
+	comments := &CommentFilter{Action: filter.Action}
+	for _, prefix := range filter.Prefixes {
+		if prefix != "" {
+			comments.Prefixes = append(comments.Prefixes, prefix)
+		}
+	}
+	if len(comments.Prefixes) > 0 {
+		e.comments = comments
+	}
+}
+
+// filterComments drops or downgrades the results on comment lines of text
+func (e *Engine) filterComments(text string, results []DetectionResult) []DetectionResult {
+	if e.comments == nil || len(results) == 0 {
+		return results
+	}
+
+	lines := newLineIndex(text)
+	kept := results[:0]
+	for _, r := range results {
+		if !e.comments.isComment(lines.line(r.Position.Start)) {
+			kept = append(kept, r)
+			continue
+		}
+		if e.comments.Action == CommentDowngrade {
+			r.Confidence = downgradeConfidence(r.Confidence)
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// isComment reports whether line is a comment line
+func (f *CommentFilter) isComment(line string) bool {
+	line = strings.TrimLeft(line, " \t")
+	for _, prefix := range f.Prefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// downgradeConfidence returns the confidence level below confidence. Low
+// and unknown levels are kept.
+func downgradeConfidence(confidence string) string {
+	switch confidence {
+	case "high":
+		return "medium"
+	case "medium":
+		return "low"
+	}
+	return confidence
+}
+
+// lineIndex maps byte offsets of a text to the lines containing them
+type lineIndex struct {
+	text   string
+	starts []int
+}
+
+// newLineIndex indexes the line starts of text
+func newLineIndex(text string) *lineIndex {
+	starts := []int{0}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &lineIndex{text: text, starts: starts}
+}
+
+// line returns the line containing the byte at offset, without its newline
+func (l *lineIndex) line(offset int) string {
+	i := sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > offset }) - 1
+	end := len(l.text)
+	if i+1 < len(l.starts) {
+		end = l.starts[i+1] - 1
+	}
+	return l.text[l.starts[i]:end]
+}
@@ -0,0 +1,128 @@
+package detector
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// confidenceRank orders confidence levels from least to most confident
+var confidenceRank = map[string]int{"low": 1, "medium": 2, "high": 3}
+
+// promoteConfidence returns the more confident of two confidence levels
+func promoteConfidence(confidence, candidate string) string {
+	if confidenceRank[candidate] > confidenceRank[confidence] {
+		return candidate
+	}
+	return confidence
+}
+
+// IsConfidenceLevel reports whether confidence is low, medium or high
+func IsConfidenceLevel(confidence string) bool {
+	_, ok := confidenceRank[confidence]
+	return ok
+}
+
+// ConfidenceAtLeast reports whether confidence is at least as confident as
+// threshold. Unknown levels rank below low.
+func ConfidenceAtLeast(confidence, threshold string) bool {
+	return confidenceRank[confidence] >= confidenceRank[threshold]
+}
+
+// componentMatch is a match of one component of a composite pattern
+type componentMatch struct {
+	component int
+	result    DetectionResult
+}
+
+// validateComposite checks the composite settings of a pattern specification
+func validateComposite(name string, spec patterns.PIIPatternSpec) error {
+	if len(spec.RequiresAll) == 1 {
+		return fmt.Errorf("composite pattern %s needs at least two components", name)
+	}
+	if spec.Window < 0 {
+		return fmt.Errorf("composite pattern %s has a negative window", name)
+	}
+	return nil
+}
+
+// matchComposite returns a combined detection for each span of the input in
+// which every component of a composite pattern matches. Components are
+// matched whether or not they are enabled; a missing or composite component
+// never matches. Spans do not overlap. The caller must hold e.mu.
+func (e *Engine) matchComposite(pattern *CompiledPattern, input *normalizedText, explain bool) []DetectionResult {
+	var matches []componentMatch
+	for i, name := range pattern.RequiresAll {
+		component, ok := e.patterns[name]
+		if !ok || len(component.RequiresAll) > 0 {
+			return nil
+		}
+
+		found := e.matchPattern(component, input, false)
+		if len(found) == 0 {
+			return nil
+		}
+		for _, result := range found {
+			matches = append(matches, componentMatch{component: i, result: result})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].result.Position.Start < matches[j].result.Position.Start
+	})
+
+	var results []DetectionResult
+	next := 0
+	for l := range matches {
+		start := matches[l].result.Position.Start
+		if start < next {
+			continue
+		}
+
+		seen := make([]bool, len(pattern.RequiresAll))
+		remaining := len(pattern.RequiresAll)
+		end := start
+		confidence := ""
+		for _, m := range matches[l:] {
+			end = max(end, m.result.Position.End)
+			if pattern.Window > 0 && end-start > pattern.Window {
+				break
+			}
+			if !seen[m.component] {
+				seen[m.component] = true
+				remaining--
+			}
+			if confidence == "" || confidenceRank[m.result.Confidence] < confidenceRank[confidence] {
+				confidence = m.result.Confidence
+			}
+			if remaining > 0 {
+				continue
+			}
+
+			results = append(results, DetectionResult{
+				PatternName: pattern.Name,
+				DisplayName: pattern.DisplayName,
+				MatchedText: input.original[start:end],
+				Position: Position{
+					Start: start,
+					End:   end,
+				},
+				Confidence: confidence,
+				Severity:   pattern.Severity,
+			})
+			if explain {
+				results[len(results)-1].Explanation = &Explanation{
+					RuleConfidence: confidence,
+					Validation:     ValidationNotRun,
+					Components:     slices.Clone(pattern.RequiresAll),
+				}
+			}
+			next = end
+			break
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,64 @@
+package detector
+
+import (
+	"context"
+	"sync"
+)
+
+// SetConcurrency sets the number of patterns matched at once by a single
+// detection call. Values below 2 match patterns one after another.
+func (e *Engine) SetConcurrency(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.concurrency = n
+}
+
+// matchPatterns matches the patterns against the input, on up to the
+// engine's concurrency of goroutines, and returns the detections sorted by
+// position. When ctx is done it returns the detections found so far with
+// the context's error. The caller holds the read lock.
+func (e *Engine) matchPatterns(ctx context.Context, selected []*CompiledPattern, input *normalizedText, explain bool) ([]DetectionResult, error) {
+	var results []DetectionResult
+	var err error
+
+	if workers := min(e.concurrency, len(selected)); workers < 2 {
+		for _, pattern := range selected {
+			if err = ctx.Err(); err != nil {
+				break
+			}
+			results = append(results, e.matchPattern(pattern, input, explain)...)
+		}
+	} else {
+		// Each pattern has its own slot, so the workers share nothing
+		matches := make([][]DetectionResult, len(selected))
+		next := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range next {
+					matches[i] = e.matchPattern(selected[i], input, explain)
+				}
+			}()
+		}
+	feed:
+		for i := range selected {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break feed
+			case next <- i:
+			}
+		}
+		close(next)
+		wg.Wait()
+
+		for _, m := range matches {
+			results = append(results, m...)
+		}
+	}
+
+	SortDetections(results)
+	return results, err
+}
@@ -0,0 +1,75 @@
+package detector
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// context returns the keywords required near matches of the rule and the
+// number of characters around a match they are searched in. The rule's own
+// keywords and window take precedence over the pattern's.
+func (r *compiledRule) context(pattern *CompiledPattern) ([]string, int) {
+	keywords := r.ContextKeywords
+	if len(keywords) == 0 {
+		keywords = pattern.ContextKeywords
+	}
+	window := r.ContextWindow
+	if window <= 0 {
+		window = pattern.ContextWindow
+	}
+	if window <= 0 {
+		window = patterns.DefaultContextWindow
+	}
+	return keywords, window
+}
+
+// hasContext reports whether one of the keywords starts a word within window
+// characters before or after text[start:end]
+func hasContext(text string, start, end int, keywords []string, window int) bool {
+	from := start
+	for n := 0; n < window && from > 0; n++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	to := end
+	for n := 0; n < window && to < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+	return containsKeyword(text, from, start, keywords) || containsKeyword(text, end, to, keywords)
+}
+
+// containsKeyword reports whether one of the keywords lies within
+// text[from:to] at the start of a word, compared without regard to case.
+// Starting a word keeps "aba" from being found in "database".
+func containsKeyword(text string, from, to int, keywords []string) bool {
+	for i := from; i < to; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if startsWord(text, i, r) {
+			for _, keyword := range keywords {
+				if j := i + len(keyword); keyword != "" && j <= to && strings.EqualFold(text[i:j], keyword) {
+					return true
+				}
+			}
+		}
+		i += size
+	}
+	return false
+}
+
+// startsWord reports whether r at offset i of text starts a word: it follows
+// a character that is neither a letter nor a digit, or is the capital
+// letter starting the next part of a camelCase word such as bankRouting
+func startsWord(text string, i int, r rune) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(r)
+}
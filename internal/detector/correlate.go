@@ -0,0 +1,93 @@
+package detector
+
+const (
+	awsAccessKeyPattern       = "aws-access-key"
+	awsSecretKeyPattern       = "aws-secret-key"
+	awsSecretCandidatePattern = "aws-secret-key-entropy"
+
+	// awsCorrelationWindow is the maximum distance in bytes between an AWS access
+	// key and a secret key candidate for the two to be treated as a credential pair
+	awsCorrelationWindow = 256
+)
+
+// correlateAWSSecrets cross-references keyword-free AWS secret key candidates with
+// AWS access keys in the same text. Candidates near an access key are raised to high
+// confidence, and candidates already covered by a keyword-based match are dropped.
+func (e *Engine) correlateAWSSecrets(input *normalizedText, results []DetectionResult) []DetectionResult {
+	hasCandidate := false
+	var keywordMatches []Position
+	for _, r := range results {
+		switch r.PatternName {
+		case awsSecretCandidatePattern:
+			hasCandidate = true
+		case awsSecretKeyPattern:
+			keywordMatches = append(keywordMatches, r.Position)
+		}
+	}
+	if !hasCandidate {
+		return results
+	}
+
+	accessKeys := e.findAccessKeys(input)
+
+	filtered := results[:0]
+	for _, r := range results {
+		if r.PatternName == awsSecretCandidatePattern {
+			if overlapsAny(r.Position, keywordMatches) {
+				continue
+			}
+			if withinWindow(r.Position, accessKeys, awsCorrelationWindow) {
+				if r.Explanation != nil && r.Confidence != "high" {
+					r.Explanation.PromotedBy = awsAccessKeyPattern
+				}
+				r.Confidence = "high"
+			}
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
+// findAccessKeys returns the positions of AWS access keys in the original text,
+// regardless of which patterns were requested for detection
+func (e *Engine) findAccessKeys(input *normalizedText) []Position {
+	pattern, ok := e.patterns[awsAccessKeyPattern]
+	if !ok {
+		return nil
+	}
+
+	var positions []Position
+	for _, rule := range pattern.Patterns {
+		for _, match := range rule.Regex.FindAllStringIndex(input.text, -1) {
+			start, end := input.originalSpan(match[0], match[1])
+			positions = append(positions, Position{Start: start, End: end})
+		}
+	}
+	return positions
+}
+
+// overlapsAny reports whether pos overlaps any of the given positions
+func overlapsAny(pos Position, others []Position) bool {
+	for _, o := range others {
+		if pos.Start < o.End && o.Start < pos.End {
+			return true
+		}
+	}
+	return false
+}
+
+// withinWindow reports whether pos lies within window bytes of any of the given positions
+func withinWindow(pos Position, others []Position, window int) bool {
+	for _, o := range others {
+		gap := 0
+		if pos.Start >= o.End {
+			gap = pos.Start - o.End
+		} else if o.Start >= pos.End {
+			gap = o.Start - pos.End
+		}
+		if gap <= window {
+			return true
+		}
+	}
+	return false
+}
@@ -3,13 +3,20 @@ package detector
 import (
 	"context"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 	"github.com/bunseokbot/pii-redactor/internal/detector/validator"
 )
 
-// Position represents the position of a match in the text
+// Position represents the position of a match in the text as UTF-8 byte
+// offsets. Detection and redaction always use byte offsets; use
+// ConvertOffsets to report rune or UTF-16 offsets.
 type Position struct {
 	Start int
 	End   int
@@ -30,6 +37,52 @@ type DetectionResult struct {
 	Confidence   string
 	Severity     string
 	RedactedText string
+
+	// Metadata holds attributes derived from the match by the pattern's
+	// validator, e.g. residency, century and gender for Korean RRNs, and
+	// the values of the regex's named capture groups
+	Metadata map[string]string
+
+	// Explanation records how the detection came about. It is only set in
+	// explain mode.
+	Explanation *Explanation `json:",omitempty"`
+}
+
+// Validation outcomes reported in an Explanation
+const (
+	ValidationPassed  = "passed"
+	ValidationSkipped = "skipped"
+	ValidationNotRun  = "none"
+)
+
+// Explanation is the provenance of a detection, for tuning patterns
+type Explanation struct {
+	// Rule is the index of the matching rule within the pattern
+	Rule int
+
+	// Regex is the regular expression of the matching rule
+	Regex string
+
+	// RuleConfidence is the confidence of the matching rule, before any
+	// promotion
+	RuleConfidence string
+
+	// Validator is the name of the pattern's validator, if it has one
+	Validator string
+
+	// Validation is ValidationPassed when the validator accepted the match,
+	// ValidationSkipped when validation is disabled and ValidationNotRun
+	// when the pattern has no validator. Matches a validator rejects are
+	// not reported.
+	Validation string
+
+	// PromotedBy names what raised the confidence above RuleConfidence:
+	// the validator, or the AWS access key the secret key was found near
+	PromotedBy string `json:",omitempty"`
+
+	// Components are the patterns whose matches a composite detection
+	// combines
+	Components []string `json:",omitempty"`
 }
 
 // LogEntry represents a log entry to be processed
@@ -46,32 +99,78 @@ type CompiledPattern struct {
 	Name            string
 	DisplayName     string
 	Category        string
+	Tags            []string
 	Patterns        []*compiledRule
 	Validator       string
 	MaskingStrategy patterns.MaskingStrategy
 	Severity        string
 	Enabled         bool
+
+	// ConfidenceMasking holds alternate masking strategies keyed by confidence
+	ConfidenceMasking map[string]patterns.MaskingStrategy
+
+	// SeparatorInsensitive matches against input with digit separators removed
+	SeparatorInsensitive bool
+
+	// RejectTrivialNumbers drops repeated or sequential digit matches
+	RejectTrivialNumbers bool
+
+	// MinLength drops matches shorter than this many characters
+	MinLength int
+
+	// RequiresAll names the components of a composite pattern
+	RequiresAll []string
+
+	// Window is the largest span a composite match may cover
+	Window int
+
+	// MaxMatchLength bounds the length of a match; zero derives it from the rules
+	MaxMatchLength int
+
+	// ContextKeywords and ContextWindow require a keyword near each match
+	ContextKeywords []string
+	ContextWindow   int
+
+	// SensitiveGroups are capture groups kept out of detection metadata
+	SensitiveGroups []string
 }
 
 type compiledRule struct {
 	Regex      *regexp.Regexp
 	Confidence string
+
+	// ContextKeywords and ContextWindow override those of the pattern
+	ContextKeywords []string
+	ContextWindow   int
 }
 
 // Engine is the main PII detection engine
 type Engine struct {
-	patterns          map[string]*CompiledPattern
-	validators        map[string]validator.Validator
-	validationEnabled bool
-	mu                sync.RWMutex
+	patterns             map[string]*CompiledPattern
+	validationEnabled    bool
+	normalizationEnabled bool
+	evasionHardening     bool
+	allowTrivialNumbers  bool
+	explain              bool
+	allowlist            *Allowlist
+	comments             *CommentFilter
+	minSeverity          string
+	minConfidence        string
+	severities           patterns.SeverityScale
+	eventSink            chan<- DetectionResult
+	drops                *dropCounters
+	concurrency          int
+	readerOverlap        int
+	maxMatches           int
+	mu                   sync.RWMutex
 }
 
 // NewEngine creates a new detection engine
 func NewEngine() *Engine {
 	e := &Engine{
 		patterns:          make(map[string]*CompiledPattern),
-		validators:        validator.Registry,
 		validationEnabled: true,
+		drops:             new(dropCounters),
 	}
 
 	// Load built-in patterns
@@ -80,6 +179,40 @@ func NewEngine() *Engine {
 	return e
 }
 
+// Snapshot returns an independent copy of the engine. Pattern state such as
+// enablement, severity and masking can be changed on the copy without affecting
+// the original; compiled regular expressions, the event sink and the counters
+// reported by Stats are shared.
+func (e *Engine) Snapshot() *Engine {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	snapshot := &Engine{
+		patterns:             make(map[string]*CompiledPattern, len(e.patterns)),
+		validationEnabled:    e.validationEnabled,
+		normalizationEnabled: e.normalizationEnabled,
+		evasionHardening:     e.evasionHardening,
+		allowTrivialNumbers:  e.allowTrivialNumbers,
+		explain:              e.explain,
+		allowlist:            e.allowlist,
+		comments:             e.comments,
+		minSeverity:          e.minSeverity,
+		minConfidence:        e.minConfidence,
+		severities:           e.severities,
+		eventSink:            e.eventSink,
+		drops:                e.drops,
+		concurrency:          e.concurrency,
+		readerOverlap:        e.readerOverlap,
+		maxMatches:           e.maxMatches,
+	}
+	for name, pattern := range e.patterns {
+		patternCopy := *pattern
+		snapshot.patterns[name] = &patternCopy
+	}
+
+	return snapshot
+}
+
 // DisableValidation disables checksum validation for all patterns
 func (e *Engine) DisableValidation() {
 	e.mu.Lock()
@@ -94,18 +227,70 @@ func (e *Engine) EnableValidation() {
 	e.validationEnabled = true
 }
 
+// EnableNormalization enables Unicode NFC normalization of input before matching.
+// Zero-width characters are dropped as part of normalization. Detection positions
+// always refer to the original, unnormalized text.
+func (e *Engine) EnableNormalization() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.normalizationEnabled = true
+}
+
+// DisableNormalization disables input normalization
+func (e *Engine) DisableNormalization() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.normalizationEnabled = false
+}
+
+// SetEvasionHardening enables or disables stripping of zero-width characters and
+// folding of Cyrillic/Greek/fullwidth homoglyphs to ASCII before matching.
+// Detection positions always refer to the original text.
+func (e *Engine) SetEvasionHardening(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.evasionHardening = enabled
+}
+
+// SetTrivialNumberFilter enables or disables dropping matches of patterns
+// with RejectTrivialNumbers whose digits are repeated or sequential, such as
+// 111-11-1111. The filter is enabled by default and, like checksums, only
+// applies while validation is enabled.
+func (e *Engine) SetTrivialNumberFilter(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.allowTrivialNumbers = !enabled
+}
+
+// SetExplain enables or disables explain mode, in which every detection
+// carries an Explanation of the rule and validation that produced it
+func (e *Engine) SetExplain(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.explain = enabled
+}
+
 // loadBuiltInPatterns loads all built-in patterns
 func (e *Engine) loadBuiltInPatterns() {
 	for name, spec := range patterns.BuiltInPatterns {
 		compiled := &CompiledPattern{
-			Name:            name,
-			DisplayName:     spec.DisplayName,
-			Category:        spec.Category,
-			Validator:       spec.Validator,
-			MaskingStrategy: spec.MaskingStrategy,
-			Severity:        spec.Severity,
-			Enabled:         spec.Enabled,
-			Patterns:        make([]*compiledRule, 0, len(spec.Patterns)),
+			Name:                 name,
+			DisplayName:          spec.DisplayName,
+			Category:             spec.Category,
+			Tags:                 spec.Tags,
+			Validator:            spec.Validator,
+			MaskingStrategy:      spec.MaskingStrategy,
+			ConfidenceMasking:    spec.ConfidenceMasking,
+			SeparatorInsensitive: spec.SeparatorInsensitive,
+			RejectTrivialNumbers: spec.RejectTrivialNumbers,
+			MinLength:            spec.MinLength,
+			MaxMatchLength:       spec.MaxMatchLength,
+			ContextKeywords:      spec.ContextKeywords,
+			ContextWindow:        spec.ContextWindow,
+			SensitiveGroups:      spec.SensitiveGroups,
+			Severity:             spec.Severity,
+			Enabled:              spec.Enabled,
+			Patterns:             make([]*compiledRule, 0, len(spec.Patterns)),
 		}
 
 		for _, p := range spec.Patterns {
@@ -114,8 +299,10 @@ func (e *Engine) loadBuiltInPatterns() {
 				continue // Skip invalid patterns
 			}
 			compiled.Patterns = append(compiled.Patterns, &compiledRule{
-				Regex:      re,
-				Confidence: p.Confidence,
+				Regex:           re,
+				Confidence:      p.Confidence,
+				ContextKeywords: p.ContextKeywords,
+				ContextWindow:   p.ContextWindow,
 			})
 		}
 
@@ -123,33 +310,95 @@ func (e *Engine) loadBuiltInPatterns() {
 	}
 }
 
+// NamedPatternSpec pairs a pattern specification with the name it is registered under
+type NamedPatternSpec struct {
+	Name string
+	Spec patterns.PIIPatternSpec
+}
+
 // AddPattern adds a custom pattern to the engine
 func (e *Engine) AddPattern(name string, spec patterns.PIIPatternSpec) error {
+	compiled, err := compilePattern(name, spec)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.patterns[name] = compiled
+	e.mu.Unlock()
+
+	return nil
+}
+
+// AddPatterns adds a batch of custom patterns to the engine. All patterns are
+// compiled before the engine is locked, and the valid ones are registered under
+// a single write lock. Patterns that fail to compile are skipped and returned
+// keyed by name; the result is nil when every pattern was added.
+func (e *Engine) AddPatterns(specs []NamedPatternSpec) map[string]error {
+	var failed map[string]error
+	compiled := make([]*CompiledPattern, 0, len(specs))
+
+	for _, named := range specs {
+		pattern, err := compilePattern(named.Name, named.Spec)
+		if err != nil {
+			if failed == nil {
+				failed = make(map[string]error)
+			}
+			failed[named.Name] = err
+			continue
+		}
+		compiled = append(compiled, pattern)
+	}
+
+	e.mu.Lock()
+	for _, pattern := range compiled {
+		e.patterns[pattern.Name] = pattern
+	}
+	e.mu.Unlock()
+
+	return failed
+}
+
+// compilePattern compiles the rules of a custom pattern specification
+func compilePattern(name string, spec patterns.PIIPatternSpec) (*CompiledPattern, error) {
 	compiled := &CompiledPattern{
-		Name:            name,
-		DisplayName:     spec.DisplayName,
-		Validator:       spec.Validator,
-		MaskingStrategy: spec.MaskingStrategy,
-		Severity:        spec.Severity,
-		Patterns:        make([]*compiledRule, 0, len(spec.Patterns)),
+		Name:                 name,
+		DisplayName:          spec.DisplayName,
+		Tags:                 spec.Tags,
+		Validator:            spec.Validator,
+		MaskingStrategy:      spec.MaskingStrategy,
+		ConfidenceMasking:    spec.ConfidenceMasking,
+		SeparatorInsensitive: spec.SeparatorInsensitive,
+		RejectTrivialNumbers: spec.RejectTrivialNumbers,
+		MinLength:            spec.MinLength,
+		RequiresAll:          spec.RequiresAll,
+		Window:               spec.Window,
+		MaxMatchLength:       spec.MaxMatchLength,
+		ContextKeywords:      spec.ContextKeywords,
+		ContextWindow:        spec.ContextWindow,
+		SensitiveGroups:      spec.SensitiveGroups,
+		Severity:             spec.Severity,
+		Patterns:             make([]*compiledRule, 0, len(spec.Patterns)),
+	}
+
+	if err := validateComposite(name, spec); err != nil {
+		return nil, err
 	}
 
 	for _, p := range spec.Patterns {
 		re, err := regexp.Compile(p.Regex)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		compiled.Patterns = append(compiled.Patterns, &compiledRule{
-			Regex:      re,
-			Confidence: p.Confidence,
+			Regex:           re,
+			Confidence:      p.Confidence,
+			ContextKeywords: p.ContextKeywords,
+			ContextWindow:   p.ContextWindow,
 		})
 	}
 
-	e.mu.Lock()
-	e.patterns[name] = compiled
-	e.mu.Unlock()
-
-	return nil
+	return compiled, nil
 }
 
 // RemovePattern removes a pattern from the engine
@@ -159,6 +408,93 @@ func (e *Engine) RemovePattern(name string) {
 	e.mu.Unlock()
 }
 
+// SetMinSeverity limits detection to patterns at or above the given severity.
+// An empty severity scans patterns of every severity.
+func (e *Engine) SetMinSeverity(severity string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.minSeverity = severity
+}
+
+// SetMinConfidence limits detection to matches at or above the given
+// confidence, so that noisy rules can be suppressed without disabling their
+// patterns. Matches are judged by their confidence after any promotion by
+// validators or correlation. An empty level reports matches of every
+// confidence.
+func (e *Engine) SetMinConfidence(level string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.minConfidence = level
+}
+
+// filterConfidence drops the results below the minimum confidence
+func (e *Engine) filterConfidence(results []DetectionResult) []DetectionResult {
+	if e.minConfidence == "" {
+		return results
+	}
+
+	kept := results[:0]
+	for _, r := range results {
+		if ConfidenceAtLeast(r.Confidence, e.minConfidence) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// SetSeverityScale ranks severities for the minimum severity filter. Patterns
+// with a severity missing from the scale rank below every listed one. A nil
+// scale restores the default critical, high, medium and low scale.
+func (e *Engine) SetSeverityScale(scale patterns.SeverityScale) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.severities = scale
+}
+
+// belowMinSeverity reports whether a pattern is excluded by the minimum severity
+func (e *Engine) belowMinSeverity(pattern *CompiledPattern) bool {
+	return !e.severities.AtLeast(pattern.Severity, e.minSeverity)
+}
+
+// SetEventSink publishes every detection to ch as it is found. Sends never
+// block detection: when ch is full the detection is dropped and counted in
+// DroppedEvents. A nil channel disables publishing.
+func (e *Engine) SetEventSink(ch chan<- DetectionResult) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.eventSink = ch
+}
+
+// DroppedEvents returns the number of detections not published because the
+// event sink was full
+func (e *Engine) DroppedEvents() int64 {
+	return e.drops.events.Load()
+}
+
+// publish sends the results to the event sink without blocking. The caller
+// must hold e.mu.
+func (e *Engine) publish(results []DetectionResult) []DetectionResult {
+	if e.eventSink == nil {
+		return results
+	}
+	for _, result := range results {
+		select {
+		case e.eventSink <- result:
+		default:
+			e.drops.events.Add(1)
+		}
+	}
+	return results
+}
+
+// publishResults sends the results to the event sink like publish, taking
+// the lock itself
+func (e *Engine) publishResults(results []DetectionResult) []DetectionResult {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.publish(results)
+}
+
 // Detect scans the log entry for PII
 func (e *Engine) Detect(ctx context.Context, log LogEntry) ([]DetectionResult, error) {
 	return e.DetectInText(ctx, log.Message)
@@ -166,104 +502,292 @@ func (e *Engine) Detect(ctx context.Context, log LogEntry) ([]DetectionResult, e
 
 // DetectInText scans text for PII using only enabled patterns
 func (e *Engine) DetectInText(ctx context.Context, text string) ([]DetectionResult, error) {
-	var results []DetectionResult
+	return e.detectInText(ctx, text, false)
+}
+
+// DetectWithExplain scans text like DetectInText and explains every
+// detection, whether or not explain mode is enabled
+func (e *Engine) DetectWithExplain(ctx context.Context, text string) ([]DetectionResult, error) {
+	return e.detectInText(ctx, text, true)
+}
+
+// detectInText scans text using only enabled patterns, explaining the
+// detections in explain mode or when forceExplain is set, and publishes the
+// detections to the event sink
+func (e *Engine) detectInText(ctx context.Context, text string, forceExplain bool) ([]DetectionResult, error) {
+	results, err := e.scanText(ctx, text, forceExplain)
+	if err != nil {
+		return results, err
+	}
+	return e.publishResults(results), nil
+}
+
+// scanText implements detectInText without publishing the detections, for
+// callers such as DetectInReader that publish them once their positions are
+// final
+func (e *Engine) scanText(ctx context.Context, text string, forceExplain bool) ([]DetectionResult, error) {
+	if isBlank(text) {
+		return nil, nil
+	}
 
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
+	input := e.prepareInput(text)
+	explain := forceExplain || e.explain
+
+	selected := make([]*CompiledPattern, 0, len(e.patterns))
 	for _, pattern := range e.patterns {
 		// Skip disabled patterns
-		if !pattern.Enabled {
+		if !pattern.Enabled || e.belowMinSeverity(pattern) {
 			continue
 		}
+		selected = append(selected, pattern)
+	}
 
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
-		}
-
-		for _, rule := range pattern.Patterns {
-			matches := rule.Regex.FindAllStringIndex(text, -1)
-			for _, match := range matches {
-				matchedText := text[match[0]:match[1]]
-
-				// Validate if validator is specified and validation is enabled
-				if e.validationEnabled && pattern.Validator != "" {
-					if v, ok := e.validators[pattern.Validator]; ok {
-						if !v.Validate(matchedText) {
-							continue
-						}
-					}
-				}
-
-				results = append(results, DetectionResult{
-					PatternName: pattern.Name,
-					DisplayName: pattern.DisplayName,
-					MatchedText: matchedText,
-					Position: Position{
-						Start: match[0],
-						End:   match[1],
-					},
-					Confidence: rule.Confidence,
-					Severity:   pattern.Severity,
-				})
-			}
-		}
+	results, err := e.matchPatterns(ctx, selected, input, explain)
+	if err != nil {
+		return results, err
 	}
 
-	return results, nil
+	results = e.filterComments(input.original, e.correlateAWSSecrets(input, results))
+	return e.filterAllowed(e.filterConfidence(results)), nil
 }
 
 // DetectWithPatterns scans text using only specified patterns
 func (e *Engine) DetectWithPatterns(ctx context.Context, text string, patternNames []string) ([]DetectionResult, error) {
-	var results []DetectionResult
+	if isBlank(text) {
+		return nil, nil
+	}
 
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
+	input := e.prepareInput(text)
+
+	selected := make([]*CompiledPattern, 0, len(patternNames))
 	for _, name := range patternNames {
 		pattern, ok := e.patterns[name]
-		if !ok {
+		if !ok || e.belowMinSeverity(pattern) {
 			continue
 		}
+		selected = append(selected, pattern)
+	}
 
-		select {
-		case <-ctx.Done():
-			return results, ctx.Err()
-		default:
-		}
+	results, err := e.matchPatterns(ctx, selected, input, e.explain)
+	if err != nil {
+		return results, err
+	}
+
+	results = e.filterComments(input.original, e.correlateAWSSecrets(input, results))
+	return e.publish(e.filterAllowed(e.filterConfidence(results))), nil
+}
+
+// DetectWithTags scans text using only the patterns carrying any of the
+// tags, whether or not they are enabled
+func (e *Engine) DetectWithTags(ctx context.Context, text string, tags []string) ([]DetectionResult, error) {
+	return e.DetectWithPatterns(ctx, text, e.ListPatternsByTags(tags))
+}
+
+// isBlank reports whether text is empty or whitespace only, which no
+// pattern is meant to match
+func isBlank(text string) bool {
+	return strings.TrimSpace(text) == ""
+}
 
-		for _, rule := range pattern.Patterns {
-			matches := rule.Regex.FindAllStringIndex(text, -1)
-			for _, match := range matches {
-				matchedText := text[match[0]:match[1]]
-
-				// Validate if validator is specified and validation is enabled
-				if e.validationEnabled && pattern.Validator != "" {
-					if v, ok := e.validators[pattern.Validator]; ok {
-						if !v.Validate(matchedText) {
-							continue
-						}
+// prepareInput returns the text to match against, normalized if enabled
+func (e *Engine) prepareInput(text string) *normalizedText {
+	if e.normalizationEnabled || e.evasionHardening {
+		return normalizeText(text, normalizeOptions{
+			nfc:            e.normalizationEnabled,
+			foldHomoglyphs: e.evasionHardening,
+		})
+	}
+	return identityText(text)
+}
+
+// matchPattern runs all rules of a pattern against the input and returns
+// detections with positions relative to the original text, explained if
+// explain is set
+func (e *Engine) matchPattern(pattern *CompiledPattern, input *normalizedText, explain bool) []DetectionResult {
+	if len(pattern.RequiresAll) > 0 {
+		return e.matchComposite(pattern, input, explain)
+	}
+
+	var results []DetectionResult
+
+	if pattern.SeparatorInsensitive {
+		input = input.withoutSeparators()
+	}
+
+	for ruleIndex, rule := range pattern.Patterns {
+		matches := rule.Regex.FindAllStringSubmatchIndex(input.text, -1)
+		for _, match := range matches {
+			// Patterns that can match the empty string, such as a custom
+			// `\d*`, would otherwise report zero-length detections
+			if match[0] == match[1] {
+				continue
+			}
+			matched := input.text[match[0]:match[1]]
+			if pattern.MinLength > 0 && utf8.RuneCountInString(matched) < pattern.MinLength {
+				continue
+			}
+			v, hasValidator := validator.GetValidator(pattern.Validator)
+
+			// Validate if validator is specified and validation is enabled
+			if e.validationEnabled && hasValidator && !v.Validate(matched) {
+				continue
+			}
+			if e.validationEnabled && pattern.RejectTrivialNumbers && !e.allowTrivialNumbers && validator.IsTrivialNumber(matched) {
+				continue
+			}
+
+			start, end := input.originalSpan(match[0], match[1])
+			if keywords, window := rule.context(pattern); len(keywords) > 0 && !hasContext(input.original, start, end, keywords, window) {
+				continue
+			}
+
+			result := DetectionResult{
+				PatternName: pattern.Name,
+				DisplayName: pattern.DisplayName,
+				MatchedText: input.original[start:end],
+				Position: Position{
+					Start: start,
+					End:   end,
+				},
+				Confidence: rule.Confidence,
+				Severity:   pattern.Severity,
+			}
+			if classifier, ok := v.(validator.Classifier); ok {
+				result.Metadata = classifier.Classify(matched)
+			}
+			result.Metadata = namedGroups(rule.Regex, input, match, pattern.sensitiveGroups(rule.Regex), result.Metadata)
+			if explain {
+				result.Explanation = &Explanation{
+					Rule:           ruleIndex,
+					Regex:          rule.Regex.String(),
+					RuleConfidence: rule.Confidence,
+					Validation:     ValidationNotRun,
+				}
+				if hasValidator {
+					result.Explanation.Validator = pattern.Validator
+					result.Explanation.Validation = ValidationSkipped
+					if e.validationEnabled {
+						result.Explanation.Validation = ValidationPassed
 					}
 				}
+			}
+			if rater, ok := v.(validator.ConfidenceRater); ok && e.validationEnabled {
+				result.Confidence = promoteConfidence(result.Confidence, rater.Confidence(matched))
+				if result.Explanation != nil && result.Confidence != rule.Confidence {
+					result.Explanation.PromotedBy = "validator"
+				}
+			}
 
-				results = append(results, DetectionResult{
-					PatternName: pattern.Name,
-					DisplayName: pattern.DisplayName,
-					MatchedText: matchedText,
-					Position: Position{
-						Start: match[0],
-						End:   match[1],
-					},
-					Confidence: rule.Confidence,
-					Severity:   pattern.Severity,
-				})
+			if e.maxMatches > 0 && len(results) == e.maxMatches {
+				e.drops.matchCapHit.Add(1)
+				return results
 			}
+			results = append(results, result)
 		}
 	}
 
-	return results, nil
+	return results
+}
+
+// namedGroups adds the named capture groups that took part in a match to
+// metadata, keeping keys the validator already set and leaving out the
+// sensitive groups, whose values would otherwise escape masking. Values are
+// taken from the original text.
+func namedGroups(re *regexp.Regexp, input *normalizedText, match []int, sensitive map[int]bool, metadata map[string]string) map[string]string {
+	for i, name := range re.SubexpNames() {
+		if name == "" || match[2*i] < 0 || sensitive[i] {
+			continue
+		}
+		if _, exists := metadata[name]; exists {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		start, end := input.originalSpan(match[2*i], match[2*i+1])
+		metadata[name] = input.original[start:end]
+	}
+	return metadata
+}
+
+// sensitiveGroups returns the indexes of the capture groups of re that are
+// kept out of metadata: the pattern's SensitiveGroups and the groups masked
+// by any of its masking strategies
+func (p *CompiledPattern) sensitiveGroups(re *regexp.Regexp) map[int]bool {
+	if re.NumSubexp() == 0 {
+		return nil
+	}
+
+	var sensitive map[int]bool
+	mark := func(group string) {
+		if i := groupIndex(re, group); i > 0 {
+			if sensitive == nil {
+				sensitive = make(map[int]bool)
+			}
+			sensitive[i] = true
+		}
+	}
+	var markStrategy func(strategy patterns.MaskingStrategy)
+	markStrategy = func(strategy patterns.MaskingStrategy) {
+		mark(strategy.Group)
+		for _, step := range strategy.Chain {
+			markStrategy(step)
+		}
+	}
+
+	for _, group := range p.SensitiveGroups {
+		mark(group)
+	}
+	markStrategy(p.MaskingStrategy)
+	for _, strategy := range p.ConfidenceMasking {
+		markStrategy(strategy)
+	}
+	return sensitive
+}
+
+// groupIndex returns the index of a capture group of re given by name or by
+// index, or -1 if re has no such group
+func groupIndex(re *regexp.Regexp, group string) int {
+	if group == "" {
+		return -1
+	}
+	if i := re.SubexpIndex(group); i >= 0 {
+		return i
+	}
+	if n, err := strconv.Atoi(group); err == nil && n >= 1 && n <= re.NumSubexp() {
+		return n
+	}
+	return -1
+}
+
+// CaptureSpan returns the byte span of a capture group, given by name or
+// index, within text matched by a pattern. The pattern's rules are matched
+// against text again; ok is false when no rule matches all of text with the
+// group taking part, e.g. for composite patterns or normalized matches.
+func (e *Engine) CaptureSpan(patternName, text, group string) (start, end int, ok bool) {
+	pattern, exists := e.GetPattern(patternName)
+	if !exists || group == "" {
+		return 0, 0, false
+	}
+
+	for _, rule := range pattern.Patterns {
+		index := groupIndex(rule.Regex, group)
+		if index < 0 {
+			continue
+		}
+		match := rule.Regex.FindStringSubmatchIndex(text)
+		if match == nil || match[0] != 0 || match[1] != len(text) || match[2*index] < 0 {
+			continue
+		}
+		return match[2*index], match[2*index+1], true
+	}
+	return 0, 0, false
 }
 
 // GetPattern returns a compiled pattern by name
@@ -297,6 +821,22 @@ func (e *Engine) GetMaskingStrategy(patternName string) (patterns.MaskingStrateg
 	return patterns.MaskingStrategy{}, false
 }
 
+// GetMaskingStrategyForConfidence returns the masking strategy for a pattern
+// match of the given confidence, falling back to the pattern's default strategy
+func (e *Engine) GetMaskingStrategyForConfidence(patternName, confidence string) (patterns.MaskingStrategy, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	pattern, ok := e.patterns[patternName]
+	if !ok {
+		return patterns.MaskingStrategy{}, false
+	}
+	if strategy, ok := pattern.ConfidenceMasking[confidence]; ok {
+		return strategy, true
+	}
+	return pattern.MaskingStrategy, true
+}
+
 // EnablePattern enables a pattern by name
 func (e *Engine) EnablePattern(name string) bool {
 	e.mu.Lock()
@@ -321,6 +861,62 @@ func (e *Engine) DisablePattern(name string) bool {
 	return false
 }
 
+// EnablePatterns enables the named patterns under a single lock and returns
+// the names that were enabled and the names of unknown patterns
+func (e *Engine) EnablePatterns(names []string) (enabled, missing []string) {
+	return e.setPatternsEnabled(names, true)
+}
+
+// DisablePatterns disables the named patterns under a single lock and returns
+// the names that were disabled and the names of unknown patterns
+func (e *Engine) DisablePatterns(names []string) (disabled, missing []string) {
+	return e.setPatternsEnabled(names, false)
+}
+
+// setPatternsEnabled sets the enablement of the named patterns, partitioning
+// the names into found and missing in the order given
+func (e *Engine) setPatternsEnabled(names []string, enabled bool) (found, missing []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	found = make([]string, 0, len(names))
+	missing = make([]string, 0)
+	for _, name := range names {
+		pattern, ok := e.patterns[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		pattern.Enabled = enabled
+		found = append(found, name)
+	}
+	return found, missing
+}
+
+// SetPatternSeverity sets the severity of a pattern by name
+func (e *Engine) SetPatternSeverity(name, severity string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if pattern, ok := e.patterns[name]; ok {
+		pattern.Severity = severity
+		return true
+	}
+	return false
+}
+
+// SetMaskingStrategy sets the masking strategy of a pattern by name
+func (e *Engine) SetMaskingStrategy(name string, strategy patterns.MaskingStrategy) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if pattern, ok := e.patterns[name]; ok {
+		pattern.MaskingStrategy = strategy
+		return true
+	}
+	return false
+}
+
 // IsPatternEnabled checks if a pattern is enabled
 func (e *Engine) IsPatternEnabled(name string) bool {
 	e.mu.RLock()
@@ -362,6 +958,29 @@ func (e *Engine) DisablePatternsByCategory(category string) int {
 	return count
 }
 
+// WithPatterns runs fn against a snapshot of the engine with exactly the
+// named patterns enabled, so that the scope never changes what the engine or
+// other goroutines detect. Unknown names are ignored.
+//
+// fn is handed the scoped snapshot rather than scanning e itself: enabling
+// the names on e for the duration of fn would leak the temporary pattern set
+// into detections the controller runs on the shared engine meanwhile, and
+// restoring it afterwards could undo enablement changes made concurrently.
+// Because e is never modified, there is nothing to restore when fn returns an
+// error or panics.
+func (e *Engine) WithPatterns(names []string, fn func(scoped *Engine) error) error {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	scoped := e.Snapshot()
+	for name, pattern := range scoped.patterns {
+		pattern.Enabled = wanted[name]
+	}
+	return fn(scoped)
+}
+
 // ListEnabledPatterns returns names of all enabled patterns
 func (e *Engine) ListEnabledPatterns() []string {
 	e.mu.RLock()
@@ -404,6 +1023,66 @@ func (e *Engine) ListPatternsByCategory(category string) []string {
 	return names
 }
 
+// ListPatternsByTag returns the sorted names of patterns carrying the tag
+func (e *Engine) ListPatternsByTag(tag string) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name, pattern := range e.patterns {
+		if slices.Contains(pattern.Tags, tag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListPatternsByTags returns the sorted names of patterns carrying any of the tags
+func (e *Engine) ListPatternsByTags(tags []string) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name, pattern := range e.patterns {
+		for _, tag := range tags {
+			if slices.Contains(pattern.Tags, tag) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UncategorizedCategory groups patterns without a category in CategoryStats
+const UncategorizedCategory = "uncategorized"
+
+// CategoryStats returns the number of patterns loaded in each category and
+// how many of them are currently scanned: enabled and at or above the
+// minimum severity
+func (e *Engine) CategoryStats() map[string]patterns.CategoryStat {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := make(map[string]patterns.CategoryStat)
+	for _, pattern := range e.patterns {
+		category := pattern.Category
+		if category == "" {
+			category = UncategorizedCategory
+		}
+
+		stat := stats[category]
+		stat.Total++
+		if pattern.Enabled && !e.belowMinSeverity(pattern) {
+			stat.Enabled++
+		}
+		stats[category] = stat
+	}
+	return stats
+}
+
 // HasPattern checks if a pattern exists in the engine
 func (e *Engine) HasPattern(name string) bool {
 	e.mu.RLock()
@@ -424,17 +1103,30 @@ func (e *Engine) GetPatternSpec(name string) *patterns.PIIPatternSpec {
 
 	// Convert CompiledPattern back to PIIPatternSpec
 	spec := &patterns.PIIPatternSpec{
-		DisplayName:     pattern.DisplayName,
-		Description:     "",
-		Validator:       pattern.Validator,
-		MaskingStrategy: pattern.MaskingStrategy,
-		Severity:        pattern.Severity,
+		DisplayName:          pattern.DisplayName,
+		Description:          "",
+		Tags:                 pattern.Tags,
+		Validator:            pattern.Validator,
+		MaskingStrategy:      pattern.MaskingStrategy,
+		ConfidenceMasking:    pattern.ConfidenceMasking,
+		SeparatorInsensitive: pattern.SeparatorInsensitive,
+		RejectTrivialNumbers: pattern.RejectTrivialNumbers,
+		MinLength:            pattern.MinLength,
+		RequiresAll:          pattern.RequiresAll,
+		Window:               pattern.Window,
+		MaxMatchLength:       pattern.MaxMatchLength,
+		ContextKeywords:      pattern.ContextKeywords,
+		ContextWindow:        pattern.ContextWindow,
+		SensitiveGroups:      pattern.SensitiveGroups,
+		Severity:             pattern.Severity,
 	}
 
 	for _, rule := range pattern.Patterns {
 		spec.Patterns = append(spec.Patterns, patterns.PatternRule{
-			Regex:      rule.Regex.String(),
-			Confidence: rule.Confidence,
+			Regex:           rule.Regex.String(),
+			Confidence:      rule.Confidence,
+			ContextKeywords: rule.ContextKeywords,
+			ContextWindow:   rule.ContextWindow,
 		})
 	}
 
@@ -0,0 +1,221 @@
+package detector
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// zeroWidthChars are invisible characters commonly inserted to split PII
+// so that it slips past regex matching
+const zeroWidthChars = "\u200B\u200C\u200D\u2060\uFEFF"
+
+// homoglyphs maps common Cyrillic and Greek look-alikes to their ASCII equivalents
+var homoglyphs = map[rune]rune{
+	// Cyrillic lowercase
+	'а': 'a', 'е': 'e', 'к': 'k', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y',
+	'х': 'x', 'ѕ': 's', 'і': 'i', 'ј': 'j', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w',
+	// Cyrillic uppercase
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I',
+	'Ј': 'J',
+	// Greek
+	'α': 'a', 'ο': 'o', 'ν': 'v', 'ι': 'i', 'κ': 'k', 'ρ': 'p',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K',
+	'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// normalizeOptions controls which transformations normalizeText applies
+type normalizeOptions struct {
+	// nfc applies Unicode NFC composition
+	nfc bool
+
+	// foldHomoglyphs maps look-alike characters to ASCII
+	foldHomoglyphs bool
+}
+
+// normalizedText holds the text used for matching together with a mapping
+// from its byte offsets back to the original input
+type normalizedText struct {
+	// original is the unmodified input
+	original string
+
+	// text is the text patterns are matched against
+	text string
+
+	// starts and ends give, for each byte of text, the byte range in
+	// original that produced it. Both are nil when text == original.
+	starts []int
+	ends   []int
+
+	// withoutSeps caches the separator-stripped copy of text, which
+	// patterns matched concurrently may ask for at the same time
+	withoutSeps     *normalizedText
+	withoutSepsOnce sync.Once
+}
+
+// identityText wraps text that needs no normalization
+func identityText(text string) *normalizedText {
+	return &normalizedText{original: text, text: text}
+}
+
+// normalizeText drops zero-width characters and applies the optional NFC and
+// homoglyph transformations, recording the original byte range of every output byte
+func normalizeText(text string, opts normalizeOptions) *normalizedText {
+	if !needsNormalization(text, opts) {
+		return identityText(text)
+	}
+
+	n := &normalizedText{
+		original: text,
+		starts:   make([]int, 0, len(text)),
+		ends:     make([]int, 0, len(text)),
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	emit := func(segment []byte, start, end int) {
+		for len(segment) > 0 {
+			r, size := utf8.DecodeRune(segment)
+			segment = segment[size:]
+
+			if strings.ContainsRune(zeroWidthChars, r) {
+				continue
+			}
+			if opts.foldHomoglyphs {
+				r = foldHomoglyph(r)
+			}
+
+			written, _ := sb.WriteRune(r)
+			for i := 0; i < written; i++ {
+				n.starts = append(n.starts, start)
+				n.ends = append(n.ends, end)
+			}
+		}
+	}
+
+	if opts.nfc {
+		var it norm.Iter
+		it.InitString(norm.NFC, text)
+		for !it.Done() {
+			start := it.Pos()
+			segment := it.Next()
+			emit(segment, start, it.Pos())
+		}
+	} else {
+		for i := 0; i < len(text); {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			emit([]byte(text[i:i+size]), i, i+size)
+			i += size
+		}
+	}
+
+	n.text = sb.String()
+	return n
+}
+
+// needsNormalization reports whether normalizeText would change text
+func needsNormalization(text string, opts normalizeOptions) bool {
+	if strings.ContainsAny(text, zeroWidthChars) {
+		return true
+	}
+	if opts.nfc && !norm.NFC.IsNormalString(text) {
+		return true
+	}
+	if opts.foldHomoglyphs {
+		for _, r := range text {
+			if foldHomoglyph(r) != r {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// foldHomoglyph returns the ASCII equivalent of a look-alike rune, including
+// fullwidth ASCII forms, or the rune itself if it has none
+func foldHomoglyph(r rune) rune {
+	if r >= '\uFF01' && r <= '\uFF5E' {
+		return r - 0xFEE0
+	}
+	if folded, ok := homoglyphs[r]; ok {
+		return folded
+	}
+	return r
+}
+
+// originalSpan maps a [start, end) byte range in the normalized text back
+// to the corresponding range in the original text
+func (n *normalizedText) originalSpan(start, end int) (int, int) {
+	if n.starts == nil {
+		return start, end
+	}
+	if start >= len(n.starts) {
+		return len(n.original), len(n.original)
+	}
+
+	origStart := n.starts[start]
+	if end <= start {
+		return origStart, origStart
+	}
+	return origStart, n.ends[end-1]
+}
+
+// numericSeparators are characters commonly used to group the digits of
+// phone and ID numbers
+const numericSeparators = " .-()"
+
+// maxSeparatorRun is the longest run of separators dropped between two digits
+const maxSeparatorRun = 3
+
+// withoutSeparators returns a copy of the text with runs of numeric separators
+// between two digits removed, e.g. "(010) 1234.5678" becomes "(01012345678".
+// Offsets of the copy still map back to the original input.
+func (n *normalizedText) withoutSeparators() *normalizedText {
+	n.withoutSepsOnce.Do(func() {
+		n.withoutSeps = n.stripSeparators()
+	})
+	return n.withoutSeps
+}
+
+// stripSeparators builds the copy returned by withoutSeparators
+func (n *normalizedText) stripSeparators() *normalizedText {
+	text := n.text
+	stripped := &normalizedText{
+		original: n.original,
+		starts:   make([]int, 0, len(text)),
+		ends:     make([]int, 0, len(text)),
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(text))
+
+	for i := 0; i < len(text); i++ {
+		if strings.IndexByte(numericSeparators, text[i]) >= 0 && i > 0 && isDigit(text[i-1]) {
+			j := i
+			for j < len(text) && j-i < maxSeparatorRun && strings.IndexByte(numericSeparators, text[j]) >= 0 {
+				j++
+			}
+			if j < len(text) && isDigit(text[j]) {
+				i = j - 1
+				continue
+			}
+		}
+
+		sb.WriteByte(text[i])
+		start, end := n.originalSpan(i, i+1)
+		stripped.starts = append(stripped.starts, start)
+		stripped.ends = append(stripped.ends, end)
+	}
+
+	stripped.text = sb.String()
+	return stripped
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
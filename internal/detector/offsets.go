@@ -0,0 +1,70 @@
+package detector
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// OffsetUnit is the unit detection positions are reported in
+type OffsetUnit string
+
+// Offset units
+const (
+	// OffsetBytes reports UTF-8 byte offsets, the unit used internally
+	OffsetBytes OffsetUnit = "bytes"
+
+	// OffsetRunes reports Unicode code point offsets
+	OffsetRunes OffsetUnit = "runes"
+
+	// OffsetUTF16 reports UTF-16 code unit offsets, as used by JavaScript and most editors
+	OffsetUTF16 OffsetUnit = "utf16"
+)
+
+// ParseOffsetUnit parses an offset unit name. An empty name is OffsetBytes.
+func ParseOffsetUnit(s string) (OffsetUnit, error) {
+	switch unit := OffsetUnit(s); unit {
+	case "":
+		return OffsetBytes, nil
+	case OffsetBytes, OffsetRunes, OffsetUTF16:
+		return unit, nil
+	default:
+		return "", fmt.Errorf("unknown offset unit %q (available: bytes, runes, utf16)", s)
+	}
+}
+
+// ConvertOffsets returns copies of the detections with their byte positions in
+// text converted to unit. Detection and redaction always work on byte offsets;
+// convert only when reporting positions to consumers.
+func ConvertOffsets(text string, detections []DetectionResult, unit OffsetUnit) []DetectionResult {
+	if unit == OffsetBytes || unit == "" {
+		return detections
+	}
+
+	converted := make([]DetectionResult, len(detections))
+	for i, d := range detections {
+		d.Position = Position{
+			Start: convertOffset(text, d.Position.Start, unit),
+			End:   convertOffset(text, d.Position.End, unit),
+		}
+		converted[i] = d
+	}
+	return converted
+}
+
+// convertOffset converts a byte offset into text to unit
+func convertOffset(text string, offset int, unit OffsetUnit) int {
+	prefix := text[:min(max(offset, 0), len(text))]
+	if unit == OffsetRunes {
+		return utf8.RuneCountInString(prefix)
+	}
+
+	units := 0
+	for _, r := range prefix {
+		if r > 0xFFFF {
+			units += 2 // surrogate pair
+		} else {
+			units++
+		}
+	}
+	return units
+}
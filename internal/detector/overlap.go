@@ -0,0 +1,85 @@
+package detector
+
+import (
+	"sort"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// ResolveOverlaps returns the detections that do not overlap a stronger
+// detection, in order of position, ranking severities on the default scale.
+// For every set of detections with overlapping byte spans, such as two
+// patterns matching the same number or two rules of one pattern, the most
+// severe is kept, then the most confident, then the longest, then the
+// earliest, then the first by pattern name.
+func ResolveOverlaps(detections []DetectionResult) []DetectionResult {
+	return resolveOverlaps(detections, nil)
+}
+
+// ResolveOverlaps is like the package-level ResolveOverlaps, ranking
+// severities on the engine's severity scale
+func (e *Engine) ResolveOverlaps(detections []DetectionResult) []DetectionResult {
+	e.mu.RLock()
+	severities := e.severities
+	e.mu.RUnlock()
+	return resolveOverlaps(detections, severities)
+}
+
+// resolveOverlaps implements ResolveOverlaps for a severity scale
+func resolveOverlaps(detections []DetectionResult, severities patterns.SeverityScale) []DetectionResult {
+	if len(detections) < 2 {
+		return detections
+	}
+
+	ranked := make([]DetectionResult, len(detections))
+	copy(ranked, detections)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if sa, sb := severities.Level(a.Severity), severities.Level(b.Severity); sa != sb {
+			return sa > sb
+		}
+		if ca, cb := confidenceRank[a.Confidence], confidenceRank[b.Confidence]; ca != cb {
+			return ca > cb
+		}
+		if la, lb := a.Position.End-a.Position.Start, b.Position.End-b.Position.Start; la != lb {
+			return la > lb
+		}
+		if a.Position.Start != b.Position.Start {
+			return a.Position.Start < b.Position.Start
+		}
+		return a.PatternName < b.PatternName
+	})
+
+	kept := make([]DetectionResult, 0, len(ranked))
+	for _, d := range ranked {
+		overlaps := false
+		for _, k := range kept {
+			if d.Position.Start < k.Position.End && k.Position.Start < d.Position.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, d)
+		}
+	}
+
+	SortDetections(kept)
+	return kept
+}
+
+// SortDetections orders detections by start, end and pattern name, so that
+// their order does not depend on the order patterns were matched in.
+// Detections that tie keep their relative order.
+func SortDetections(detections []DetectionResult) {
+	sort.SliceStable(detections, func(i, j int) bool {
+		a, b := detections[i], detections[j]
+		if a.Position.Start != b.Position.Start {
+			return a.Position.Start < b.Position.Start
+		}
+		if a.Position.End != b.Position.End {
+			return a.Position.End < b.Position.End
+		}
+		return a.PatternName < b.PatternName
+	})
+}
@@ -1,32 +1,142 @@
 package patterns
 
+import (
+	"slices"
+	"sort"
+)
+
 // PIIPatternSpec represents a built-in PII pattern specification
 type PIIPatternSpec struct {
 	DisplayName     string
 	Description     string
-	Category        string // Category for grouping (e.g., "korea", "usa", "global", "secrets")
+	Category        string   // Category for grouping (e.g., "korea", "usa", "global", "secrets")
+	Tags            []string // Cross-cutting labels such as compliance regimes (e.g., "pci", "gdpr", "hipaa")
 	Patterns        []PatternRule
 	Validator       string
 	MaskingStrategy MaskingStrategy
 	Severity        string
 	Enabled         bool // Whether this pattern is enabled by default
+
+	// ConfidenceMasking holds alternate masking strategies keyed by match
+	// confidence (high, medium, low); MaskingStrategy applies otherwise
+	ConfidenceMasking map[string]MaskingStrategy
+
+	// SeparatorInsensitive matches the pattern against a copy of the input with
+	// spaces, dots, hyphens and parentheses between digits removed
+	SeparatorInsensitive bool
+
+	// RejectTrivialNumbers drops matches whose digits are all the same or
+	// fully sequential, such as 111-11-1111 or 123456789
+	RejectTrivialNumbers bool
+
+	// MinLength drops matches shorter than this many characters; zero disables the check
+	MinLength int
+
+	// RequiresAll makes this a composite pattern that matches only where all
+	// of the named patterns match, emitting a single detection spanning them.
+	// Composite patterns have no rules of their own.
+	RequiresAll []string
+
+	// Window is the largest span in bytes a composite match may cover; zero
+	// allows the components anywhere in the input
+	Window int
+
+	// MaxMatchLength bounds the length in bytes of a match, for patterns whose
+	// rules can match arbitrarily long text such as PEM blocks. Streaming
+	// detection overlaps its windows by at least this much. Zero derives the
+	// bound from the rules.
+	MaxMatchLength int
+
+	// ContextKeywords, when set, keep only matches with one of the keywords
+	// within ContextWindow characters before or after them, compared without
+	// regard to case. They cut false positives of generic rules such as a
+	// bare 9-digit number.
+	ContextKeywords []string
+
+	// ContextWindow is how many characters around a match are searched for
+	// ContextKeywords; zero means DefaultContextWindow
+	ContextWindow int
+
+	// SensitiveGroups names capture groups, by name or by index, whose values
+	// are kept out of detection metadata. Groups masked by one of the
+	// pattern's masking strategies are always kept out.
+	SensitiveGroups []string
 }
 
+// DefaultContextWindow is the number of characters around a match searched
+// for context keywords when no window is set
+const DefaultContextWindow = 32
+
 // PatternRule defines a regex pattern with confidence level
 type PatternRule struct {
 	Regex      string
 	Confidence string // high, medium, low
+
+	// ContextKeywords and ContextWindow override those of the pattern for
+	// matches of this rule
+	ContextKeywords []string
+	ContextWindow   int
 }
 
 // MaskingStrategy defines how to mask detected PII
 type MaskingStrategy struct {
-	Type        string // full, partial, hash, tokenize
+	Type        string // full, partial, hash, tokenize, pseudonym, maskBefore, maskAfter
 	ShowFirst   int
 	ShowLast    int
 	MaskChar    string
 	Replacement string
+
+	// Delimiter anchors maskBefore and maskAfter, which mask only the text
+	// before the last or after the first occurrence of the delimiter
+	Delimiter string
+
+	// ShowFirstPercent and ShowLastPercent reveal a share (0-100) of the
+	// matched length and take precedence over ShowFirst/ShowLast when set
+	ShowFirstPercent int
+	ShowLastPercent  int
+
+	// Chain lists further masking steps, each applied in order to the output
+	// of the previous one
+	Chain []MaskingStrategy
+
+	// PreserveBoundaryLines keeps the first and last lines of a multiline
+	// match, such as the BEGIN and END markers of a PEM block, visible and
+	// masks only the lines between them
+	PreserveBoundaryLines bool
+
+	// Group names the capture group of the matching rule to mask, by name or
+	// by index, leaving the rest of the match visible. Matches in which the
+	// group did not take part are masked whole.
+	Group string
+}
+
+// IsMaskingType reports whether t names a masking strategy type
+func IsMaskingType(t string) bool {
+	switch t {
+	case "full", "partial", "hash", "tokenize", "pseudonym", "maskBefore", "maskAfter":
+		return true
+	}
+	return false
 }
 
+// Valid reports whether the strategy can be applied as configured: its type
+// is known and delimiter-anchored types have a delimiter. Masking with an
+// invalid strategy falls back to partial masking with the strategy's reveal
+// amounts, which may be none at all.
+func (s MaskingStrategy) Valid() bool {
+	if !IsMaskingType(s.Type) {
+		return false
+	}
+	return s.Delimiter != "" || (s.Type != "maskBefore" && s.Type != "maskAfter")
+}
+
+// privateKeyMaxLength bounds a PEM private key block; an unencrypted
+// 4096-bit RSA key takes about 3.3KB
+const privateKeyMaxLength = 8 * 1024
+
+// jwtMaxLength bounds a JWT, which servers commonly refuse in headers over 8KB
+const jwtMaxLength = 8 * 1024
+
 // BuiltInPatterns contains all built-in PII patterns
 var BuiltInPatterns = map[string]PIIPatternSpec{
 	// ============================================
@@ -38,6 +148,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "Email Address",
 		Description:     "Detects email addresses",
 		Category:        "global",
+		Tags:            []string{"gdpr", "hipaa"},
 		Patterns:        []PatternRule{{Regex: `[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
 		Severity:        "medium",
@@ -49,14 +160,16 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName: "Credit Card Number",
 		Description: "Detects credit card numbers (Visa, MasterCard, Amex, Discover)",
 		Category:    "global",
+		Tags:        []string{"pci", "gdpr"},
 		Patterns: []PatternRule{
 			{Regex: `\b(?:4[0-9]{12}(?:[0-9]{3})?|5[1-5][0-9]{14}|3[47][0-9]{13}|6(?:011|5[0-9]{2})[0-9]{12})\b`, Confidence: "high"},
 			{Regex: `\d{4}[- ]?\d{4}[- ]?\d{4}[- ]?\d{4}`, Confidence: "medium"},
 		},
-		Validator:       "luhn",
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 4, ShowLast: 4, MaskChar: "*"},
-		Severity:        "critical",
-		Enabled:         true,
+		Validator:            "luhn",
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 4, ShowLast: 4, MaskChar: "*"},
+		Severity:             "critical",
+		Enabled:              true,
+		RejectTrivialNumbers: true,
 	},
 
 	// IP Address
@@ -64,6 +177,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "IP Address",
 		Description:     "Detects IPv4 addresses",
 		Category:        "global",
+		Tags:            []string{"gdpr", "hipaa"},
 		Patterns:        []PatternRule{{Regex: `\b(?:(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\.){3}(?:25[0-5]|2[0-4][0-9]|[01]?[0-9][0-9]?)\b`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[IP_REDACTED]"},
 		Severity:        "low",
@@ -73,22 +187,45 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 	// IPv6 Address
 	"ipv6-address": {
 		DisplayName: "IPv6 Address",
-		Description: "Detects IPv6 addresses",
+		Description: "Detects IPv6 addresses, including compressed forms and zones",
 		Category:    "global",
+		Tags:        []string{"gdpr", "hipaa"},
+		// The regex takes whole runs of hex digits, colons and dots that are
+		// not part of a longer word, so that no valid-looking part of a longer
+		// run or of a path such as std::string is reported, and the validator
+		// narrows them down from times, MAC addresses and the like
 		Patterns: []PatternRule{
-			{Regex: `(?:[0-9a-fA-F]{1,4}:){7}[0-9a-fA-F]{1,4}`, Confidence: "high"},
-			{Regex: `(?:[0-9a-fA-F]{1,4}:){1,7}:`, Confidence: "medium"},
+			{Regex: `(?:\b[0-9a-fA-F]+|\B):[0-9a-fA-F:.]*(?:[0-9a-fA-F]\b|:\B)(?:%[0-9A-Za-z._~-]+)?`, Confidence: "high"},
 		},
+		Validator:       "ipv6",
 		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[IPv6_REDACTED]"},
 		Severity:        "low",
 		Enabled:         false,
 	},
 
+	// International Phone Number
+	"phone-intl": {
+		DisplayName: "International Phone Number",
+		Description: "Phone numbers written with a + and the country calling code, with optional extensions",
+		Category:    "global",
+		Tags:        []string{"gdpr", "hipaa"},
+		Patterns: []PatternRule{
+			// \B keeps the + from following a letter or digit, as in the
+			// offset of a timestamp or inside an ID
+			{Regex: `\B\+[1-9](?:[ .-]?\(?\d\)?){6,14}(?:\s?(?i:ext\.?|x|#)\s?\d{1,6})?\b`, Confidence: "medium"},
+		},
+		Validator:       "phone-length",
+		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 2, MaskChar: "*"},
+		Severity:        "high",
+		Enabled:         true,
+	},
+
 	// IBAN (International Bank Account Number)
 	"iban": {
 		DisplayName:     "IBAN",
 		Description:     "International Bank Account Number",
 		Category:        "global",
+		Tags:            []string{"gdpr"},
 		Patterns:        []PatternRule{{Regex: `[A-Z]{2}\d{2}[A-Z0-9]{4}\d{7}(?:[A-Z0-9]?){0,16}`, Confidence: "high"}},
 		Validator:       "iban-checksum",
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 4, ShowLast: 4, MaskChar: "*"},
@@ -101,12 +238,29 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "MAC Address",
 		Description:     "Detects MAC addresses",
 		Category:        "global",
+		Tags:            []string{"gdpr", "hipaa"},
 		Patterns:        []PatternRule{{Regex: `(?:[0-9A-Fa-f]{2}[:-]){5}[0-9A-Fa-f]{2}`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 8, ShowLast: 0, MaskChar: "*"},
 		Severity:        "low",
 		Enabled:         false,
 	},
 
+	// Date of Birth. Generic dates are far too common to flag, so a birth
+	// keyword must precede the date and is redacted along with it.
+	"date-of-birth": {
+		DisplayName: "Date of Birth",
+		Description: "Detects dates of birth (YYYY-MM-DD, MM/DD/YYYY, DD.MM.YYYY) following a keyword such as dob, birth or born",
+		Category:    "global",
+		Tags:        []string{"gdpr", "hipaa"},
+		Patterns: []PatternRule{
+			{Regex: `(?i)\b(?:dob|d\.o\.b\.|date[ _-]?of[ _-]?birth|birth[ _-]?date|birthday|born(?: on)?)[\s:="'-]{0,5}(?:\d{4}-\d{1,2}-\d{1,2}|\d{1,2}/\d{1,2}/\d{4}|\d{1,2}\.\d{1,2}\.\d{4})\b`, Confidence: "medium"},
+		},
+		Validator:       "date-of-birth",
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[DOB_REDACTED]"},
+		Severity:        "high",
+		Enabled:         false, // Disabled by default as dates are common in logs
+	},
+
 	// ============================================
 	// USA PATTERNS
 	// ============================================
@@ -116,13 +270,16 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName: "US Social Security Number",
 		Description: "US Social Security Number (XXX-XX-XXXX format)",
 		Category:    "usa",
+		Tags:        []string{"hipaa"},
 		Patterns: []PatternRule{
 			{Regex: `\b\d{3}-\d{2}-\d{4}\b`, Confidence: "high"},
 			{Regex: `\b\d{9}\b`, Confidence: "low"},
 		},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 0, ShowLast: 4, MaskChar: "*"},
-		Severity:        "critical",
-		Enabled:         true,
+		Validator:            "ssn-us",
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 0, ShowLast: 4, MaskChar: "*"},
+		Severity:             "critical",
+		Enabled:              true,
+		RejectTrivialNumbers: true,
 	},
 
 	// US Phone Number
@@ -130,6 +287,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "US Phone Number",
 		Description:     "US phone numbers in various formats",
 		Category:        "usa",
+		Tags:            []string{"hipaa"},
 		Patterns:        []PatternRule{{Regex: `\b(?:\+1[-.\s]?)?\(?[2-9]\d{2}\)?[-.\s]?\d{3}[-.\s]?\d{4}\b`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 4, MaskChar: "*"},
 		Severity:        "high",
@@ -141,6 +299,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "US Driver License",
 		Description:     "US Driver License numbers (generic pattern)",
 		Category:        "usa",
+		Tags:            []string{"hipaa"},
 		Patterns:        []PatternRule{{Regex: `\b[A-Z]{1,2}\d{5,8}\b`, Confidence: "medium"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
 		Severity:        "critical",
@@ -149,24 +308,27 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 
 	// US Passport Number
 	"passport-us": {
-		DisplayName:     "US Passport Number",
-		Description:     "US Passport numbers",
-		Category:        "usa",
-		Patterns:        []PatternRule{{Regex: `\b[0-9]{9}\b`, Confidence: "low"}},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
-		Severity:        "critical",
-		Enabled:         false,
+		DisplayName:          "US Passport Number",
+		Description:          "US Passport numbers",
+		Category:             "usa",
+		Patterns:             []PatternRule{{Regex: `\b[0-9]{9}\b`, Confidence: "low"}},
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
+		Severity:             "critical",
+		Enabled:              false,
+		RejectTrivialNumbers: true,
 	},
 
 	// US Bank Routing Number
 	"routing-number-us": {
-		DisplayName:     "US Bank Routing Number",
-		Description:     "US Bank ABA Routing Transit Number",
-		Category:        "usa",
-		Patterns:        []PatternRule{{Regex: `\b[0-9]{9}\b`, Confidence: "low"}},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 0, ShowLast: 4, MaskChar: "*"},
-		Severity:        "high",
-		Enabled:         false,
+		DisplayName:          "US Bank Routing Number",
+		Description:          "US Bank ABA Routing Transit Number",
+		Category:             "usa",
+		Patterns:             []PatternRule{{Regex: `\b[0-9]{9}\b`, Confidence: "low"}},
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 0, ShowLast: 4, MaskChar: "*"},
+		Severity:             "high",
+		Enabled:              false,
+		RejectTrivialNumbers: true,
+		ContextKeywords:      []string{"routing", "aba", "rtn", "transit"},
 	},
 
 	// US Individual Taxpayer Identification Number (ITIN)
@@ -185,6 +347,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "US Medicare ID",
 		Description:     "US Medicare Beneficiary Identifier (MBI)",
 		Category:        "usa",
+		Tags:            []string{"hipaa"},
 		Patterns:        []PatternRule{{Regex: `\b[1-9][AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9]\d[AC-HJKMNP-RT-Y][AC-HJKMNP-RT-Y0-9]\d[AC-HJKMNP-RT-Y]{2}\d{2}\b`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 0, ShowLast: 4, MaskChar: "*"},
 		Severity:        "critical",
@@ -193,13 +356,14 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 
 	// US Employer Identification Number (EIN)
 	"ein-us": {
-		DisplayName:     "US EIN",
-		Description:     "US Employer Identification Number",
-		Category:        "usa",
-		Patterns:        []PatternRule{{Regex: `\b\d{2}-\d{7}\b`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
-		Severity:        "high",
-		Enabled:         true,
+		DisplayName:          "US EIN",
+		Description:          "US Employer Identification Number",
+		Category:             "usa",
+		Patterns:             []PatternRule{{Regex: `\b\d{2}-\d{7}\b`, Confidence: "high"}},
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
+		Severity:             "high",
+		Enabled:              true,
+		RejectTrivialNumbers: true,
 	},
 
 	// US DEA Number
@@ -207,6 +371,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "US DEA Number",
 		Description:     "US Drug Enforcement Administration registration number",
 		Category:        "usa",
+		Tags:            []string{"hipaa"},
 		Patterns:        []PatternRule{{Regex: `\b[A-Z][A-Z9][0-9]{7}\b`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
 		Severity:        "critical",
@@ -222,9 +387,10 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName: "Korean Resident Registration Number",
 		Description: "Korean RRN (Resident Registration Number)",
 		Category:    "korea",
+		Tags:        []string{"pipa"},
 		Patterns: []PatternRule{
-			{Regex: `\d{6}-[1-4]\d{6}`, Confidence: "high"},
-			{Regex: `\d{6}[1-4]\d{6}`, Confidence: "medium"},
+			{Regex: `\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])-[0-49]\d{6}`, Confidence: "high"},
+			{Regex: `\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])[0-49]\d{6}`, Confidence: "medium"},
 		},
 		Validator:       "rrn-checksum",
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 6, ShowLast: 0, MaskChar: "*"},
@@ -237,14 +403,16 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName: "Korean Phone Number",
 		Description: "Korean phone numbers (mobile and landline)",
 		Category:    "korea",
+		Tags:        []string{"pipa"},
 		Patterns: []PatternRule{
 			{Regex: `01[016789]-?\d{3,4}-?\d{4}`, Confidence: "high"},
 			{Regex: `02-?\d{3,4}-?\d{4}`, Confidence: "high"},
 			{Regex: `0[3-6][1-5]-?\d{3,4}-?\d{4}`, Confidence: "high"},
 		},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 4, MaskChar: "*"},
-		Severity:        "high",
-		Enabled:         true,
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 4, MaskChar: "*"},
+		Severity:             "high",
+		Enabled:              true,
+		SeparatorInsensitive: true,
 	},
 
 	// Korean Passport Number
@@ -252,6 +420,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "Korean Passport Number",
 		Description:     "Korean passport numbers",
 		Category:        "korea",
+		Tags:            []string{"pipa"},
 		Patterns:        []PatternRule{{Regex: `[A-Z]{1,2}\d{7,8}`, Confidence: "medium"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 2, ShowLast: 0, MaskChar: "*"},
 		Severity:        "critical",
@@ -260,25 +429,28 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 
 	// Korean Driver License
 	"driver-license-kr": {
-		DisplayName:     "Korean Driver License",
-		Description:     "Korean driver license numbers",
-		Category:        "korea",
-		Patterns:        []PatternRule{{Regex: `\d{2}-\d{2}-\d{6}-\d{2}`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 5, ShowLast: 0, MaskChar: "*"},
-		Severity:        "critical",
-		Enabled:         true,
+		DisplayName:          "Korean Driver License",
+		Description:          "Korean driver license numbers",
+		Category:             "korea",
+		Tags:                 []string{"pipa"},
+		Patterns:             []PatternRule{{Regex: `\d{2}-\d{2}-\d{6}-\d{2}`, Confidence: "high"}},
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 5, ShowLast: 0, MaskChar: "*"},
+		Severity:             "critical",
+		Enabled:              true,
+		RejectTrivialNumbers: true,
 	},
 
 	// Korean Business Registration Number
 	"business-number-kr": {
-		DisplayName:     "Korean Business Registration Number",
-		Description:     "Korean business registration numbers",
-		Category:        "korea",
-		Patterns:        []PatternRule{{Regex: `\d{3}-\d{2}-\d{5}`, Confidence: "high"}},
-		Validator:       "business-number-checksum",
-		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 0, MaskChar: "*"},
-		Severity:        "high",
-		Enabled:         true,
+		DisplayName:          "Korean Business Registration Number",
+		Description:          "Korean business registration numbers",
+		Category:             "korea",
+		Patterns:             []PatternRule{{Regex: `\d{3}-\d{2}-\d{5}`, Confidence: "high"}},
+		Validator:            "business-number-checksum",
+		MaskingStrategy:      MaskingStrategy{Type: "partial", ShowFirst: 3, ShowLast: 0, MaskChar: "*"},
+		Severity:             "high",
+		Enabled:              true,
+		RejectTrivialNumbers: true,
 	},
 
 	// Korean Foreign Registration Number
@@ -286,9 +458,12 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName: "Korean Foreign Registration Number",
 		Description: "Korean foreign registration numbers",
 		Category:    "korea",
+		Tags:        []string{"pipa"},
 		Patterns: []PatternRule{
-			{Regex: `\d{6}-[5-8]\d{6}`, Confidence: "high"},
+			{Regex: `\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])-[5-8]\d{6}`, Confidence: "high"},
+			{Regex: `\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])[5-8]\d{6}`, Confidence: "medium"},
 		},
+		Validator:       "rrn-checksum",
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 6, ShowLast: 0, MaskChar: "*"},
 		Severity:        "critical",
 		Enabled:         true,
@@ -314,12 +489,27 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "AWS Secret Access Key",
 		Description:     "AWS Secret Access Key",
 		Category:        "secrets",
-		Patterns:        []PatternRule{{Regex: `(?i)aws.{0,20}secret.{0,20}['\"][0-9a-zA-Z/+]{40}['\"]`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[AWS_SECRET_REDACTED]"},
+		Patterns:        []PatternRule{{Regex: `(?i)aws.{0,20}secret.{0,20}['\"](?P<secret>[0-9a-zA-Z/+]{40})['\"]`, Confidence: "high"}},
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[AWS_SECRET_REDACTED]", Group: "secret"},
 		Severity:        "critical",
 		Enabled:         true,
 	},
 
+	// AWS Secret Key (keyword-free). Matches are entropy-checked and raised to
+	// high confidence when an AWS access key appears nearby. Disabled by
+	// default, as hashes and base64 data of the same shape pass the entropy
+	// check and would raise critical alerts.
+	"aws-secret-key-entropy": {
+		DisplayName:     "AWS Secret Access Key (Entropy)",
+		Description:     "High-entropy 40-character string shaped like an AWS Secret Access Key",
+		Category:        "secrets",
+		Patterns:        []PatternRule{{Regex: `[0-9a-zA-Z/+]{40,}`, Confidence: "low"}},
+		Validator:       "aws-secret-entropy",
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[AWS_SECRET_REDACTED]"},
+		Severity:        "critical",
+		Enabled:         false,
+	},
+
 	// GitHub Token
 	"github-token": {
 		DisplayName: "GitHub Token",
@@ -393,6 +583,7 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		Patterns:        []PatternRule{{Regex: `eyJ[a-zA-Z0-9_-]*\.eyJ[a-zA-Z0-9_-]*\.[a-zA-Z0-9_-]*`, Confidence: "high"}},
 		MaskingStrategy: MaskingStrategy{Type: "partial", ShowFirst: 10, ShowLast: 0, MaskChar: "*"},
 		Severity:        "high",
+		MaxMatchLength:  jwtMaxLength,
 		Enabled:         true,
 	},
 
@@ -401,9 +592,10 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "Private Key",
 		Description:     "Detects private keys (RSA, DSA, EC, etc.)",
 		Category:        "secrets",
-		Patterns:        []PatternRule{{Regex: `-----BEGIN (?:RSA |DSA |EC |OPENSSH )?PRIVATE KEY-----`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[PRIVATE_KEY_REDACTED]"},
+		Patterns:        []PatternRule{{Regex: `-----BEGIN (?:RSA |DSA |EC |OPENSSH |ENCRYPTED )?PRIVATE KEY-----(?:[A-Za-z0-9+/=:,\-\s]*?-----END (?:RSA |DSA |EC |OPENSSH |ENCRYPTED )?PRIVATE KEY-----)?`, Confidence: "high"}},
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[PRIVATE_KEY_REDACTED]", PreserveBoundaryLines: true},
 		Severity:        "critical",
+		MaxMatchLength:  privateKeyMaxLength,
 		Enabled:         true,
 	},
 
@@ -412,8 +604,8 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "Password in URL",
 		Description:     "Detects passwords embedded in URLs",
 		Category:        "secrets",
-		Patterns:        []PatternRule{{Regex: `(?i)(?:https?://)[^:]+:([^@]+)@`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[PASSWORD_REDACTED]"},
+		Patterns:        []PatternRule{{Regex: `(?i)(?:https?://)[^:]+:(?P<password>[^@]+)@`, Confidence: "high"}},
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[PASSWORD_REDACTED]", Group: "password"},
 		Severity:        "critical",
 		Enabled:         true,
 	},
@@ -434,8 +626,8 @@ var BuiltInPatterns = map[string]PIIPatternSpec{
 		DisplayName:     "Database Connection String",
 		Description:     "Detects database connection strings with credentials",
 		Category:        "secrets",
-		Patterns:        []PatternRule{{Regex: `(?i)(?:mongodb|postgres|mysql|redis|amqp):\/\/[^:]+:[^@]+@`, Confidence: "high"}},
-		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[DB_CONNECTION_REDACTED]"},
+		Patterns:        []PatternRule{{Regex: `(?i)(?:mongodb|postgres|mysql|redis|amqp):\/\/[^:]+:(?P<password>[^@]+)@`, Confidence: "high"}},
+		MaskingStrategy: MaskingStrategy{Type: "full", Replacement: "[PASSWORD_REDACTED]", Group: "password"},
 		Severity:        "critical",
 		Enabled:         true,
 	},
@@ -541,3 +733,58 @@ func GetCategories() []string {
 	}
 	return categories
 }
+
+// HasTag reports whether the pattern carries the tag
+func (s *PIIPatternSpec) HasTag(tag string) bool {
+	return slices.Contains(s.Tags, tag)
+}
+
+// ListPatternsByTag returns the sorted names of built-in patterns carrying the tag
+func ListPatternsByTag(tag string) []string {
+	names := make([]string, 0)
+	for name, spec := range BuiltInPatterns {
+		if spec.HasTag(tag) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetTags returns all tags used by built-in patterns, sorted
+func GetTags() []string {
+	tagMap := make(map[string]bool)
+	for _, spec := range BuiltInPatterns {
+		for _, tag := range spec.Tags {
+			tagMap[tag] = true
+		}
+	}
+
+	tags := make([]string, 0, len(tagMap))
+	for tag := range tagMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// CategoryStat counts the patterns in a category
+type CategoryStat struct {
+	Total   int `json:"total"`
+	Enabled int `json:"enabled"`
+}
+
+// CategoryStats returns the number of built-in patterns in each category and
+// how many of them are enabled by default
+func CategoryStats() map[string]CategoryStat {
+	stats := make(map[string]CategoryStat)
+	for _, spec := range BuiltInPatterns {
+		stat := stats[spec.Category]
+		stat.Total++
+		if spec.Enabled {
+			stat.Enabled++
+		}
+		stats[spec.Category] = stat
+	}
+	return stats
+}
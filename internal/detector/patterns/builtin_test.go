@@ -0,0 +1,31 @@
+package patterns
+
+import "testing"
+
+func TestCategoryStats(t *testing.T) {
+	stats := CategoryStats()
+
+	for _, category := range GetCategories() {
+		names := ListPatternsByCategory(category)
+		enabled := 0
+		for _, name := range names {
+			if BuiltInPatterns[name].Enabled {
+				enabled++
+			}
+		}
+
+		want := CategoryStat{Total: len(names), Enabled: enabled}
+		if got := stats[category]; got != want {
+			t.Errorf("CategoryStats()[%q] = %+v, want %+v", category, got, want)
+		}
+	}
+
+	total := 0
+	for _, stat := range stats {
+		total += stat.Total
+	}
+	if total != len(BuiltInPatterns) || len(stats) != len(GetCategories()) {
+		t.Errorf("CategoryStats() covers %d patterns in %d categories, want %d in %d",
+			total, len(stats), len(BuiltInPatterns), len(GetCategories()))
+	}
+}
@@ -0,0 +1,34 @@
+package patterns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// ParseRevealAmount splits a showFirst/showLast value into an absolute count or a percentage
+func ParseRevealAmount(v intstr.IntOrString) (count, percent int, err error) {
+	if v.Type == intstr.Int {
+		if v.IntVal < 0 {
+			return 0, 0, fmt.Errorf("must not be negative")
+		}
+		return int(v.IntVal), 0, nil
+	}
+
+	value := strings.TrimSpace(v.StrVal)
+	if !strings.HasSuffix(value, "%") {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return 0, 0, fmt.Errorf("invalid value %q: must be a non-negative integer or percentage", v.StrVal)
+		}
+		return n, 0, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || n < 0 || n > 100 {
+		return 0, 0, fmt.Errorf("invalid percentage %q: must be between 0%% and 100%%", v.StrVal)
+	}
+	return 0, n, nil
+}
@@ -0,0 +1,37 @@
+package patterns
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestParseRevealAmount(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       intstr.IntOrString
+		wantCount   int
+		wantPercent int
+		wantErr     bool
+	}{
+		{name: "integer", value: intstr.FromInt32(4), wantCount: 4},
+		{name: "numeric string", value: intstr.FromString("3"), wantCount: 3},
+		{name: "percentage", value: intstr.FromString("25%"), wantPercent: 25},
+		{name: "zero", value: intstr.IntOrString{}, wantCount: 0},
+		{name: "negative integer", value: intstr.FromInt32(-1), wantErr: true},
+		{name: "over 100 percent", value: intstr.FromString("150%"), wantErr: true},
+		{name: "garbage", value: intstr.FromString("half"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, percent, err := ParseRevealAmount(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRevealAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if count != tt.wantCount || percent != tt.wantPercent {
+				t.Errorf("ParseRevealAmount() = (%d, %d), want (%d, %d)", count, percent, tt.wantCount, tt.wantPercent)
+			}
+		})
+	}
+}
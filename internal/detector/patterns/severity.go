@@ -0,0 +1,88 @@
+package patterns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SeverityScale ranks severity names for comparison; a higher rank is more
+// severe and names missing from the scale rank 0
+type SeverityScale map[string]int
+
+// DefaultSeverityScale is the four-level scale used when none is configured
+var DefaultSeverityScale = SeverityScale{
+	"critical": 4,
+	"high":     3,
+	"medium":   2,
+	"low":      1,
+}
+
+// Level returns the rank of a severity on the scale, or 0 for an unknown
+// severity. A nil scale is the default scale.
+func (s SeverityScale) Level(severity string) int {
+	if s == nil {
+		s = DefaultSeverityScale
+	}
+	return s[severity]
+}
+
+// AtLeast reports whether severity ranks at or above min
+func (s SeverityScale) AtLeast(severity, min string) bool {
+	return s.Level(severity) >= s.Level(min)
+}
+
+// Band maps a severity onto the default four levels by its rank relative to
+// the highest rank of the scale, so a custom scale can reuse what is keyed
+// by the default severities. It returns "" for an unknown severity.
+func (s SeverityScale) Band(severity string) string {
+	if s == nil {
+		s = DefaultSeverityScale
+	}
+	level := s[severity]
+	if level <= 0 {
+		return ""
+	}
+	top := 0
+	for _, l := range s {
+		top = max(top, l)
+	}
+	switch band := (level*4 + top - 1) / top; {
+	case band >= 4:
+		return "critical"
+	case band == 3:
+		return "high"
+	case band == 2:
+		return "medium"
+	default:
+		return "low"
+	}
+}
+
+// ParseSeverityScale parses a scale written as comma separated name=rank
+// pairs, such as "sev1=10,sev2=7,sev3=3". Ranks must be positive.
+func ParseSeverityScale(spec string) (SeverityScale, error) {
+	scale := make(SeverityScale)
+	for _, pair := range strings.Split(spec, ",") {
+		name, rank, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid severity %q: want name=rank", pair)
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(rank))
+		if err != nil || level <= 0 {
+			return nil, fmt.Errorf("invalid rank for severity %q: want a positive integer", name)
+		}
+		if _, dup := scale[name]; dup {
+			return nil, fmt.Errorf("severity %q listed twice", name)
+		}
+		scale[name] = level
+	}
+	return scale, nil
+}
+
+// SeverityLevel returns the numeric level of a severity on the default scale,
+// from 4 for critical down to 1 for low, or 0 for an unknown severity
+func SeverityLevel(severity string) int {
+	return DefaultSeverityScale.Level(severity)
+}
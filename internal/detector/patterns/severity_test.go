@@ -0,0 +1,67 @@
+package patterns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeverityScale_Band(t *testing.T) {
+	scale := SeverityScale{"sev1": 10, "sev2": 7, "sev3": 5, "sev4": 2}
+
+	tests := []struct {
+		scale    SeverityScale
+		severity string
+		want     string
+	}{
+		{nil, "critical", "critical"},
+		{nil, "high", "high"},
+		{nil, "medium", "medium"},
+		{nil, "low", "low"},
+		{nil, "sev1", ""},
+		{scale, "sev1", "critical"},
+		{scale, "sev2", "high"},
+		{scale, "sev3", "medium"},
+		{scale, "sev4", "low"},
+		{scale, "critical", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.scale.Band(tt.severity); got != tt.want {
+			t.Errorf("Band(%q) = %q, want %q", tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestSeverityScale_AtLeast(t *testing.T) {
+	scale := SeverityScale{"sev1": 10, "sev2": 7, "sev3": 5}
+
+	if !scale.AtLeast("sev1", "sev2") {
+		t.Error("sev1 should rank at least sev2")
+	}
+	if scale.AtLeast("sev3", "sev2") {
+		t.Error("sev3 should rank below sev2")
+	}
+	if scale.AtLeast("unknown", "sev3") {
+		t.Error("unknown severity should rank below sev3")
+	}
+	if !scale.AtLeast("sev3", "") {
+		t.Error("an empty minimum should admit every severity")
+	}
+}
+
+func TestParseSeverityScale(t *testing.T) {
+	got, err := ParseSeverityScale("sev1=10, sev2 = 7,sev3=3")
+	if err != nil {
+		t.Fatalf("ParseSeverityScale() error = %v", err)
+	}
+	want := SeverityScale{"sev1": 10, "sev2": 7, "sev3": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSeverityScale() = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"sev1", "=3", "sev1=0", "sev1=high", "sev1=2,sev1=3"} {
+		if _, err := ParseSeverityScale(spec); err == nil {
+			t.Errorf("ParseSeverityScale(%q) expected error", spec)
+		}
+	}
+}
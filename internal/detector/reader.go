@@ -0,0 +1,219 @@
+package detector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp/syntax"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultReaderOverlap is the longest match DetectInReader assumes for
+// rules that can match arbitrarily long text, unless their pattern sets
+// MaxMatchLength
+const DefaultReaderOverlap = 256
+
+// readerChunkSize is the number of bytes DetectInReader reads at a time
+const readerChunkSize = 64 * 1024
+
+// unboundedLength is the length regexMaxLength reports for a regular
+// expression that can match arbitrarily long text
+const unboundedLength = -1
+
+// SetReaderOverlap sets the overlap in bytes between the windows
+// DetectInReader scans. Overlaps shorter than the longest match of the
+// enabled patterns are raised to it, so a non-positive overlap uses exactly
+// that minimum; see ReaderOverlap.
+func (e *Engine) SetReaderOverlap(overlap int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.readerOverlap = overlap
+}
+
+// ReaderOverlap returns the overlap DetectInReader uses: the overlap set by
+// SetReaderOverlap, raised to the longest match any enabled pattern can
+// produce. That length is the pattern's MaxMatchLength when set, and is
+// otherwise derived from its rules, with DefaultReaderOverlap standing in
+// for rules that can match arbitrarily long text.
+func (e *Engine) ReaderOverlap() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	overlap := e.readerOverlap
+	for _, pattern := range e.patterns {
+		if pattern.Enabled {
+			overlap = max(overlap, pattern.maxMatchLength())
+		}
+	}
+	return overlap
+}
+
+// DetectInReader scans a stream for PII using only enabled patterns like
+// DetectInText, holding only a window of the stream in memory at a time.
+// Positions are byte offsets from the start of the stream, and each
+// detection is published to the event sink once, with those positions.
+//
+// Consecutive windows overlap, so that a match straddling the end of one
+// window is found in the next. Matches longer than the overlap (see
+// ReaderOverlap) may be missed or cut short, and a match is given the
+// overlap as left context, so patterns relying on lookbehind-like anchors
+// such as \b see at least that much of the text before it.
+//
+// The overlap trades memory for recall. A window holds the larger of 64KB
+// and four times the overlap, plus twice the overlap, and every window
+// rescans the overlap. Engines with only short patterns such as emails keep
+// small windows, while enabling a pattern like private-key, whose PEM blocks
+// run to kilobytes, widens every window to match.
+func (e *Engine) DetectInReader(ctx context.Context, r io.Reader) ([]DetectionResult, error) {
+	overlap := e.ReaderOverlap()
+	return e.detectInReader(ctx, r, max(readerChunkSize, 4*overlap), overlap)
+}
+
+// maxMatchLength returns the length in bytes of the longest match of the
+// pattern, which is MaxMatchLength when set. Separator-insensitive matches
+// are allowed a separator after every character of the rule's match.
+func (p *CompiledPattern) maxMatchLength() int {
+	if p.MaxMatchLength > 0 {
+		return p.MaxMatchLength
+	}
+	if len(p.RequiresAll) > 0 {
+		if p.Window > 0 {
+			return p.Window
+		}
+		return DefaultReaderOverlap
+	}
+
+	length := 0
+	for _, rule := range p.Patterns {
+		n := DefaultReaderOverlap
+		if re, err := syntax.Parse(rule.Regex.String(), syntax.Perl); err == nil {
+			if m := regexMaxLength(re); m != unboundedLength {
+				n = m
+			}
+		}
+		length = max(length, n)
+	}
+	if p.SeparatorInsensitive {
+		length *= 2
+	}
+	return length
+}
+
+// regexMaxLength returns the length in bytes of the longest text re can
+// match, or unboundedLength
+func regexMaxLength(re *syntax.Regexp) int {
+	switch re.Op {
+	case syntax.OpLiteral:
+		n := 0
+		for _, r := range re.Rune {
+			n += runeMaxLength(r, re.Flags&syntax.FoldCase != 0)
+		}
+		return n
+	case syntax.OpCharClass:
+		n := 0
+		for i := 1; i < len(re.Rune); i += 2 {
+			n = max(n, runeMaxLength(re.Rune[i], false))
+		}
+		return n
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return utf8.UTFMax
+	case syntax.OpCapture, syntax.OpQuest:
+		return regexMaxLength(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		return unboundedLength
+	case syntax.OpRepeat:
+		n := regexMaxLength(re.Sub[0])
+		if re.Max == -1 || n == unboundedLength {
+			return unboundedLength
+		}
+		return n * re.Max
+	case syntax.OpConcat, syntax.OpAlternate:
+		total := 0
+		for _, sub := range re.Sub {
+			n := regexMaxLength(sub)
+			if n == unboundedLength {
+				return unboundedLength
+			}
+			if re.Op == syntax.OpConcat {
+				total += n
+			} else {
+				total = max(total, n)
+			}
+		}
+		return total
+	}
+
+	// Empty-width assertions and empty matches
+	return 0
+}
+
+// runeMaxLength returns the UTF-8 length of r or, when folding case, of
+// the longest rune it folds to, such as the Kelvin sign for k
+func runeMaxLength(r rune, fold bool) int {
+	n := utf8.RuneLen(r)
+	if n < 0 {
+		n = utf8.UTFMax
+	}
+	if fold {
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			n = max(n, utf8.RuneLen(f))
+		}
+	}
+	return n
+}
+
+// detectInReader implements DetectInReader. Every window but the last
+// reports the detections starting in it before its final overlap bytes,
+// and the next window starts overlap bytes before that cut-off as context.
+func (e *Engine) detectInReader(ctx context.Context, r io.Reader, chunkSize, overlap int) ([]DetectionResult, error) {
+	var results []DetectionResult
+
+	// window holds the stream from offset windowStart; detections starting
+	// before reportFrom were reported by an earlier window
+	window := make([]byte, 0, chunkSize+2*overlap)
+	windowStart, reportFrom := 0, 0
+
+	for {
+		n, err := io.ReadFull(r, window[len(window):cap(window)])
+		window = window[:len(window)+n]
+		eof := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+		if err != nil && !eof {
+			return results, err
+		}
+
+		cutoff := len(window)
+		if !eof {
+			cutoff -= overlap
+		}
+
+		// Windows are scanned without publishing, so that the event sink
+		// sees each detection once, at its position in the stream
+		detections, derr := e.scanText(ctx, string(window), false)
+		reported := len(results)
+		for _, d := range detections {
+			if start := windowStart + d.Position.Start; start >= reportFrom && d.Position.Start < cutoff {
+				d.Position.Start += windowStart
+				d.Position.End += windowStart
+				results = append(results, d)
+			}
+		}
+		if derr != nil {
+			return results, derr
+		}
+		e.publishResults(results[reported:])
+		if eof {
+			return results, nil
+		}
+
+		// Keep overlap bytes before the cut-off as context, starting on a
+		// character boundary
+		keepFrom := max(cutoff-overlap, 0)
+		for keepFrom > 0 && !utf8.RuneStart(window[keepFrom]) {
+			keepFrom--
+		}
+		reportFrom = windowStart + cutoff
+		windowStart += keepFrom
+		window = window[:copy(window, window[keepFrom:])]
+	}
+}
@@ -0,0 +1,72 @@
+package detector
+
+import "sync/atomic"
+
+// Stats counts what performance limits kept from being scanned or reported,
+// across an engine and its snapshots
+type Stats struct {
+	// DroppedEvents is the number of detections not published because the
+	// event sink was full
+	DroppedEvents int64
+
+	// SizeSkipped is the number of inputs not scanned at all because they
+	// exceeded a size limit
+	SizeSkipped int64
+
+	// SizeTruncated is the number of inputs of which only a prefix was
+	// scanned because they exceeded a size limit
+	SizeTruncated int64
+
+	// SampledOut is the number of inputs whose detections were not processed
+	// because sampling left them out
+	SampledOut int64
+
+	// MatchCapHit is the number of times a pattern stopped reporting matches
+	// in an input because it reached the match cap
+	MatchCapHit int64
+}
+
+// dropCounters backs Stats. Counters are atomic so that concurrent scans can
+// update them without holding the engine lock.
+type dropCounters struct {
+	events        atomic.Int64
+	sizeSkipped   atomic.Int64
+	sizeTruncated atomic.Int64
+	sampledOut    atomic.Int64
+	matchCapHit   atomic.Int64
+}
+
+// Stats returns the drop counters of the engine
+func (e *Engine) Stats() Stats {
+	return Stats{
+		DroppedEvents: e.drops.events.Load(),
+		SizeSkipped:   e.drops.sizeSkipped.Load(),
+		SizeTruncated: e.drops.sizeTruncated.Load(),
+		SampledOut:    e.drops.sampledOut.Load(),
+		MatchCapHit:   e.drops.matchCapHit.Load(),
+	}
+}
+
+// SetMaxMatchesPerPattern caps the number of matches a pattern reports in a
+// single input. Matches beyond the cap are neither reported nor redacted and
+// are counted in Stats as MatchCapHit. Zero or less reports every match.
+func (e *Engine) SetMaxMatchesPerPattern(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.maxMatches = n
+}
+
+// RecordOversizedInput counts an input that a caller enforcing a size limit
+// skipped, or truncated when skipped is false, before handing it to the engine
+func (e *Engine) RecordOversizedInput(skipped bool) {
+	if skipped {
+		e.drops.sizeSkipped.Add(1)
+	} else {
+		e.drops.sizeTruncated.Add(1)
+	}
+}
+
+// RecordSampledOut counts an input that a caller's sampling left out
+func (e *Engine) RecordSampledOut() {
+	e.drops.sampledOut.Add(1)
+}
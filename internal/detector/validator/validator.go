@@ -1,8 +1,16 @@
 package validator
 
 import (
+	"fmt"
+	"math"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Validator is an interface for validating detected PII
@@ -10,16 +18,72 @@ type Validator interface {
 	Validate(input string) bool
 }
 
-// Registry holds all registered validators
+// Classifier is implemented by validators that can describe a detected value,
+// for example the holder attributes encoded in an identification number
+type Classifier interface {
+	Classify(input string) map[string]string
+}
+
+// ConfidenceRater is implemented by validators whose passing result is itself
+// evidence about a match, such as a checksum. The engine raises the confidence
+// of a validated match to the rated confidence but never lowers it.
+type ConfidenceRater interface {
+	Confidence(input string) string
+}
+
+// Registry holds all registered validators. It is guarded by registryMu;
+// use Register and GetValidator rather than accessing it directly.
 var Registry = map[string]Validator{
 	"luhn":                     &LuhnValidator{},
 	"rrn-checksum":             &KoreanRRNValidator{},
 	"business-number-checksum": &KoreanBusinessNumberValidator{},
 	"iban-checksum":            &IBANValidator{},
+	"aws-secret-entropy":       &AWSSecretKeyValidator{},
+	"date-of-birth":            &DateOfBirthValidator{},
+	"ipv6":                     &IPv6Validator{},
+	"phone-length":             &PhoneLengthValidator{},
+	"ssn-us":                   &SSNValidator{},
+}
+
+// registryMu guards Registry against concurrent registration
+var registryMu sync.RWMutex
+
+// Register makes a validator available to patterns under name. Engines
+// look validators up through GetValidator while detecting, so a validator
+// may be registered at any time, typically from an init function. Register
+// panics if name is empty, v is nil or a validator is already registered
+// under name.
+func Register(name string, v Validator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if name == "" || v == nil {
+		panic("validator: Register needs a name and a validator")
+	}
+	if _, exists := Registry[name]; exists {
+		panic("validator: Register called twice for " + name)
+	}
+	Registry[name] = v
+}
+
+// List returns the names of all registered validators, sorted
+func List() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(Registry))
+	for name := range Registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // GetValidator returns a validator by name
 func GetValidator(name string) (Validator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
 	v, ok := Registry[name]
 	return v, ok
 }
@@ -27,7 +91,50 @@ func GetValidator(name string) (Validator, bool) {
 // LuhnValidator validates credit card numbers using Luhn algorithm
 type LuhnValidator struct{}
 
-// Validate implements the Luhn algorithm for credit card validation
+// cardIssuer describes the numbers a card network issues
+type cardIssuer struct {
+	name string
+
+	// ranges are the inclusive ranges of leading digits of the network's
+	// numbers; both ends of a range have the same number of digits
+	ranges [][2]string
+
+	// lengths are the numbers of digits the network's numbers can have
+	lengths []int
+}
+
+// cardIssuers lists the major card networks. Their ranges do not overlap.
+var cardIssuers = []cardIssuer{
+	{name: "visa", ranges: [][2]string{{"4", "4"}}, lengths: []int{13, 16, 19}},
+	{name: "mastercard", ranges: [][2]string{{"51", "55"}, {"2221", "2720"}}, lengths: []int{16}},
+	{name: "amex", ranges: [][2]string{{"34", "34"}, {"37", "37"}}, lengths: []int{15}},
+	{name: "discover", ranges: [][2]string{{"6011", "6011"}, {"644", "649"}, {"65", "65"}}, lengths: []int{16, 17, 18, 19}},
+	{name: "diners", ranges: [][2]string{{"300", "305"}, {"36", "36"}, {"38", "39"}}, lengths: []int{14, 15, 16, 17, 18, 19}},
+	{name: "jcb", ranges: [][2]string{{"3528", "3589"}}, lengths: []int{16, 17, 18, 19}},
+	{name: "unionpay", ranges: [][2]string{{"62", "62"}}, lengths: []int{16, 17, 18, 19}},
+	{name: "maestro", ranges: [][2]string{{"50", "50"}, {"56", "58"}, {"67", "67"}}, lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}},
+}
+
+// issuerFor returns the card network issuing numbers starting with digits
+func issuerFor(digits string) (cardIssuer, bool) {
+	for _, issuer := range cardIssuers {
+		for _, r := range issuer.ranges {
+			if len(digits) < len(r[0]) {
+				continue
+			}
+			if prefix := digits[:len(r[0])]; prefix >= r[0] && prefix <= r[1] {
+				return issuer, true
+			}
+		}
+	}
+	return cardIssuer{}, false
+}
+
+// Validate implements the Luhn algorithm for credit card validation. Numbers
+// starting with the digits of a major card network must also have one of
+// the network's lengths, so that e.g. a 13-digit number starting with 51 is
+// rejected even if it passes the Luhn check. Numbers of other issuers are
+// checked by the Luhn algorithm alone.
 func (v *LuhnValidator) Validate(input string) bool {
 	// Remove non-digit characters
 	digits := strings.Map(func(r rune) rune {
@@ -40,6 +147,9 @@ func (v *LuhnValidator) Validate(input string) bool {
 	if len(digits) < 13 || len(digits) > 19 {
 		return false
 	}
+	if issuer, ok := issuerFor(digits); ok && !slices.Contains(issuer.lengths, len(digits)) {
+		return false
+	}
 
 	sum := 0
 	alt := false
@@ -61,25 +171,92 @@ func (v *LuhnValidator) Validate(input string) bool {
 	return sum%10 == 0
 }
 
-// KoreanRRNValidator validates Korean Resident Registration Numbers
+// Confidence rates a number passing the Luhn check as high confidence
+func (v *LuhnValidator) Confidence(input string) string {
+	return "high"
+}
+
+// KoreanRRNValidator validates Korean Resident Registration Numbers and
+// foreign registration numbers
 type KoreanRRNValidator struct{}
 
-// Validate validates Korean RRN checksum
-func (v *KoreanRRNValidator) Validate(input string) bool {
-	// Remove hyphen
+// rrnCode describes the holder encoded by the 7th digit of an RRN
+type rrnCode struct {
+	century   int
+	foreigner bool
+	female    bool
+}
+
+// rrnCodes maps the 7th digit of an RRN to the holder it encodes
+var rrnCodes = map[byte]rrnCode{
+	'9': {century: 1800},
+	'0': {century: 1800, female: true},
+	'1': {century: 1900},
+	'2': {century: 1900, female: true},
+	'3': {century: 2000},
+	'4': {century: 2000, female: true},
+	'5': {century: 1900, foreigner: true},
+	'6': {century: 1900, foreigner: true, female: true},
+	'7': {century: 2000, foreigner: true},
+	'8': {century: 2000, foreigner: true, female: true},
+}
+
+// rrnRandomizedFromYear is the first birth year whose numbers are issued
+// with random trailing digits and no check digit (from October 2020)
+const rrnRandomizedFromYear = 2020
+
+// parseRRN checks the digits, gender code and birth date of an RRN and
+// returns the holder code and birth year. The birth date must be a real
+// calendar date in the century given by the 7th digit, and not in the future.
+func parseRRN(input string) (string, rrnCode, int, bool) {
 	digits := strings.ReplaceAll(input, "-", "")
 
 	if len(digits) != 13 {
-		return false
+		return "", rrnCode{}, 0, false
 	}
 
 	// Validate all characters are digits
 	for _, c := range digits {
 		if c < '0' || c > '9' {
-			return false
+			return "", rrnCode{}, 0, false
 		}
 	}
 
+	code, ok := rrnCodes[digits[6]]
+	if !ok {
+		return "", rrnCode{}, 0, false
+	}
+
+	yy, _ := strconv.Atoi(digits[0:2])
+	month, _ := strconv.Atoi(digits[2:4])
+	day, _ := strconv.Atoi(digits[4:6])
+	year := code.century + yy
+
+	birth := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if birth.Year() != year || int(birth.Month()) != month || birth.Day() != day {
+		return "", rrnCode{}, 0, false
+	}
+
+	// A century digit that puts the birth date in the future is inconsistent
+	if birth.After(time.Now().UTC()) {
+		return "", rrnCode{}, 0, false
+	}
+
+	return digits, code, year, true
+}
+
+// Validate validates the RRN birth date, gender code and checksum
+func (v *KoreanRRNValidator) Validate(input string) bool {
+	digits, code, year, ok := parseRRN(input)
+	if !ok {
+		return false
+	}
+
+	// Newer numbers carry no check digit
+	if year >= rrnRandomizedFromYear {
+		return true
+	}
+
 	// Checksum weights
 	weights := []int{2, 3, 4, 5, 6, 7, 8, 9, 2, 3, 4, 5}
 
@@ -91,10 +268,47 @@ func (v *KoreanRRNValidator) Validate(input string) bool {
 
 	checkDigit, _ := strconv.Atoi(string(digits[12]))
 	expected := (11 - (sum % 11)) % 10
+	if code.foreigner {
+		expected = (13 - (sum % 11)) % 10
+	}
 
 	return checkDigit == expected
 }
 
+// Confidence rates an RRN with a valid check digit as high confidence. Newer
+// numbers carry no check digit, so validation only vouches for their birth
+// date and gender code and they are rated medium.
+func (v *KoreanRRNValidator) Confidence(input string) string {
+	_, _, year, ok := parseRRN(input)
+	if !ok || year >= rrnRandomizedFromYear {
+		return "medium"
+	}
+	return "high"
+}
+
+// Classify describes the holder of an RRN: residency, birth century and gender
+func (v *KoreanRRNValidator) Classify(input string) map[string]string {
+	_, code, _, ok := parseRRN(input)
+	if !ok {
+		return nil
+	}
+
+	residency := "citizen"
+	if code.foreigner {
+		residency = "foreigner"
+	}
+	gender := "male"
+	if code.female {
+		gender = "female"
+	}
+
+	return map[string]string{
+		"residency": residency,
+		"century":   fmt.Sprintf("%ds", code.century),
+		"gender":    gender,
+	}
+}
+
 // KoreanBusinessNumberValidator validates Korean Business Registration Numbers
 type KoreanBusinessNumberValidator struct{}
 
@@ -133,15 +347,44 @@ func (v *KoreanBusinessNumberValidator) Validate(input string) bool {
 	return checkDigit == expected
 }
 
+// Confidence rates a business number with a valid check digit as high confidence
+func (v *KoreanBusinessNumberValidator) Confidence(input string) string {
+	return "high"
+}
+
 // IBANValidator validates International Bank Account Numbers
 type IBANValidator struct{}
 
-// Validate validates IBAN checksum using MOD 97-10
+// ibanLengths gives the length of the IBANs of each country in the SWIFT
+// IBAN registry (ISO 13616)
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16,
+	"BG": 22, "BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22,
+	"CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20,
+	"EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22,
+	"GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28, "HN": 28, "HR": 21,
+	"HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30,
+	"KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20,
+	"MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23,
+	"PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22,
+	"RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24,
+	"SM": 27, "SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26,
+	"UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// Validate checks an IBAN as ISO 13616 defines it: a country of the IBAN
+// registry, the length of that country's IBANs, check digits 02 to 98 and
+// the MOD 97-10 checksum (ISO 7064). Spaces are ignored.
 func (v *IBANValidator) Validate(input string) bool {
 	// Remove spaces
 	iban := strings.ReplaceAll(strings.ToUpper(input), " ", "")
 
-	if len(iban) < 15 || len(iban) > 34 {
+	if len(iban) < 4 || len(iban) != ibanLengths[iban[:2]] {
+		return false
+	}
+	check, err := strconv.Atoi(iban[2:4])
+	if err != nil || check < 2 || check > 98 {
 		return false
 	}
 
@@ -166,6 +409,11 @@ func (v *IBANValidator) Validate(input string) bool {
 	return remainder == 1
 }
 
+// Confidence rates an IBAN passing the MOD 97-10 check as high confidence
+func (v *IBANValidator) Confidence(input string) string {
+	return "high"
+}
+
 // mod97 calculates the remainder when dividing a large number string by 97
 func mod97(numStr string) int {
 	remainder := 0
@@ -175,3 +423,278 @@ func mod97(numStr string) int {
 	}
 	return remainder
 }
+
+// awsSecretMinEntropy is the minimum Shannon entropy (bits per character) for
+// a 40-character string to be considered a randomly generated secret
+const awsSecretMinEntropy = 4.0
+
+// AWSSecretKeyValidator validates AWS secret access key candidates by charset and entropy
+type AWSSecretKeyValidator struct{}
+
+// Validate checks that the input is exactly 40 base64 characters with mixed
+// character classes and high entropy
+func (v *AWSSecretKeyValidator) Validate(input string) bool {
+	if len(input) != 40 {
+		return false
+	}
+
+	var hasUpper, hasLower, hasDigit bool
+	for _, c := range input {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= '0' && c <= '9':
+			hasDigit = true
+		case c == '/' || c == '+':
+		default:
+			return false
+		}
+	}
+
+	if !hasUpper || !hasLower || !hasDigit {
+		return false
+	}
+
+	return shannonEntropy(input) >= awsSecretMinEntropy
+}
+
+// IPv6Validator validates IPv6 address candidates
+type IPv6Validator struct{}
+
+// Validate checks that the input parses as an IPv6 address, in full or
+// compressed form, optionally with an embedded IPv4 address or a zone, and
+// spells out at least two groups. Addresses such as ::1 and fe80:: are
+// rejected, as they are indistinguishable from scoped names like Foo::bar.
+func (v *IPv6Validator) Validate(input string) bool {
+	addr, err := netip.ParseAddr(input)
+	if err != nil || !addr.Is6() {
+		return false
+	}
+
+	address, _, _ := strings.Cut(input, "%")
+	groups := 0
+	for _, group := range strings.Split(address, ":") {
+		if group != "" {
+			groups++
+		}
+	}
+	return groups >= 2
+}
+
+// SSNValidator validates US Social Security Numbers against the ranges the
+// Social Security Administration never issues
+type SSNValidator struct{}
+
+// Validate checks that the input has nine digits, ignoring separators, and
+// rejects area numbers 000, 666 and 900-999, group 00 and serial 0000
+func (v *SSNValidator) Validate(input string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, input)
+	if len(digits) != 9 {
+		return false
+	}
+
+	area, group, serial := digits[:3], digits[3:5], digits[5:]
+	switch {
+	case area == "000" || area == "666" || area[0] == '9':
+		return false
+	case group == "00" || serial == "0000":
+		return false
+	}
+	return true
+}
+
+// phoneNumberLengths gives the shortest and longest national number, the
+// digits after the country code, of common country calling codes
+var phoneNumberLengths = map[string][2]int{
+	"1":   {10, 10}, // NANP: United States, Canada, Caribbean
+	"7":   {10, 10}, // Russia, Kazakhstan
+	"20":  {8, 10},  // Egypt
+	"27":  {9, 9},   // South Africa
+	"31":  {9, 9},   // Netherlands
+	"33":  {9, 9},   // France
+	"34":  {9, 9},   // Spain
+	"39":  {6, 11},  // Italy
+	"41":  {9, 9},   // Switzerland
+	"44":  {9, 10},  // United Kingdom
+	"46":  {7, 13},  // Sweden
+	"49":  {6, 13},  // Germany
+	"52":  {10, 10}, // Mexico
+	"55":  {10, 11}, // Brazil
+	"61":  {9, 9},   // Australia
+	"62":  {8, 12},  // Indonesia
+	"63":  {8, 10},  // Philippines
+	"65":  {8, 8},   // Singapore
+	"66":  {8, 9},   // Thailand
+	"81":  {9, 10},  // Japan
+	"82":  {8, 10},  // South Korea
+	"84":  {9, 10},  // Vietnam
+	"86":  {10, 11}, // China
+	"91":  {10, 10}, // India
+	"852": {8, 8},   // Hong Kong
+	"886": {8, 9},   // Taiwan
+	"971": {8, 9},   // United Arab Emirates
+}
+
+// Lengths of international numbers of country codes missing from
+// phoneNumberLengths, counting the country code: E.164 allows at most 15
+// digits, and numbers shorter than 8 are rare
+const (
+	phoneMinDigits = 8
+	phoneMaxDigits = 15
+)
+
+// phoneExtension matches an extension at the end of a phone number
+var phoneExtension = regexp.MustCompile(`(?i)\s*(?:ext\.?|x|#)\s*\d+$`)
+
+// PhoneLengthValidator validates international phone numbers, written with
+// a + and the country calling code, by the length of the national number
+// the country code allows
+type PhoneLengthValidator struct{}
+
+// Validate checks the number of digits, ignoring separators and any
+// extension, against the lengths of the country code. Country codes are
+// prefix free, so at most one of the first three digits' prefixes is known.
+func (v *PhoneLengthValidator) Validate(input string) bool {
+	digits, ok := phoneDigits(input)
+	if !ok {
+		return false
+	}
+	if lengths, code, ok := phoneCountry(digits); ok {
+		national := len(digits) - len(code)
+		return national >= lengths[0] && national <= lengths[1]
+	}
+	return len(digits) >= phoneMinDigits && len(digits) <= phoneMaxDigits
+}
+
+// Confidence rates numbers of a known country code, whose length was
+// checked against that country, as high confidence
+func (v *PhoneLengthValidator) Confidence(input string) string {
+	if digits, ok := phoneDigits(input); ok {
+		if _, _, known := phoneCountry(digits); known {
+			return "high"
+		}
+	}
+	return "medium"
+}
+
+// phoneDigits returns the digits of an international number without its
+// extension. ok is false if the input does not start with a +.
+func phoneDigits(input string) (string, bool) {
+	input = strings.TrimSpace(input)
+	if !strings.HasPrefix(input, "+") {
+		return "", false
+	}
+	input = phoneExtension.ReplaceAllString(input, "")
+
+	var b strings.Builder
+	for _, c := range input {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String(), b.Len() > 0
+}
+
+// phoneCountry returns the lengths and country code of a known country code
+// the digits start with
+func phoneCountry(digits string) ([2]int, string, bool) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		if lengths, ok := phoneNumberLengths[digits[:n]]; ok {
+			return lengths, digits[:n], true
+		}
+	}
+	return [2]int{}, "", false
+}
+
+// dobMaxAge is the oldest plausible age in years for a date of birth
+const dobMaxAge = 120
+
+// dobDate matches a date at the end of a date of birth match in the
+// YYYY-MM-DD, MM/DD/YYYY or DD.MM.YYYY format
+var dobDate = regexp.MustCompile(`(?:(\d{4})-(\d{1,2})-(\d{1,2})|(\d{1,2})/(\d{1,2})/(\d{4})|(\d{1,2})\.(\d{1,2})\.(\d{4}))$`)
+
+// DateOfBirthValidator validates dates of birth by checking that the date
+// exists and gives an age between 0 and 120 years
+type DateOfBirthValidator struct{}
+
+// Validate checks that the date at the end of the input is a real calendar
+// date that is neither in the future nor more than dobMaxAge years ago
+func (v *DateOfBirthValidator) Validate(input string) bool {
+	m := dobDate.FindStringSubmatch(input)
+	if m == nil {
+		return false
+	}
+
+	var year, month, day string
+	switch {
+	case m[1] != "":
+		year, month, day = m[1], m[2], m[3]
+	case m[6] != "":
+		year, month, day = m[6], m[4], m[5]
+	default:
+		year, month, day = m[9], m[8], m[7]
+	}
+	y, _ := strconv.Atoi(year)
+	mo, _ := strconv.Atoi(month)
+	d, _ := strconv.Atoi(day)
+
+	birth := time.Date(y, time.Month(mo), d, 0, 0, 0, 0, time.UTC)
+	if birth.Year() != y || int(birth.Month()) != mo || birth.Day() != d {
+		return false
+	}
+
+	today := time.Now().UTC()
+	return !birth.After(today) && birth.After(today.AddDate(-dobMaxAge-1, 0, 0))
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, c := range s {
+		counts[c]++
+	}
+
+	entropy := 0.0
+	length := float64(len(s))
+	for _, n := range counts {
+		p := float64(n) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// IsTrivialNumber reports whether the digits of input are all the same
+// (111-11-1111) or run in sequence up or down (123456789, 98765), which
+// marks obvious test data rather than a real identifier. Non-digits are
+// ignored; fewer than two digits is never trivial.
+func IsTrivialNumber(input string) bool {
+	var digits []byte
+	for i := 0; i < len(input); i++ {
+		if input[i] >= '0' && input[i] <= '9' {
+			digits = append(digits, input[i]-'0')
+		}
+	}
+	if len(digits) < 2 {
+		return false
+	}
+
+	repeated, ascending, descending := true, true, true
+	for i := 1; i < len(digits); i++ {
+		step := (int(digits[i]) - int(digits[i-1]) + 10) % 10
+		repeated = repeated && step == 0
+		ascending = ascending && step == 1
+		descending = descending && step == 9
+	}
+	return repeated || ascending || descending
+}
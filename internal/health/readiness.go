@@ -0,0 +1,112 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/source"
+)
+
+// ReadinessMode controls how source sync failures affect readiness
+type ReadinessMode string
+
+const (
+	// ReadinessStrict reports not-ready while any community source is failing
+	ReadinessStrict ReadinessMode = "strict"
+
+	// ReadinessLenient reports not-ready only when every community source is failing
+	ReadinessLenient ReadinessMode = "lenient"
+)
+
+// ParseReadinessMode parses a readiness mode name
+func ParseReadinessMode(value string) (ReadinessMode, error) {
+	switch mode := ReadinessMode(value); mode {
+	case ReadinessStrict, ReadinessLenient:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown readiness mode %q (expected strict or lenient)", value)
+	}
+}
+
+// SourceSummary summarizes the sync health of cached community sources
+type SourceSummary struct {
+	// Total is the number of cached sources
+	Total int
+
+	// Failing lists the names of sources whose last sync failed
+	Failing []string
+}
+
+// String returns a short human-readable summary
+func (s SourceSummary) String() string {
+	if len(s.Failing) == 0 {
+		return fmt.Sprintf("%d/%d sources healthy", s.Total, s.Total)
+	}
+	return fmt.Sprintf("%d/%d sources healthy, failing: %s",
+		s.Total-len(s.Failing), s.Total, strings.Join(s.Failing, ", "))
+}
+
+// ReadinessChecker reports readiness based on the detection engine and the
+// community source cache
+type ReadinessChecker struct {
+	engine *detector.Engine
+	cache  *source.Cache
+	mode   ReadinessMode
+}
+
+// NewReadinessChecker creates a new readiness checker. The cache may be nil.
+func NewReadinessChecker(engine *detector.Engine, cache *source.Cache, mode ReadinessMode) *ReadinessChecker {
+	return &ReadinessChecker{
+		engine: engine,
+		cache:  cache,
+		mode:   mode,
+	}
+}
+
+// Summary returns the current source sync health
+func (c *ReadinessChecker) Summary() SourceSummary {
+	var summary SourceSummary
+	if c.cache == nil {
+		return summary
+	}
+
+	for _, name := range c.cache.ListSources() {
+		cached, ok := c.cache.GetSource(name)
+		if !ok {
+			continue
+		}
+		summary.Total++
+		if cached.Error != "" {
+			summary.Failing = append(summary.Failing, name)
+		}
+	}
+	sort.Strings(summary.Failing)
+
+	return summary
+}
+
+// Check implements healthz.Checker
+func (c *ReadinessChecker) Check(_ *http.Request) error {
+	if c.engine == nil || len(c.engine.ListPatterns()) == 0 {
+		return fmt.Errorf("detection engine has no patterns loaded")
+	}
+
+	summary := c.Summary()
+	if len(summary.Failing) == 0 {
+		return nil
+	}
+
+	switch c.mode {
+	case ReadinessStrict:
+		return fmt.Errorf("community source sync failing: %s", summary)
+	default:
+		if len(summary.Failing) == summary.Total {
+			return fmt.Errorf("all community sources failing: %s", summary)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/source"
+)
+
+func emptyEngine() *detector.Engine {
+	engine := detector.NewEngine()
+	for _, name := range engine.ListPatterns() {
+		engine.RemovePattern(name)
+	}
+	return engine
+}
+
+func TestReadinessChecker_Engine(t *testing.T) {
+	tests := []struct {
+		name      string
+		engine    *detector.Engine
+		wantReady bool
+	}{
+		{name: "nil engine", engine: nil, wantReady: false},
+		{name: "empty engine", engine: emptyEngine(), wantReady: false},
+		{name: "loaded engine", engine: detector.NewEngine(), wantReady: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewReadinessChecker(tt.engine, source.NewCache(), ReadinessStrict)
+			err := checker.Check(nil)
+			if (err == nil) != tt.wantReady {
+				t.Errorf("Check() error = %v, wantReady %v", err, tt.wantReady)
+			}
+		})
+	}
+}
+
+func TestReadinessChecker_Sources(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      ReadinessMode
+		healthy   bool
+		failing   bool
+		wantReady bool
+	}{
+		{name: "strict all healthy", mode: ReadinessStrict, healthy: true, wantReady: true},
+		{name: "strict partly failing", mode: ReadinessStrict, healthy: true, failing: true, wantReady: false},
+		{name: "lenient partly failing", mode: ReadinessLenient, healthy: true, failing: true, wantReady: true},
+		{name: "lenient all failing", mode: ReadinessLenient, failing: true, wantReady: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := source.NewCache()
+			if tt.healthy {
+				cache.SetSource("official", []*source.RuleSet{{Name: "korea"}})
+			}
+			if tt.failing {
+				cache.SetSourceError("mirror", "connection refused")
+			}
+
+			checker := NewReadinessChecker(detector.NewEngine(), cache, tt.mode)
+			err := checker.Check(nil)
+			if (err == nil) != tt.wantReady {
+				t.Errorf("Check() error = %v, wantReady %v", err, tt.wantReady)
+			}
+		})
+	}
+}
+
+func TestReadinessChecker_Summary(t *testing.T) {
+	cache := source.NewCache()
+	cache.SetSource("official", nil)
+	cache.SetSourceError("b-mirror", "timeout")
+	cache.SetSourceError("a-mirror", "timeout")
+
+	summary := NewReadinessChecker(detector.NewEngine(), cache, ReadinessLenient).Summary()
+	want := "1/3 sources healthy, failing: a-mirror, b-mirror"
+	if summary.String() != want {
+		t.Errorf("String() = %q, want %q", summary.String(), want)
+	}
+}
+
+func TestParseReadinessMode(t *testing.T) {
+	for _, value := range []string{"strict", "lenient"} {
+		if _, err := ParseReadinessMode(value); err != nil {
+			t.Errorf("ParseReadinessMode(%q) error = %v", value, err)
+		}
+	}
+	if _, err := ParseReadinessMode("eager"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
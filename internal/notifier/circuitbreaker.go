@@ -0,0 +1,186 @@
+package notifier
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults
+const (
+	// DefaultFailureThreshold is the number of consecutive failures that opens the breaker
+	DefaultFailureThreshold = 5
+
+	// DefaultBreakerCooldown is how long an open breaker fails fast before probing the channel
+	DefaultBreakerCooldown = time.Minute
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+// Circuit breaker states
+const (
+	// BreakerClosed lets sends through
+	BreakerClosed BreakerState = "closed"
+
+	// BreakerOpen fails sends fast until the cooldown elapses
+	BreakerOpen BreakerState = "open"
+
+	// BreakerHalfOpen lets a single probe through to test recovery
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker stops sending to a channel after consecutive failures.
+// Once the cooldown elapses it lets one probe through: success closes the
+// breaker, failure opens it for another cooldown.
+type CircuitBreaker struct {
+	mu sync.Mutex
+
+	// threshold is the number of consecutive failures that opens the breaker
+	threshold int
+
+	// cooldown is how long the breaker stays open
+	cooldown time.Duration
+
+	// state is the current breaker state
+	state BreakerState
+
+	// failures counts consecutive failures
+	failures int
+
+	// openedAt is when the breaker last opened
+	openedAt time.Time
+
+	// probing is true while a half-open probe is in flight
+	probing bool
+
+	// rejected counts sends that failed fast
+	rejected int64
+
+	// now returns the current time
+	now func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker. Non-positive values
+// use DefaultFailureThreshold and DefaultBreakerCooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultFailureThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     BreakerClosed,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a send may proceed. An open breaker whose cooldown
+// has elapsed becomes half-open and allows a single probe.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+		b.probing = false
+	}
+
+	switch b.state {
+	case BreakerOpen:
+		b.rejected++
+		return false
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// RecordSuccess records a successful send, closing the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure records a failed send, opening the breaker when the probe
+// fails or the failure threshold is reached
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Stats returns circuit breaker statistics
+func (b *CircuitBreaker) Stats() CircuitBreakerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := CircuitBreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Rejected:            b.rejected,
+	}
+	if b.state == BreakerOpen {
+		stats.OpenedAt = b.openedAt
+	}
+	return stats
+}
+
+// CircuitBreakerStats holds statistics about a circuit breaker
+type CircuitBreakerStats struct {
+	// State is the current breaker state
+	State BreakerState
+
+	// ConsecutiveFailures is the number of failures since the last success
+	ConsecutiveFailures int
+
+	// OpenedAt is when the breaker opened, zero unless it is open
+	OpenedAt time.Time
+
+	// Rejected is the number of sends that failed fast
+	Rejected int64
+}
+
+// CircuitOpenError is returned when an alert is not sent because the
+// channel's circuit breaker is open
+type CircuitOpenError struct {
+	Channel string
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for channel: %s", e.Channel)
+}
+
+// IsCircuitOpenError checks if an error is a circuit open error
+func IsCircuitOpenError(err error) bool {
+	_, ok := err.(*CircuitOpenError)
+	return ok
+}
@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAfterThreshold(t *testing.T) {
+	now := time.Now()
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		breaker.RecordFailure()
+		if breaker.State() != BreakerClosed {
+			t.Fatalf("State() = %s after %d failures, want closed", breaker.State(), i+1)
+		}
+	}
+
+	breaker.RecordFailure()
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("State() = %s after threshold, want open", breaker.State())
+	}
+	if breaker.Allow() {
+		t.Error("open breaker should fail fast")
+	}
+
+	// After the cooldown a single probe is let through
+	now = now.Add(time.Minute)
+	if !breaker.Allow() {
+		t.Fatal("breaker should allow a probe after the cooldown")
+	}
+	if breaker.State() != BreakerHalfOpen {
+		t.Errorf("State() = %s, want half-open", breaker.State())
+	}
+	if breaker.Allow() {
+		t.Error("half-open breaker should allow only one probe")
+	}
+
+	stats := breaker.Stats()
+	if stats.Rejected != 2 {
+		t.Errorf("Rejected = %d, want 2", stats.Rejected)
+	}
+}
+
+func TestCircuitBreaker_ProbeResult(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeed   bool
+		wantState BreakerState
+	}{
+		{name: "probe succeeds", succeed: true, wantState: BreakerClosed},
+		{name: "probe fails", succeed: false, wantState: BreakerOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			breaker := NewCircuitBreaker(1, time.Minute)
+			breaker.now = func() time.Time { return now }
+
+			breaker.RecordFailure()
+			now = now.Add(time.Minute)
+			if !breaker.Allow() {
+				t.Fatal("breaker should allow a probe after the cooldown")
+			}
+
+			if tt.succeed {
+				breaker.RecordSuccess()
+			} else {
+				breaker.RecordFailure()
+			}
+
+			if breaker.State() != tt.wantState {
+				t.Errorf("State() = %s, want %s", breaker.State(), tt.wantState)
+			}
+			if breaker.Allow() != tt.succeed {
+				t.Errorf("Allow() = %v, want %v", !tt.succeed, tt.succeed)
+			}
+		})
+	}
+}
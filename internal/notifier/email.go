@@ -20,6 +20,7 @@ type EmailNotifier struct {
 	password   string
 	useTLS     bool
 	skipVerify bool
+	timeout    time.Duration
 }
 
 // EmailConfig holds configuration for EmailNotifier
@@ -32,6 +33,10 @@ type EmailConfig struct {
 	Password   string
 	UseTLS     bool
 	SkipVerify bool // Skip TLS certificate verification (not recommended for production)
+
+	// Timeout bounds connecting to the SMTP server and the whole exchange
+	// that follows (DefaultTimeout when zero)
+	Timeout time.Duration
 }
 
 // NewEmailNotifier creates a new email notifier
@@ -49,6 +54,7 @@ func NewEmailNotifier(config EmailConfig) *EmailNotifier {
 		password:   config.Password,
 		useTLS:     config.UseTLS,
 		skipVerify: config.SkipVerify,
+		timeout:    timeoutOrDefault(config.Timeout),
 	}
 }
 
@@ -168,7 +174,7 @@ func (e *EmailNotifier) sendMail(ctx context.Context, message []byte) error {
 	var err error
 
 	dialer := &net.Dialer{
-		Timeout: 30 * time.Second,
+		Timeout: e.timeout,
 	}
 
 	if e.useTLS {
@@ -186,6 +192,12 @@ func (e *EmailNotifier) sendMail(ctx context.Context, message []byte) error {
 	}
 	defer conn.Close()
 
+	// Bound the SMTP exchange, so a server that stops responding cannot
+	// block the send indefinitely
+	if err := conn.SetDeadline(time.Now().Add(e.timeout)); err != nil {
+		return fmt.Errorf("failed to set SMTP deadline: %w", err)
+	}
+
 	client, err := smtp.NewClient(conn, e.smtpHost)
 	if err != nil {
 		return fmt.Errorf("failed to create SMTP client: %w", err)
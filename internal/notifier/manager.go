@@ -2,10 +2,15 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
 
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
 // Manager manages multiple notification channels
@@ -14,18 +19,52 @@ type Manager struct {
 	notifiers    map[string]Notifier
 	configs      map[string]NotifierConfig
 	rateLimiters *RateLimiterRegistry
+	breakers     map[string]*CircuitBreaker
+
+	// severities ranks severities for the channels' MinSeverity filter
+	// (the default scale when nil)
+	severities patterns.SeverityScale
+
+	// closed rejects new alerts once shutdown has begun, and inFlight
+	// tracks the sends shutdown waits for
+	closed   bool
+	inFlight sync.WaitGroup
 }
 
+// ErrManagerClosed is returned when sending through a manager that has shut down
+var ErrManagerClosed = errors.New("notifier manager is shut down")
+
 // NewManager creates a new notification manager
 func NewManager() *Manager {
 	return &Manager{
 		notifiers:    make(map[string]Notifier),
 		configs:      make(map[string]NotifierConfig),
 		rateLimiters: NewRateLimiterRegistry(),
+		breakers:     make(map[string]*CircuitBreaker),
 	}
 }
 
-// Register registers a notifier with the given name
+// SetSeverityScale sets how severities rank against each channel's
+// MinSeverity. A nil scale restores the default four levels.
+func (m *Manager) SetSeverityScale(scale patterns.SeverityScale) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.severities = scale
+}
+
+// SeverityScale returns the scale severities are ranked by
+func (m *Manager) SeverityScale() patterns.SeverityScale {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.severities == nil {
+		return patterns.DefaultSeverityScale
+	}
+	return m.severities
+}
+
+// Register registers a notifier with the given name. Registering a name
+// again keeps its circuit breaker, and so its open or half-open state, unless
+// the breaker's threshold or cooldown changed.
 func (m *Manager) Register(name string, notifier Notifier, config NotifierConfig) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -34,8 +73,12 @@ func (m *Manager) Register(name string, notifier Notifier, config NotifierConfig
 		return fmt.Errorf("invalid notifier configuration: %w", err)
 	}
 
+	previous, registered := m.configs[name]
 	m.notifiers[name] = notifier
 	m.configs[name] = config
+	if !registered || previous.FailureThreshold != config.FailureThreshold || previous.BreakerCooldown != config.BreakerCooldown {
+		m.breakers[name] = NewCircuitBreaker(config.FailureThreshold, config.BreakerCooldown)
+	}
 
 	// Setup rate limiter
 	if config.RateLimitPerMinute > 0 {
@@ -52,6 +95,7 @@ func (m *Manager) Unregister(name string) {
 
 	delete(m.notifiers, name)
 	delete(m.configs, name)
+	delete(m.breakers, name)
 	m.rateLimiters.Remove(name)
 }
 
@@ -69,8 +113,16 @@ func (m *Manager) SendAlert(ctx context.Context, channelName string, alert *Aler
 	logger := log.FromContext(ctx)
 
 	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return ErrManagerClosed
+	}
+	m.inFlight.Add(1)
+	defer m.inFlight.Done()
 	notifier, exists := m.notifiers[channelName]
 	config, configExists := m.configs[channelName]
+	breaker := m.breakers[channelName]
+	severities := m.severities
 	m.mu.RUnlock()
 
 	if !exists {
@@ -79,7 +131,7 @@ func (m *Manager) SendAlert(ctx context.Context, channelName string, alert *Aler
 
 	// Check severity threshold
 	if configExists && config.MinSeverity != "" {
-		if !ShouldAlert(alert.Severity, config.MinSeverity) {
+		if !severities.AtLeast(alert.Severity, config.MinSeverity) {
 			logger.V(1).Info("Alert below severity threshold",
 				"channel", channelName,
 				"alertSeverity", alert.Severity,
@@ -96,41 +148,91 @@ func (m *Manager) SendAlert(ctx context.Context, channelName string, alert *Aler
 		}
 	}
 
+	// Fail fast while the channel's circuit breaker is open
+	if breaker != nil && !breaker.Allow() {
+		logger.V(1).Info("Alert short-circuited", "channel", channelName)
+		return &CircuitOpenError{Channel: channelName}
+	}
+
 	// Send the alert
 	if err := notifier.Send(ctx, alert); err != nil {
+		if breaker != nil {
+			breaker.RecordFailure()
+		}
 		return fmt.Errorf("failed to send alert via %s: %w", channelName, err)
 	}
+	if breaker != nil {
+		breaker.RecordSuccess()
+	}
 
 	logger.V(1).Info("Alert sent successfully", "channel", channelName, "alertID", alert.ID)
 	return nil
 }
 
-// SendAlertToChannels sends an alert to multiple channels
-func (m *Manager) SendAlertToChannels(ctx context.Context, channelNames []string, alert *Alert) map[string]error {
+// ChannelResult is the outcome of sending an alert through one channel
+type ChannelResult struct {
+	Channel string
+	Err     error
+}
+
+// SendAlertToChannels sends an alert to multiple channels one at a time, in
+// the given order. It returns the outcome of every attempt in that order
+// along with the errors keyed by channel.
+func (m *Manager) SendAlertToChannels(ctx context.Context, channelNames []string, alert *Alert) ([]ChannelResult, map[string]error) {
+	results := make([]ChannelResult, 0, len(channelNames))
 	errors := make(map[string]error)
 
 	for _, channelName := range channelNames {
-		if err := m.SendAlert(ctx, channelName, alert); err != nil {
+		err := m.SendAlert(ctx, channelName, alert)
+		results = append(results, ChannelResult{Channel: channelName, Err: err})
+		if err != nil {
 			errors[channelName] = err
 		}
 	}
 
-	return errors
+	return results, errors
 }
 
-// Broadcast sends an alert to all registered channels
-func (m *Manager) Broadcast(ctx context.Context, alert *Alert) map[string]error {
-	m.mu.RLock()
-	channelNames := make([]string, 0, len(m.notifiers))
-	for name := range m.notifiers {
-		channelNames = append(channelNames, name)
+// Broadcast sends an alert to all registered channels in name order
+func (m *Manager) Broadcast(ctx context.Context, alert *Alert) ([]ChannelResult, map[string]error) {
+	return m.SendAlertToChannels(ctx, m.List(), alert)
+}
+
+// Shutdown stops accepting alerts, waits for in-flight sends to finish and
+// closes the notifiers' connections. If the context is done first, the
+// notifiers are left open for the sends still running.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		m.inFlight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		return fmt.Errorf("alerts still in flight: %w", ctx.Err())
 	}
-	m.mu.RUnlock()
 
-	return m.SendAlertToChannels(ctx, channelNames, alert)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var errs []error
+	for name, notifier := range m.notifiers {
+		if closer, ok := notifier.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close notifier %s: %w", name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// List returns all registered channel names
+// List returns all registered channel names in sorted order
 func (m *Manager) List() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -139,9 +241,22 @@ func (m *Manager) List() []string {
 	for name := range m.notifiers {
 		names = append(names, name)
 	}
+	sort.Strings(names)
 	return names
 }
 
+// BreakerStats returns the circuit breaker statistics of a channel
+func (m *Manager) BreakerStats(name string) (CircuitBreakerStats, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	breaker, exists := m.breakers[name]
+	if !exists {
+		return CircuitBreakerStats{}, false
+	}
+	return breaker.Stats(), true
+}
+
 // Stats returns statistics for all channels
 func (m *Manager) Stats() map[string]ChannelStats {
 	m.mu.RLock()
@@ -160,6 +275,10 @@ func (m *Manager) Stats() map[string]ChannelStats {
 		if config, exists := m.configs[name]; exists {
 			channelStats.MinSeverity = config.MinSeverity
 		}
+		if breaker, exists := m.breakers[name]; exists {
+			breakerStats := breaker.Stats()
+			channelStats.CircuitBreaker = &breakerStats
+		}
 		stats[name] = channelStats
 	}
 
@@ -186,9 +305,10 @@ func (m *Manager) UpdateConfig(name string, config NotifierConfig) error {
 
 // ChannelStats holds statistics for a notification channel
 type ChannelStats struct {
-	Type        string
-	MinSeverity string
-	RateLimiter *RateLimiterStats
+	Type           string
+	MinSeverity    string
+	RateLimiter    *RateLimiterStats
+	CircuitBreaker *CircuitBreakerStats
 }
 
 // RateLimitError is returned when an alert is rate limited
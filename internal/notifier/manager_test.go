@@ -2,8 +2,12 @@ package notifier
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
 // mockNotifier is a simple mock notifier for testing
@@ -131,7 +135,7 @@ func TestManager_Broadcast(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	errors := manager.Broadcast(ctx, alert)
+	_, errors := manager.Broadcast(ctx, alert)
 
 	if len(errors) != 0 {
 		t.Errorf("Expected no errors, got %v", errors)
@@ -146,6 +150,84 @@ func TestManager_Broadcast(t *testing.T) {
 	}
 }
 
+// orderedNotifier appends its channel name to a shared log on every send
+type orderedNotifier struct {
+	mockNotifier
+	name string
+	log  *[]string
+}
+
+func (o *orderedNotifier) Send(ctx context.Context, alert *Alert) error {
+	*o.log = append(*o.log, o.name)
+	return o.mockNotifier.Send(ctx, alert)
+}
+
+func TestManager_DeterministicOrder(t *testing.T) {
+	manager := NewManager()
+	var attempts []string
+	for _, name := range []string{"pagerduty", "email", "webhook", "slack", "teams"} {
+		n := &orderedNotifier{mockNotifier: mockNotifier{typeStr: "mock"}, name: name, log: &attempts}
+		if name == "slack" {
+			n.sendError = errors.New("webhook rejected")
+		}
+		// Keep the failing channel's breaker closed across the repeated runs
+		manager.Register(name, n, NotifierConfig{FailureThreshold: 100})
+	}
+	alert := &Alert{ID: "order-1", Severity: SeverityHigh}
+
+	tests := []struct {
+		name     string
+		send     func() ([]ChannelResult, map[string]error)
+		expected []string
+	}{
+		{
+			name:     "broadcast in name order",
+			send:     func() ([]ChannelResult, map[string]error) { return manager.Broadcast(context.Background(), alert) },
+			expected: []string{"email", "pagerduty", "slack", "teams", "webhook"},
+		},
+		{
+			name: "channels in the given order",
+			send: func() ([]ChannelResult, map[string]error) {
+				return manager.SendAlertToChannels(context.Background(), []string{"webhook", "missing", "slack", "email"}, alert)
+			},
+			expected: []string{"webhook", "missing", "slack", "email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for run := 0; run < 20; run++ {
+				attempts = nil
+				results, errs := tt.send()
+
+				var channels []string
+				for _, result := range results {
+					channels = append(channels, result.Channel)
+					if (result.Err != nil) != (errs[result.Channel] != nil) {
+						t.Errorf("run %d: %s result error %v, error map %v", run, result.Channel, result.Err, errs[result.Channel])
+					}
+				}
+				if !reflect.DeepEqual(channels, tt.expected) {
+					t.Fatalf("run %d: results in order %v, want %v", run, channels, tt.expected)
+				}
+
+				var delivered []string
+				for _, name := range tt.expected {
+					if name != "missing" {
+						delivered = append(delivered, name)
+					}
+				}
+				if !reflect.DeepEqual(attempts, delivered) {
+					t.Fatalf("run %d: delivery attempts %v, want %v", run, attempts, delivered)
+				}
+				if errs["slack"] == nil || len(errs) != len(tt.expected)-len(delivered)+1 {
+					t.Errorf("run %d: errors = %v", run, errs)
+				}
+			}
+		})
+	}
+}
+
 func TestManager_List(t *testing.T) {
 	manager := NewManager()
 
@@ -216,6 +298,30 @@ func TestManager_SeverityFiltering(t *testing.T) {
 	}
 }
 
+func TestManager_SeverityFilteringCustomScale(t *testing.T) {
+	manager := NewManager()
+	manager.SetSeverityScale(patterns.SeverityScale{"sev1": 10, "sev2": 7, "sev3": 3})
+
+	mock := &mockNotifier{typeStr: "mock"}
+	manager.Register("test-channel", mock, NotifierConfig{MinSeverity: "sev2"})
+
+	ctx := context.Background()
+	for _, severity := range []string{"sev3", "sev2", "sev1", SeverityCritical} {
+		alert := &Alert{ID: severity, Severity: severity, Timestamp: time.Now()}
+		if err := manager.SendAlert(ctx, "test-channel", alert); err != nil {
+			t.Errorf("SendAlert(%s) error = %v", severity, err)
+		}
+	}
+
+	var sent []string
+	for _, alert := range mock.sent {
+		sent = append(sent, alert.Severity)
+	}
+	if want := []string{"sev2", "sev1"}; !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent severities = %v, want %v", sent, want)
+	}
+}
+
 func TestManager_Stats(t *testing.T) {
 	manager := NewManager()
 
@@ -242,3 +348,138 @@ func TestManager_Stats(t *testing.T) {
 		t.Errorf("MinSeverity = %s, want %s", channelStats.MinSeverity, SeverityMedium)
 	}
 }
+
+func TestManager_CircuitBreaker(t *testing.T) {
+	manager := NewManager()
+
+	mock := &mockNotifier{typeStr: "mock", sendError: errors.New("connection refused")}
+	config := NotifierConfig{FailureThreshold: 2, BreakerCooldown: 50 * time.Millisecond}
+	manager.Register("flaky-channel", mock, config)
+
+	alert := NewAlert("email", "default", "PII detected")
+	ctx := context.Background()
+
+	// Consecutive failures open the breaker
+	for i := 0; i < 2; i++ {
+		if err := manager.SendAlert(ctx, "flaky-channel", alert); err == nil || IsCircuitOpenError(err) {
+			t.Fatalf("send %d: error = %v, want send failure", i, err)
+		}
+	}
+
+	// Subsequent sends fail fast without reaching the notifier
+	mock.sendError = nil
+	if err := manager.SendAlert(ctx, "flaky-channel", alert); !IsCircuitOpenError(err) {
+		t.Fatalf("error = %v, want circuit open error", err)
+	}
+	if len(mock.sent) != 0 {
+		t.Errorf("notifier received %d alerts while the breaker was open", len(mock.sent))
+	}
+
+	stats := manager.Stats()["flaky-channel"]
+	if stats.CircuitBreaker == nil || stats.CircuitBreaker.State != BreakerOpen {
+		t.Fatalf("CircuitBreaker stats = %+v, want open", stats.CircuitBreaker)
+	}
+
+	// After the cooldown the probe succeeds and the breaker closes
+	time.Sleep(60 * time.Millisecond)
+	if err := manager.SendAlert(ctx, "flaky-channel", alert); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	if len(mock.sent) != 1 {
+		t.Errorf("notifier received %d alerts, want 1", len(mock.sent))
+	}
+	if state := manager.Stats()["flaky-channel"].CircuitBreaker.State; state != BreakerClosed {
+		t.Errorf("State = %s, want closed", state)
+	}
+}
+
+func TestManager_RegisterKeepsBreaker(t *testing.T) {
+	manager := NewManager()
+	ctx := context.Background()
+	alert := NewAlert("email", "default", "PII detected")
+
+	config := NotifierConfig{FailureThreshold: 1, BreakerCooldown: time.Hour}
+	manager.Register("flaky-channel", &mockNotifier{typeStr: "mock", sendError: errors.New("connection refused")}, config)
+	if err := manager.SendAlert(ctx, "flaky-channel", alert); err == nil {
+		t.Fatal("SendAlert() error = nil, want send failure")
+	}
+
+	// Reconciling the channel registers it again with the same breaker settings
+	manager.Register("flaky-channel", &mockNotifier{typeStr: "mock"}, NotifierConfig{FailureThreshold: 1, BreakerCooldown: time.Hour, MinSeverity: "high"})
+	if stats, ok := manager.BreakerStats("flaky-channel"); !ok || stats.State != BreakerOpen {
+		t.Fatalf("BreakerStats() = %+v, %v, want open", stats, ok)
+	}
+
+	// New breaker settings start a new breaker
+	manager.Register("flaky-channel", &mockNotifier{typeStr: "mock"}, NotifierConfig{FailureThreshold: 3, BreakerCooldown: time.Hour})
+	if stats, _ := manager.BreakerStats("flaky-channel"); stats.State != BreakerClosed {
+		t.Errorf("State = %s after changing the breaker settings, want closed", stats.State)
+	}
+
+	if _, ok := manager.BreakerStats("unknown"); ok {
+		t.Error("BreakerStats() found an unregistered channel")
+	}
+}
+
+// blockingNotifier is a notifier whose Send blocks until released
+type blockingNotifier struct {
+	mockNotifier
+	started chan struct{}
+	release chan struct{}
+	closed  bool
+}
+
+func (b *blockingNotifier) Send(ctx context.Context, alert *Alert) error {
+	close(b.started)
+	<-b.release
+	return b.mockNotifier.Send(ctx, alert)
+}
+
+func (b *blockingNotifier) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestManager_Shutdown(t *testing.T) {
+	manager := NewManager()
+	n := &blockingNotifier{
+		mockNotifier: mockNotifier{typeStr: "mock"},
+		started:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	if err := manager.Register("test", n, NotifierConfig{}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- manager.SendAlert(context.Background(), "test", &Alert{ID: "in-flight"})
+	}()
+	<-n.started
+
+	// Shutdown waits for the in-flight alert
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	err := manager.Shutdown(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() with an alert in flight error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n.closed {
+		t.Error("notifier closed while an alert was in flight")
+	}
+
+	close(n.release)
+	if err := manager.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if err := <-sent; err != nil {
+		t.Errorf("in-flight SendAlert() error = %v", err)
+	}
+	if len(n.sent) != 1 || !n.closed {
+		t.Errorf("sent %d alerts, closed %v; want 1 alert and closed notifier", len(n.sent), n.closed)
+	}
+
+	if err := manager.SendAlert(context.Background(), "test", &Alert{ID: "late"}); !errors.Is(err, ErrManagerClosed) {
+		t.Errorf("SendAlert() after shutdown error = %v, want %v", err, ErrManagerClosed)
+	}
+}
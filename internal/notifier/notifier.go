@@ -2,9 +2,15 @@ package notifier
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
 // Severity levels for alerts
@@ -17,7 +23,8 @@ const (
 
 // Alert represents a PII detection alert
 type Alert struct {
-	// ID is a unique identifier for this alert
+	// ID identifies the logical alert. It is derived from the alert content, so
+	// the same detection produces the same ID across retries and restarts.
 	ID string `json:"id"`
 
 	// Severity is the alert severity level
@@ -61,6 +68,27 @@ type Alert struct {
 
 	// Labels contains additional metadata
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// Breakdown summarizes detections per pattern for aggregated alerts
+	Breakdown []PatternCount `json:"breakdown,omitempty"`
+
+	// dedupKey is the deduplication key rendered by WithDedupKey
+	dedupKey string
+}
+
+// PatternCount is the number of detections for a single pattern
+type PatternCount struct {
+	// PatternName is the name of the pattern
+	PatternName string `json:"patternName"`
+
+	// DisplayName is the human-readable pattern name
+	DisplayName string `json:"displayName,omitempty"`
+
+	// Severity is the pattern severity level
+	Severity string `json:"severity"`
+
+	// Count is the number of matches for the pattern
+	Count int `json:"count"`
 }
 
 // Notifier defines the interface for sending alerts
@@ -75,6 +103,18 @@ type Notifier interface {
 	Validate() error
 }
 
+// DefaultTimeout bounds a single delivery attempt of channels that do not
+// configure a timeout
+const DefaultTimeout = 30 * time.Second
+
+// timeoutOrDefault returns timeout, or DefaultTimeout if it is not positive
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultTimeout
+	}
+	return timeout
+}
+
 // NotifierConfig holds common configuration for notifiers
 type NotifierConfig struct {
 	// MinSeverity is the minimum severity level to send alerts for
@@ -82,22 +122,19 @@ type NotifierConfig struct {
 
 	// RateLimitPerMinute limits the number of alerts per minute
 	RateLimitPerMinute int
+
+	// FailureThreshold is the number of consecutive send failures that opens
+	// the channel's circuit breaker (DefaultFailureThreshold when zero)
+	FailureThreshold int
+
+	// BreakerCooldown is how long an open circuit breaker fails fast before
+	// probing the channel again (DefaultBreakerCooldown when zero)
+	BreakerCooldown time.Duration
 }
 
 // SeverityLevel returns numeric severity for comparison
 func SeverityLevel(severity string) int {
-	switch severity {
-	case SeverityCritical:
-		return 4
-	case SeverityHigh:
-		return 3
-	case SeverityMedium:
-		return 2
-	case SeverityLow:
-		return 1
-	default:
-		return 0
-	}
+	return patterns.SeverityLevel(severity)
 }
 
 // ShouldAlert returns true if the alert severity meets the minimum threshold
@@ -107,8 +144,7 @@ func ShouldAlert(alertSeverity, minSeverity string) bool {
 
 // NewAlert creates a new alert with the given parameters
 func NewAlert(patternName, namespace, message string) *Alert {
-	return &Alert{
-		ID:          generateAlertID(),
+	alert := &Alert{
 		PatternName: patternName,
 		Namespace:   namespace,
 		Message:     message,
@@ -116,11 +152,127 @@ func NewAlert(patternName, namespace, message string) *Alert {
 		Severity:    SeverityMedium,
 		Labels:      make(map[string]string),
 	}
+	alert.ID = alert.Fingerprint()
+	return alert
 }
 
-// generateAlertID generates a unique alert ID
-func generateAlertID() string {
-	return time.Now().Format("20060102150405.000000000")
+// NewAggregatedAlert creates a single alert covering all detections in a log entry.
+// The alert carries a per-pattern breakdown, ordered by severity and then count,
+// and takes the highest severity among the detections.
+func NewAggregatedAlert(entry detector.LogEntry, detections []detector.DetectionResult) *Alert {
+	counts := make(map[string]*PatternCount)
+	severity := ""
+	for _, d := range detections {
+		pc, ok := counts[d.PatternName]
+		if !ok {
+			pc = &PatternCount{
+				PatternName: d.PatternName,
+				DisplayName: d.DisplayName,
+				Severity:    d.Severity,
+			}
+			counts[d.PatternName] = pc
+		}
+		pc.Count++
+
+		if SeverityLevel(d.Severity) > SeverityLevel(severity) {
+			severity = d.Severity
+		}
+	}
+
+	breakdown := make([]PatternCount, 0, len(counts))
+	for _, pc := range counts {
+		breakdown = append(breakdown, *pc)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		li, lj := SeverityLevel(breakdown[i].Severity), SeverityLevel(breakdown[j].Severity)
+		if li != lj {
+			return li > lj
+		}
+		if breakdown[i].Count != breakdown[j].Count {
+			return breakdown[i].Count > breakdown[j].Count
+		}
+		return breakdown[i].PatternName < breakdown[j].PatternName
+	})
+
+	names := make([]string, len(breakdown))
+	for i, pc := range breakdown {
+		names[i] = pc.PatternName
+	}
+
+	message := fmt.Sprintf("Detected %d PII match(es) across %d pattern(s)", len(detections), len(breakdown))
+	alert := NewAlert(strings.Join(names, ","), entry.Namespace, message).
+		WithPod(entry.Pod, entry.Container).
+		WithDetections(detections)
+	if severity != "" {
+		alert.Severity = severity
+	}
+	if len(breakdown) == 1 {
+		alert.PatternDisplayName = breakdown[0].DisplayName
+	}
+	alert.Breakdown = breakdown
+
+	return alert
+}
+
+// BreakdownSummary renders the per-pattern breakdown as one line per pattern
+func (a *Alert) BreakdownSummary() string {
+	lines := make([]string, 0, len(a.Breakdown))
+	for _, pc := range a.Breakdown {
+		name := pc.PatternName
+		if pc.DisplayName != "" {
+			name = pc.DisplayName
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s): %d", name, pc.Severity, pc.Count))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Fingerprint returns a stable hash of the alert's identifying content: the
+// namespace, pattern, pod and the set of matched values. Timestamps and
+// messages are ignored so that repeated alerts for the same issue share it.
+func (a *Alert) Fingerprint() string {
+	matched := make([]string, 0, len(a.Detections))
+	for _, d := range a.Detections {
+		matched = append(matched, d.PatternName+"\x00"+d.MatchedText)
+	}
+	sort.Strings(matched)
+
+	h := sha256.New()
+	for _, field := range []string{a.Namespace, a.PatternName, a.Pod} {
+		h.Write([]byte(field))
+		h.Write([]byte{0})
+	}
+	for i, m := range matched {
+		if i > 0 && m == matched[i-1] {
+			continue
+		}
+		h.Write([]byte(m))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// DedupKey returns the key used by notification services to deduplicate
+// repeated alerts for the same logical detection: the key rendered by
+// WithDedupKey, or one derived from the alert's fingerprint
+func (a *Alert) DedupKey() string {
+	if a.dedupKey != "" {
+		return a.dedupKey
+	}
+	return "pii-" + a.Fingerprint()
+}
+
+// WithDedupKey renders the alert's deduplication key from a template. The
+// key is rendered once, so set the alert's other fields first. A nil
+// template, or one that fails to render, keeps the default key.
+func (a *Alert) WithDedupKey(t *DedupKeyTemplate) *Alert {
+	if t == nil {
+		return a
+	}
+	if key, err := t.Render(a); err == nil {
+		a.dedupKey = key
+	}
+	return a
 }
 
 // WithSeverity sets the severity on the alert
@@ -133,6 +285,7 @@ func (a *Alert) WithSeverity(severity string) *Alert {
 func (a *Alert) WithPod(pod, container string) *Alert {
 	a.Pod = pod
 	a.Container = container
+	a.ID = a.Fingerprint()
 	return a
 }
 
@@ -140,6 +293,7 @@ func (a *Alert) WithPod(pod, container string) *Alert {
 func (a *Alert) WithDetections(detections []detector.DetectionResult) *Alert {
 	a.Detections = detections
 	a.MatchCount = len(detections)
+	a.ID = a.Fingerprint()
 	return a
 }
 
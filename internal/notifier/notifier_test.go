@@ -1,7 +1,15 @@
 package notifier
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
 )
 
 func TestSeverityLevel(t *testing.T) {
@@ -103,3 +111,280 @@ func TestAlertBuilder(t *testing.T) {
 		t.Errorf("Labels[key] = %s, want value", alert.Labels["key"])
 	}
 }
+
+func TestNewAggregatedAlert(t *testing.T) {
+	entry := detector.LogEntry{
+		Namespace: "payments",
+		Pod:       "api-0",
+		Container: "app",
+	}
+	detections := []detector.DetectionResult{
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityMedium},
+		{PatternName: "korean-rrn", DisplayName: "Korean RRN", Severity: SeverityCritical},
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityMedium},
+		{PatternName: "phone-kr", DisplayName: "Korean Phone", Severity: SeverityMedium},
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityMedium},
+	}
+
+	alert := NewAggregatedAlert(entry, detections)
+
+	if alert.Severity != SeverityCritical {
+		t.Errorf("Severity = %s, want %s", alert.Severity, SeverityCritical)
+	}
+	if alert.MatchCount != 5 {
+		t.Errorf("MatchCount = %d, want 5", alert.MatchCount)
+	}
+	if alert.Namespace != "payments" || alert.Pod != "api-0" || alert.Container != "app" {
+		t.Errorf("unexpected location %s/%s/%s", alert.Namespace, alert.Pod, alert.Container)
+	}
+	if alert.PatternName != "korean-rrn,email,phone-kr" {
+		t.Errorf("PatternName = %s, want korean-rrn,email,phone-kr", alert.PatternName)
+	}
+
+	want := []PatternCount{
+		{PatternName: "korean-rrn", DisplayName: "Korean RRN", Severity: SeverityCritical, Count: 1},
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityMedium, Count: 3},
+		{PatternName: "phone-kr", DisplayName: "Korean Phone", Severity: SeverityMedium, Count: 1},
+	}
+	if len(alert.Breakdown) != len(want) {
+		t.Fatalf("Breakdown has %d entries, want %d", len(alert.Breakdown), len(want))
+	}
+	for i, pc := range want {
+		if alert.Breakdown[i] != pc {
+			t.Errorf("Breakdown[%d] = %+v, want %+v", i, alert.Breakdown[i], pc)
+		}
+	}
+
+	summary := "Korean RRN (critical): 1\nEmail Address (medium): 3\nKorean Phone (medium): 1"
+	if got := alert.BreakdownSummary(); got != summary {
+		t.Errorf("BreakdownSummary() = %q, want %q", got, summary)
+	}
+}
+
+func TestNewAggregatedAlert_SinglePattern(t *testing.T) {
+	detections := []detector.DetectionResult{
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityLow},
+		{PatternName: "email", DisplayName: "Email Address", Severity: SeverityLow},
+	}
+
+	alert := NewAggregatedAlert(detector.LogEntry{Namespace: "default"}, detections)
+
+	if alert.PatternName != "email" {
+		t.Errorf("PatternName = %s, want email", alert.PatternName)
+	}
+	if alert.PatternDisplayName != "Email Address" {
+		t.Errorf("PatternDisplayName = %s, want Email Address", alert.PatternDisplayName)
+	}
+	if alert.Severity != SeverityLow {
+		t.Errorf("Severity = %s, want %s", alert.Severity, SeverityLow)
+	}
+	if len(alert.Breakdown) != 1 || alert.Breakdown[0].Count != 2 {
+		t.Errorf("unexpected breakdown %+v", alert.Breakdown)
+	}
+}
+
+func TestAlert_StableID(t *testing.T) {
+	newAlert := func(namespace, pod, matched string) *Alert {
+		return NewAlert("email", namespace, "PII detected").
+			WithPod(pod, "app").
+			WithDetections([]detector.DetectionResult{{PatternName: "email", MatchedText: matched}})
+	}
+
+	base := newAlert("default", "api-0", "test@example.com")
+
+	tests := []struct {
+		name     string
+		alert    *Alert
+		wantSame bool
+	}{
+		{name: "identical alert", alert: newAlert("default", "api-0", "test@example.com"), wantSame: true},
+		{name: "different message", alert: func() *Alert {
+			a := newAlert("default", "api-0", "test@example.com")
+			a.Message = "retry"
+			return a
+		}(), wantSame: true},
+		{name: "different namespace", alert: newAlert("payments", "api-0", "test@example.com"), wantSame: false},
+		{name: "different pod", alert: newAlert("default", "api-1", "test@example.com"), wantSame: false},
+		{name: "different match", alert: newAlert("default", "api-0", "other@example.com"), wantSame: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.alert.ID == base.ID) != tt.wantSame {
+				t.Errorf("ID %s vs %s, wantSame %v", tt.alert.ID, base.ID, tt.wantSame)
+			}
+			if (tt.alert.DedupKey() == base.DedupKey()) != tt.wantSame {
+				t.Errorf("DedupKey %s vs %s, wantSame %v", tt.alert.DedupKey(), base.DedupKey(), tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestAlert_FingerprintIgnoresDetectionOrder(t *testing.T) {
+	detections := []detector.DetectionResult{
+		{PatternName: "email", MatchedText: "a@example.com"},
+		{PatternName: "email", MatchedText: "b@example.com"},
+	}
+	reversed := []detector.DetectionResult{detections[1], detections[0]}
+
+	a := NewAlert("email", "default", "").WithDetections(detections)
+	b := NewAlert("email", "default", "").WithDetections(reversed)
+	if a.ID != b.ID {
+		t.Errorf("IDs differ for the same detections in a different order: %s vs %s", a.ID, b.ID)
+	}
+}
+
+func TestNotifier_Timeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+
+	// The stub servers do not answer until the test is over
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	silentSMTP, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer silentSMTP.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			// Accept connections but never send the SMTP greeting
+			conn, err := silentSMTP.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	smtpPort := silentSMTP.Addr().(*net.TCPAddr).Port
+
+	pagerDuty := NewPagerDutyNotifier(PagerDutyConfig{RoutingKey: "key", Timeout: timeout})
+	pagerDuty.apiURL = slow.URL
+
+	notifiers := []Notifier{
+		NewSlackNotifier(SlackConfig{WebhookURL: slow.URL, Timeout: timeout}),
+		NewWebhookNotifier(WebhookConfig{URL: slow.URL, Timeout: timeout}),
+		pagerDuty,
+		NewEmailNotifier(EmailConfig{
+			SMTPHost: "127.0.0.1",
+			SMTPPort: smtpPort,
+			From:     "alerts@example.com",
+			To:       []string{"admin@example.com"},
+			Timeout:  timeout,
+		}),
+	}
+
+	for _, n := range notifiers {
+		t.Run(n.Type(), func(t *testing.T) {
+			start := time.Now()
+			err := n.Send(context.Background(), NewAlert("email", "default", "test"))
+			elapsed := time.Since(start)
+
+			var netErr net.Error
+			if !errors.As(err, &netErr) || !netErr.Timeout() {
+				t.Fatalf("Send() error = %v, want a timeout error", err)
+			}
+			if elapsed > 2*time.Second {
+				t.Errorf("Send() took %v, want it to give up after about %v", elapsed, timeout)
+			}
+		})
+	}
+}
+
+func TestTimeoutOrDefault(t *testing.T) {
+	tests := []struct {
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{timeout: 0, want: DefaultTimeout},
+		{timeout: -time.Second, want: DefaultTimeout},
+		{timeout: 5 * time.Second, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := timeoutOrDefault(tt.timeout); got != tt.want {
+			t.Errorf("timeoutOrDefault(%v) = %v, want %v", tt.timeout, got, tt.want)
+		}
+	}
+}
+
+func TestAlert_DedupKeyTemplate(t *testing.T) {
+	tmpl, err := ParseDedupKeyTemplate("{{ .Namespace }}/{{ .PatternName }}")
+	if err != nil {
+		t.Fatalf("ParseDedupKeyTemplate() error = %v", err)
+	}
+
+	// Two alerts for the same underlying issue, raised from different pods at
+	// different times
+	first := NewAlert("email", "payments", "PII detected").
+		WithPod("api-0", "app").
+		WithDetections([]detector.DetectionResult{{PatternName: "email", MatchedText: "a@example.com"}}).
+		WithDedupKey(tmpl)
+	second := NewAlert("email", "payments", "PII detected").
+		WithPod("api-1", "app").
+		WithDetections([]detector.DetectionResult{{PatternName: "email", MatchedText: "b@example.com"}}).
+		WithDedupKey(tmpl)
+	second.Timestamp = first.Timestamp.Add(time.Hour)
+
+	if first.ID == second.ID {
+		t.Fatalf("IDs should differ for alerts from different pods")
+	}
+	if first.DedupKey() != "payments/email" || second.DedupKey() != first.DedupKey() {
+		t.Errorf("DedupKey() = %q and %q, want both %q", first.DedupKey(), second.DedupKey(), "payments/email")
+	}
+
+	other := NewAlert("email", "default", "PII detected").WithDedupKey(tmpl)
+	if other.DedupKey() == first.DedupKey() {
+		t.Errorf("DedupKey() = %q for a different namespace", other.DedupKey())
+	}
+
+	// A nil template keeps the default key
+	alert := NewAlert("email", "payments", "PII detected")
+	if got := alert.WithDedupKey(nil).DedupKey(); got != "pii-"+alert.Fingerprint() {
+		t.Errorf("DedupKey() = %q, want the default key", got)
+	}
+
+	// PagerDuty deduplicates by the rendered key
+	event := NewPagerDutyNotifier(PagerDutyConfig{RoutingKey: "key"}).buildEvent(first)
+	if event.DedupKey != "payments/email" {
+		t.Errorf("PagerDuty dedup_key = %q, want %q", event.DedupKey, "payments/email")
+	}
+}
+
+func TestParseDedupKeyTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty", text: "", wantNil: true},
+		{name: "default", text: DefaultDedupKeyTemplate},
+		{name: "fields", text: "{{ .Namespace }}/{{ .Pod }}/{{ .PatternName }}"},
+		{name: "syntax error", text: "{{ .Namespace ", wantErr: true},
+		{name: "unknown field", text: "{{ .Nope }}", wantErr: true},
+		{name: "blank key", text: "{{ if false }}x{{ end }}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseDedupKeyTemplate(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDedupKeyTemplate(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if !tt.wantErr && (tmpl == nil) != tt.wantNil {
+				t.Errorf("ParseDedupKeyTemplate(%q) = %v, wantNil %v", tt.text, tmpl, tt.wantNil)
+			}
+		})
+	}
+}
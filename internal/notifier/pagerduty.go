@@ -23,6 +23,9 @@ type PagerDutyNotifier struct {
 type PagerDutyConfig struct {
 	RoutingKey string // Integration/routing key
 	Severity   string // critical, error, warning, info
+
+	// Timeout bounds each request to the Events API (DefaultTimeout when zero)
+	Timeout time.Duration
 }
 
 // NewPagerDutyNotifier creates a new PagerDuty notifier
@@ -36,7 +39,7 @@ func NewPagerDutyNotifier(config PagerDutyConfig) *PagerDutyNotifier {
 		severity:   config.Severity,
 		apiURL:     pagerDutyEventsAPIURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: timeoutOrDefault(config.Timeout),
 		},
 	}
 }
@@ -159,7 +162,7 @@ func (p *PagerDutyNotifier) buildEvent(alert *Alert) pagerDutyEvent {
 	return pagerDutyEvent{
 		RoutingKey:  p.routingKey,
 		EventAction: "trigger",
-		DedupKey:    fmt.Sprintf("pii-%s-%s-%s", alert.Namespace, alert.PatternName, alert.ID),
+		DedupKey:    alert.DedupKey(),
 		Payload: pagerDutyPayload{
 			Summary:       summary,
 			Source:        source,
@@ -193,3 +196,9 @@ func (p *PagerDutyNotifier) mapSeverity(severity string) string {
 func (p *PagerDutyNotifier) SetHTTPClient(client *http.Client) {
 	p.httpClient = client
 }
+
+// Close closes the notifier's idle connections
+func (p *PagerDutyNotifier) Close() error {
+	p.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -8,15 +8,20 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
 // SlackNotifier sends alerts to Slack via webhook
 type SlackNotifier struct {
-	webhookURL string
-	channel    string
-	username   string
-	iconEmoji  string
-	httpClient *http.Client
+	webhookURL  string
+	channel     string
+	username    string
+	iconEmoji   string
+	template    *MessageTemplate
+	templateErr error
+	severities  patterns.SeverityScale
+	httpClient  *http.Client
 }
 
 // SlackConfig holds configuration for SlackNotifier
@@ -25,6 +30,17 @@ type SlackConfig struct {
 	Channel    string
 	Username   string
 	IconEmoji  string
+
+	// MessageTemplate is an optional Go template over Alert for the message text
+	MessageTemplate string
+
+	// Timeout bounds each request to the webhook (DefaultTimeout when zero)
+	Timeout time.Duration
+
+	// SeverityScale ranks alert severities for the attachment color; custom
+	// severities take the color of the default level at the same relative rank
+	// (the default scale when nil)
+	SeverityScale patterns.SeverityScale
 }
 
 // NewSlackNotifier creates a new Slack notifier
@@ -36,13 +52,18 @@ func NewSlackNotifier(config SlackConfig) *SlackNotifier {
 		config.IconEmoji = ":shield:"
 	}
 
+	tmpl, err := ParseMessageTemplate(config.MessageTemplate)
+
 	return &SlackNotifier{
-		webhookURL: config.WebhookURL,
-		channel:    config.Channel,
-		username:   config.Username,
-		iconEmoji:  config.IconEmoji,
+		webhookURL:  config.WebhookURL,
+		channel:     config.Channel,
+		username:    config.Username,
+		iconEmoji:   config.IconEmoji,
+		template:    tmpl,
+		templateErr: err,
+		severities:  config.SeverityScale,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: timeoutOrDefault(config.Timeout),
 		},
 	}
 }
@@ -61,6 +82,9 @@ func (s *SlackNotifier) Validate() error {
 		!strings.HasPrefix(s.webhookURL, "https://") {
 		return fmt.Errorf("invalid slack webhook URL format")
 	}
+	if s.templateErr != nil {
+		return s.templateErr
+	}
 	return nil
 }
 
@@ -153,10 +177,14 @@ func (s *SlackNotifier) buildMessage(alert *Alert) slackMessage {
 		fields = append(fields, slackField{Title: "Source", Value: alert.Source, Short: true})
 	}
 
+	if len(alert.Breakdown) > 0 {
+		fields = append(fields, slackField{Title: "Breakdown", Value: alert.BreakdownSummary(), Short: false})
+	}
+
 	attachment := slackAttachment{
 		Color:     color,
 		Title:     title,
-		Text:      alert.Message,
+		Text:      renderMessage(s.template, alert),
 		Fields:    fields,
 		Footer:    "PII Redactor",
 		Timestamp: alert.Timestamp.Unix(),
@@ -172,7 +200,7 @@ func (s *SlackNotifier) buildMessage(alert *Alert) slackMessage {
 
 // severityColor returns the Slack color for a severity level
 func (s *SlackNotifier) severityColor(severity string) string {
-	switch severity {
+	switch s.severities.Band(severity) {
 	case SeverityCritical:
 		return "#dc3545" // red
 	case SeverityHigh:
@@ -190,3 +218,9 @@ func (s *SlackNotifier) severityColor(severity string) string {
 func (s *SlackNotifier) SetHTTPClient(client *http.Client) {
 	s.httpClient = client
 }
+
+// Close closes the notifier's idle connections
+func (s *SlackNotifier) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -7,6 +7,9 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
 func TestSlackNotifier_Type(t *testing.T) {
@@ -99,6 +102,35 @@ func TestSlackNotifier_Send(t *testing.T) {
 	}
 }
 
+func TestSlackNotifier_SeverityColor(t *testing.T) {
+	custom := NewSlackNotifier(SlackConfig{
+		WebhookURL:    "https://hooks.slack.com/test",
+		SeverityScale: patterns.SeverityScale{"sev1": 10, "sev2": 7, "sev3": 5, "sev4": 2},
+	})
+	standard := NewSlackNotifier(SlackConfig{WebhookURL: "https://hooks.slack.com/test"})
+
+	tests := []struct {
+		notifier *SlackNotifier
+		severity string
+		want     string
+	}{
+		{standard, SeverityCritical, "#dc3545"},
+		{standard, SeverityLow, "#17a2b8"},
+		{standard, "sev1", "#6c757d"},
+		{custom, "sev1", "#dc3545"},
+		{custom, "sev2", "#fd7e14"},
+		{custom, "sev3", "#ffc107"},
+		{custom, "sev4", "#17a2b8"},
+		{custom, SeverityCritical, "#6c757d"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.notifier.severityColor(tt.severity); got != tt.want {
+			t.Errorf("severityColor(%q) = %s, want %s", tt.severity, got, tt.want)
+		}
+	}
+}
+
 func TestSlackNotifier_SendError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -122,3 +154,100 @@ func TestSlackNotifier_SendError(t *testing.T) {
 		t.Error("Expected error for 500 response")
 	}
 }
+
+func TestSlackNotifier_MessageTemplate(t *testing.T) {
+	notifier := NewSlackNotifier(SlackConfig{
+		WebhookURL:      "https://hooks.slack.com/test",
+		MessageTemplate: "{{.MatchCount}} x {{.PatternName}} found in {{.Namespace}}/{{.Pod}}",
+	})
+
+	if err := notifier.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	alert := &Alert{
+		Severity:    SeverityHigh,
+		PatternName: "email",
+		Namespace:   "payments",
+		Pod:         "api-0",
+		Message:     "default message",
+		Timestamp:   time.Now(),
+		MatchCount:  3,
+	}
+
+	msg := notifier.buildMessage(alert)
+	if len(msg.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(msg.Attachments))
+	}
+
+	want := "3 x email found in payments/api-0"
+	if msg.Attachments[0].Text != want {
+		t.Errorf("Text = %q, want %q", msg.Attachments[0].Text, want)
+	}
+}
+
+func TestSlackNotifier_MessageTemplateDefault(t *testing.T) {
+	notifier := NewSlackNotifier(SlackConfig{
+		WebhookURL: "https://hooks.slack.com/test",
+	})
+
+	alert := &Alert{
+		PatternName: "email",
+		Message:     "default message",
+		Timestamp:   time.Now(),
+	}
+
+	msg := notifier.buildMessage(alert)
+	if msg.Attachments[0].Text != "default message" {
+		t.Errorf("Text = %q, want default message", msg.Attachments[0].Text)
+	}
+}
+
+func TestSlackNotifier_InvalidMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{name: "syntax error", template: "{{.PatternName"},
+		{name: "unknown field", template: "{{.NoSuchField}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := NewSlackNotifier(SlackConfig{
+				WebhookURL:      "https://hooks.slack.com/test",
+				MessageTemplate: tt.template,
+			})
+			if err := notifier.Validate(); err == nil {
+				t.Error("expected Validate() to fail for invalid template")
+			}
+		})
+	}
+}
+
+func TestSlackNotifier_AggregatedBreakdown(t *testing.T) {
+	notifier := NewSlackNotifier(SlackConfig{
+		WebhookURL: "https://hooks.slack.com/test",
+	})
+
+	alert := NewAggregatedAlert(detector.LogEntry{Namespace: "default"}, []detector.DetectionResult{
+		{PatternName: "email", Severity: SeverityMedium},
+		{PatternName: "credit-card", Severity: SeverityCritical},
+	})
+
+	msg := notifier.buildMessage(alert)
+
+	var breakdown *slackField
+	for i, f := range msg.Attachments[0].Fields {
+		if f.Title == "Breakdown" {
+			breakdown = &msg.Attachments[0].Fields[i]
+		}
+	}
+	if breakdown == nil {
+		t.Fatal("expected Breakdown field")
+	}
+	want := "credit-card (critical): 1\nemail (medium): 1"
+	if breakdown.Value != want {
+		t.Errorf("Breakdown = %q, want %q", breakdown.Value, want)
+	}
+}
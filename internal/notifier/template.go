@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// MessageTemplate renders the human-readable alert message from a Go template
+// evaluated against an Alert
+type MessageTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseMessageTemplate parses and test-renders a message template.
+// An empty string yields a nil template, which renders the alert message as-is.
+func ParseMessageTemplate(text string) (*MessageTemplate, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+
+	// Execute against an empty alert to catch references to unknown fields
+	if err := tmpl.Execute(io.Discard, &Alert{}); err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+
+	return &MessageTemplate{tmpl: tmpl}, nil
+}
+
+// Render renders the template for the given alert
+func (t *MessageTemplate) Render(alert *Alert) (string, error) {
+	if t == nil {
+		return alert.Message, nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf("failed to render message template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// renderMessage renders the alert message, falling back to the default
+// message if the template fails
+func renderMessage(t *MessageTemplate, alert *Alert) string {
+	message, err := t.Render(alert)
+	if err != nil {
+		return alert.Message
+	}
+	return message
+}
+
+// DefaultDedupKeyTemplate renders the default deduplication key of an alert,
+// derived from its fingerprint rather than its timestamp
+const DefaultDedupKeyTemplate = "pii-{{.Fingerprint}}"
+
+// maxDedupKeyLength is the longest deduplication key PagerDuty accepts;
+// longer rendered keys are replaced by their hash
+const maxDedupKeyLength = 255
+
+// DedupKeyTemplate renders the key notification services deduplicate alerts
+// by from a Go template evaluated against an Alert, so that repeated alerts
+// for the same underlying issue share a key
+type DedupKeyTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseDedupKeyTemplate parses and test-renders a deduplication key
+// template, e.g. "pii-{{.Namespace}}-{{.PatternName}}". An empty string
+// yields a nil template, which renders the alert's default key.
+func ParseDedupKeyTemplate(text string) (*DedupKeyTemplate, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("dedupKey").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deduplication key template: %w", err)
+	}
+
+	// Render a sample alert to catch references to unknown fields and
+	// templates that render no key at all
+	sample := NewAlert("email", "default", "sample")
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, sample); err != nil {
+		return nil, fmt.Errorf("invalid deduplication key template: %w", err)
+	}
+	if strings.TrimSpace(buf.String()) == "" {
+		return nil, fmt.Errorf("invalid deduplication key template: renders an empty key")
+	}
+
+	return &DedupKeyTemplate{tmpl: tmpl}, nil
+}
+
+// Render renders the deduplication key for the given alert. Keys longer
+// than PagerDuty accepts are replaced by a hash of the rendered key.
+func (t *DedupKeyTemplate) Render(alert *Alert) (string, error) {
+	if t == nil {
+		return "pii-" + alert.Fingerprint(), nil
+	}
+
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, alert); err != nil {
+		return "", fmt.Errorf("failed to render deduplication key template: %w", err)
+	}
+	key := strings.TrimSpace(buf.String())
+	if len(key) > maxDedupKeyLength {
+		sum := sha256.Sum256([]byte(key))
+		key = "pii-" + hex.EncodeToString(sum[:16])
+	}
+	return key, nil
+}
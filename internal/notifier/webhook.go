@@ -11,10 +11,12 @@ import (
 
 // WebhookNotifier sends alerts to a generic HTTP webhook
 type WebhookNotifier struct {
-	url        string
-	method     string
-	headers    map[string]string
-	httpClient *http.Client
+	url         string
+	method      string
+	headers     map[string]string
+	template    *MessageTemplate
+	templateErr error
+	httpClient  *http.Client
 }
 
 // WebhookConfig holds configuration for WebhookNotifier
@@ -22,6 +24,12 @@ type WebhookConfig struct {
 	URL     string
 	Method  string // POST or PUT
 	Headers map[string]string
+
+	// MessageTemplate is an optional Go template over Alert for the message field
+	MessageTemplate string
+
+	// Timeout bounds each request to the webhook (DefaultTimeout when zero)
+	Timeout time.Duration
 }
 
 // NewWebhookNotifier creates a new webhook notifier
@@ -33,12 +41,16 @@ func NewWebhookNotifier(config WebhookConfig) *WebhookNotifier {
 		config.Headers = make(map[string]string)
 	}
 
+	tmpl, err := ParseMessageTemplate(config.MessageTemplate)
+
 	return &WebhookNotifier{
-		url:     config.URL,
-		method:  config.Method,
-		headers: config.Headers,
+		url:         config.URL,
+		method:      config.Method,
+		headers:     config.Headers,
+		template:    tmpl,
+		templateErr: err,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: timeoutOrDefault(config.Timeout),
 		},
 	}
 }
@@ -56,6 +68,9 @@ func (w *WebhookNotifier) Validate() error {
 	if w.method != http.MethodPost && w.method != http.MethodPut {
 		return fmt.Errorf("webhook method must be POST or PUT")
 	}
+	if w.templateErr != nil {
+		return w.templateErr
+	}
 	return nil
 }
 
@@ -115,6 +130,7 @@ type webhookAlert struct {
 	PolicyName         string            `json:"policyName,omitempty"`
 	Source             string            `json:"source,omitempty"`
 	Labels             map[string]string `json:"labels,omitempty"`
+	Breakdown          []PatternCount    `json:"breakdown,omitempty"`
 }
 
 // buildPayload builds a webhook payload from an alert
@@ -130,11 +146,12 @@ func (w *WebhookNotifier) buildPayload(alert *Alert) webhookPayload {
 			Namespace:          alert.Namespace,
 			Pod:                alert.Pod,
 			Container:          alert.Container,
-			Message:            alert.Message,
+			Message:            renderMessage(w.template, alert),
 			MatchCount:         alert.MatchCount,
 			PolicyName:         alert.PolicyName,
 			Source:             alert.Source,
 			Labels:             alert.Labels,
+			Breakdown:          alert.Breakdown,
 		},
 		Metadata: map[string]interface{}{
 			"version": "1.0",
@@ -148,6 +165,12 @@ func (w *WebhookNotifier) SetHTTPClient(client *http.Client) {
 	w.httpClient = client
 }
 
+// Close closes the notifier's idle connections
+func (w *WebhookNotifier) Close() error {
+	w.httpClient.CloseIdleConnections()
+	return nil
+}
+
 // AddHeader adds or updates a header
 func (w *WebhookNotifier) AddHeader(key, value string) {
 	w.headers[key] = value
@@ -7,6 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
 )
 
 func TestWebhookNotifier_Type(t *testing.T) {
@@ -128,3 +130,30 @@ func TestWebhookNotifier_SendError(t *testing.T) {
 		t.Error("Expected error for 400 response")
 	}
 }
+
+func TestWebhookNotifier_AggregatedBreakdown(t *testing.T) {
+	notifier := NewWebhookNotifier(WebhookConfig{
+		URL: "https://example.com/webhook",
+	})
+
+	alert := NewAggregatedAlert(detector.LogEntry{Namespace: "default"}, []detector.DetectionResult{
+		{PatternName: "email", Severity: SeverityMedium},
+		{PatternName: "email", Severity: SeverityMedium},
+		{PatternName: "korean-rrn", Severity: SeverityCritical},
+	})
+
+	payload := notifier.buildPayload(alert)
+
+	if payload.Alert.Severity != SeverityCritical {
+		t.Errorf("Severity = %s, want %s", payload.Alert.Severity, SeverityCritical)
+	}
+	if payload.Alert.MatchCount != 3 {
+		t.Errorf("MatchCount = %d, want 3", payload.Alert.MatchCount)
+	}
+	if len(payload.Alert.Breakdown) != 2 {
+		t.Fatalf("expected 2 breakdown entries, got %d", len(payload.Alert.Breakdown))
+	}
+	if payload.Alert.Breakdown[1].PatternName != "email" || payload.Alert.Breakdown[1].Count != 2 {
+		t.Errorf("unexpected breakdown entry %+v", payload.Alert.Breakdown[1])
+	}
+}
@@ -3,6 +3,8 @@ package policy
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -77,11 +79,56 @@ func (a *Aggregator) AggregatePatterns(ctx context.Context, selection piiv1alpha
 		}
 	}
 
+	// Aggregate patterns selected by tag. Tagged custom patterns are only
+	// picked up from the policy's own namespace.
+	for _, tag := range selection.Tags {
+		names := a.engine.ListPatternsByTag(tag)
+		if len(names) == 0 {
+			result.Errors = append(result.Errors, fmt.Sprintf("no patterns tagged: %s", tag))
+			continue
+		}
+		for _, name := range names {
+			switch {
+			case patterns.IsBuiltInPattern(name):
+				result.BuiltInPatterns = appendMissing(result.BuiltInPatterns, name)
+			case strings.Contains(name, "/"):
+				if strings.HasPrefix(name, policyNamespace+"/") {
+					result.CustomPatterns = appendMissing(result.CustomPatterns, name)
+				}
+			default:
+				result.CommunityPatterns = appendMissing(result.CommunityPatterns, name)
+			}
+		}
+	}
+
+	// Validate overrides, which are scoped to built-in patterns
+	for _, override := range selection.Overrides {
+		if !patterns.IsBuiltInPattern(override.Pattern) {
+			result.Errors = append(result.Errors, fmt.Sprintf("override target is not a built-in pattern: %s", override.Pattern))
+			continue
+		}
+		if override.MaskingStrategy != nil {
+			if _, err := ConvertMaskingStrategy(*override.MaskingStrategy); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("invalid masking override for %s: %s", override.Pattern, err.Error()))
+				continue
+			}
+		}
+		result.Overrides = append(result.Overrides, override)
+	}
+
 	result.TotalPatterns = len(result.BuiltInPatterns) + len(result.CustomPatterns) + len(result.CommunityPatterns)
 
 	return result, nil
 }
 
+// appendMissing appends name to names unless it is already present
+func appendMissing(names []string, name string) []string {
+	if slices.Contains(names, name) {
+		return names
+	}
+	return append(names, name)
+}
+
 // AggregationResult holds the result of pattern aggregation
 type AggregationResult struct {
 	// BuiltInPatterns is the list of built-in pattern names
@@ -93,6 +140,9 @@ type AggregationResult struct {
 	// CommunityPatterns is the list of community pattern names
 	CommunityPatterns []string
 
+	// Overrides is the list of validated built-in pattern overrides
+	Overrides []piiv1alpha1.PatternOverride
+
 	// TotalPatterns is the total count of all patterns
 	TotalPatterns int
 
@@ -127,6 +177,74 @@ func (a *Aggregator) EnablePatterns(result *AggregationResult) error {
 	return nil
 }
 
+// ScopedEngine returns a snapshot of the engine with the aggregated built-in
+// patterns enabled and the policy's overrides applied. Changes made to the
+// snapshot do not leak into the shared engine or other policies.
+func (a *Aggregator) ScopedEngine(result *AggregationResult) *detector.Engine {
+	scoped := a.engine.Snapshot()
+
+	for _, name := range result.BuiltInPatterns {
+		scoped.EnablePattern(name)
+	}
+
+	for _, override := range result.Overrides {
+		if override.Severity != "" {
+			scoped.SetPatternSeverity(override.Pattern, override.Severity)
+		}
+
+		if override.Enabled != nil {
+			if *override.Enabled {
+				scoped.EnablePattern(override.Pattern)
+			} else {
+				scoped.DisablePattern(override.Pattern)
+			}
+		}
+
+		if override.MaskingStrategy != nil {
+			if strategy, err := ConvertMaskingStrategy(*override.MaskingStrategy); err == nil {
+				scoped.SetMaskingStrategy(override.Pattern, strategy)
+			}
+		}
+	}
+
+	return scoped
+}
+
+// convertMaskingStrategy converts a CRD masking strategy to the internal representation
+func ConvertMaskingStrategy(m piiv1alpha1.MaskingStrategy) (patterns.MaskingStrategy, error) {
+	showFirst, showFirstPercent, err := patterns.ParseRevealAmount(m.ShowFirst)
+	if err != nil {
+		return patterns.MaskingStrategy{}, fmt.Errorf("showFirst: %w", err)
+	}
+	showLast, showLastPercent, err := patterns.ParseRevealAmount(m.ShowLast)
+	if err != nil {
+		return patterns.MaskingStrategy{}, fmt.Errorf("showLast: %w", err)
+	}
+
+	strategy := patterns.MaskingStrategy{
+		Type:             m.Type,
+		ShowFirst:        showFirst,
+		ShowLast:         showLast,
+		ShowFirstPercent: showFirstPercent,
+		ShowLastPercent:  showLastPercent,
+		MaskChar:         m.MaskChar,
+		Replacement:      m.Replacement,
+		Delimiter:        m.Delimiter,
+
+		PreserveBoundaryLines: m.PreserveBoundaryLines,
+		Group:                 m.Group,
+	}
+	for i, step := range m.Chain {
+		converted, err := ConvertMaskingStrategy(step.Strategy())
+		if err != nil {
+			return patterns.MaskingStrategy{}, fmt.Errorf("chain[%d].%w", i, err)
+		}
+		strategy.Chain = append(strategy.Chain, converted)
+	}
+
+	return strategy, nil
+}
+
 // DisableAllExcept disables all patterns except the specified ones
 func (a *Aggregator) DisableAllExcept(keepPatterns []string) {
 	keepSet := make(map[string]struct{})
@@ -2,6 +2,9 @@ package policy
 
 import (
 	"context"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -10,6 +13,8 @@ import (
 
 	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
 	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
 )
 
 func TestAggregator_AggregateBuiltInPatterns(t *testing.T) {
@@ -141,6 +146,66 @@ func TestAggregator_AggregateCustomPatterns_NotFound(t *testing.T) {
 	}
 }
 
+func TestAggregator_AggregateTaggedPatterns(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = piiv1alpha1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	engine := detector.NewEngine()
+	hipaa := patterns.PIIPatternSpec{
+		Tags:     []string{"hipaa"},
+		Patterns: []patterns.PatternRule{{Regex: `PAT-\d{6}`, Confidence: "high"}},
+		Severity: "high",
+	}
+	for _, name := range []string{"patient-id", "default/chart-number", "other/chart-number"} {
+		if err := engine.AddPattern(name, hipaa); err != nil {
+			t.Fatalf("AddPattern(%s) error = %v", name, err)
+		}
+	}
+	aggregator := NewAggregator(fakeClient, engine)
+
+	selection := piiv1alpha1.PatternSelection{
+		BuiltIn: []string{"email"},
+		Tags:    []string{"hipaa", "sox"},
+	}
+
+	ctx := context.Background()
+	result, err := aggregator.AggregatePatterns(ctx, selection, "default")
+	if err != nil {
+		t.Fatalf("AggregatePatterns() error = %v", err)
+	}
+
+	// email is both listed and tagged, and selected once
+	if want := patterns.ListPatternsByTag("hipaa"); !reflect.DeepEqual(sorted(result.BuiltInPatterns), want) {
+		t.Errorf("BuiltInPatterns = %v, want %v", result.BuiltInPatterns, want)
+	}
+	if want := []string{"default/chart-number"}; !reflect.DeepEqual(result.CustomPatterns, want) {
+		t.Errorf("CustomPatterns = %v, want %v", result.CustomPatterns, want)
+	}
+	if want := []string{"patient-id"}; !reflect.DeepEqual(result.CommunityPatterns, want) {
+		t.Errorf("CommunityPatterns = %v, want %v", result.CommunityPatterns, want)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0], "sox") {
+		t.Errorf("Errors = %v, want one for the unknown tag", result.Errors)
+	}
+
+	// ip-address is disabled by default but selected through its hipaa tag
+	scoped := aggregator.ScopedEngine(result)
+	if !scoped.IsPatternEnabled("ip-address") {
+		t.Error("tagged ip-address is not enabled in the scoped engine")
+	}
+	if scoped.IsPatternEnabled("credit-card") != engine.IsPatternEnabled("credit-card") {
+		t.Error("untagged credit-card enablement changed in the scoped engine")
+	}
+}
+
+// sorted returns a sorted copy of names
+func sorted(names []string) []string {
+	names = slices.Clone(names)
+	slices.Sort(names)
+	return names
+}
+
 func TestAggregationResult_AllPatterns(t *testing.T) {
 	result := &AggregationResult{
 		BuiltInPatterns:   []string{"email", "credit-card"},
@@ -182,3 +247,116 @@ func TestAggregationResult_HasErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestAggregator_ScopedEngineOverrides(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = piiv1alpha1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	engine := detector.NewEngine()
+	aggregator := NewAggregator(fakeClient, engine)
+
+	disabled := false
+	selection := piiv1alpha1.PatternSelection{
+		BuiltIn: []string{"email", "phone-kr"},
+		Overrides: []piiv1alpha1.PatternOverride{
+			{
+				Pattern:         "email",
+				Severity:        "critical",
+				MaskingStrategy: &piiv1alpha1.MaskingStrategy{Type: "full", Replacement: "[EMAIL]"},
+			},
+			{
+				Pattern: "phone-kr",
+				Enabled: &disabled,
+			},
+		},
+	}
+
+	ctx := context.Background()
+	result, err := aggregator.AggregatePatterns(ctx, selection, "default")
+	if err != nil {
+		t.Fatalf("AggregatePatterns() error = %v", err)
+	}
+	if result.HasErrors() {
+		t.Fatalf("unexpected aggregation errors: %v", result.Errors)
+	}
+
+	scoped := aggregator.ScopedEngine(result)
+	input := "Contact test@example.com or 010-1234-5678"
+
+	redacted, err := redactor.NewRedactor(scoped).Redact(ctx, input)
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	if redacted.RedactedText != "Contact [EMAIL] or 010-1234-5678" {
+		t.Errorf("scoped RedactedText = %q", redacted.RedactedText)
+	}
+	if severity := scoped.GetPatternSpec("email").Severity; severity != "critical" {
+		t.Errorf("scoped email severity = %s, want critical", severity)
+	}
+
+	// The shared engine must be unaffected by the policy's overrides
+	shared, err := redactor.NewRedactor(engine).Redact(ctx, "Contact test@example.com")
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	if shared.RedactedText == "Contact [EMAIL]" {
+		t.Error("override leaked into the shared engine")
+	}
+	if severity := engine.GetPatternSpec("email").Severity; severity == "critical" {
+		t.Error("severity override leaked into the shared engine")
+	}
+	if !engine.IsPatternEnabled("phone-kr") {
+		t.Error("enabled override leaked into the shared engine")
+	}
+}
+
+func TestAggregator_OverrideInvalidTarget(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = piiv1alpha1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	engine := detector.NewEngine()
+	aggregator := NewAggregator(fakeClient, engine)
+
+	selection := piiv1alpha1.PatternSelection{
+		BuiltIn: []string{"email"},
+		Overrides: []piiv1alpha1.PatternOverride{
+			{Pattern: "nonexistent-pattern", Severity: "low"},
+		},
+	}
+
+	result, err := aggregator.AggregatePatterns(context.Background(), selection, "default")
+	if err != nil {
+		t.Fatalf("AggregatePatterns() error = %v", err)
+	}
+	if !result.HasErrors() {
+		t.Error("expected an error for override of unknown pattern")
+	}
+	if len(result.Overrides) != 0 {
+		t.Errorf("expected no valid overrides, got %d", len(result.Overrides))
+	}
+}
+
+func TestNewInputLimiter(t *testing.T) {
+	tests := []struct {
+		name        string
+		perf        *piiv1alpha1.PerformanceConfig
+		size        int
+		wantScanned bool
+	}{
+		{name: "default limit below", perf: nil, size: 1000 * 1024, wantScanned: true},
+		{name: "custom limit skip", perf: &piiv1alpha1.PerformanceConfig{MaxLogSizeKB: 1, OversizeAction: "skip"}, size: 2048, wantScanned: false},
+		{name: "custom limit truncate", perf: &piiv1alpha1.PerformanceConfig{MaxLogSizeKB: 1, OversizeAction: "truncate"}, size: 2048, wantScanned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewInputLimiter(tt.perf)
+			_, scanned := limiter.Limit(strings.Repeat("a", tt.size))
+			if scanned != tt.wantScanned {
+				t.Errorf("scanned = %v, want %v", scanned, tt.wantScanned)
+			}
+		})
+	}
+}
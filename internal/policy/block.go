@@ -0,0 +1,90 @@
+package policy
+
+import (
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/notifier"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// DefaultBlockNotice replaces the content of entries blocked by a policy
+const DefaultBlockNotice = "[BLOCKED: entry contained sensitive data]"
+
+// defaultBlockSeverity is the minimum severity that blocks an entry when a
+// block action does not set one
+const defaultBlockSeverity = "critical"
+
+// Blocker enforces a policy's block action on redaction results
+type Blocker struct {
+	minSeverity string
+	notice      string
+}
+
+// NewBlocker creates the blocker for a policy's block action.
+// It returns nil, which never blocks, when the action is nil or disabled.
+func NewBlocker(action *piiv1alpha1.BlockAction) *Blocker {
+	if action == nil || !action.Enabled {
+		return nil
+	}
+
+	b := &Blocker{
+		minSeverity: action.MinSeverity,
+		notice:      action.Notice,
+	}
+	if b.minSeverity == "" {
+		b.minSeverity = defaultBlockSeverity
+	}
+	if b.notice == "" {
+		b.notice = DefaultBlockNotice
+	}
+	return b
+}
+
+// Apply blocks the result if any detection meets the minimum severity,
+// replacing the redacted text with the block notice. It returns the most
+// severe detection, which triggered the block.
+func (b *Blocker) Apply(result *redactor.RedactResult) (detector.DetectionResult, bool) {
+	if result == nil {
+		return detector.DetectionResult{}, false
+	}
+	trigger, blocked := b.Trigger(result.Detections)
+	if !blocked {
+		return detector.DetectionResult{}, false
+	}
+
+	result.RedactedText = b.notice
+	result.Blocked = true
+	return trigger, true
+}
+
+// Trigger returns the most severe of the detections that meet the minimum
+// severity, and whether there is one
+func (b *Blocker) Trigger(detections []detector.DetectionResult) (detector.DetectionResult, bool) {
+	if b == nil {
+		return detector.DetectionResult{}, false
+	}
+
+	var trigger detector.DetectionResult
+	blocked := false
+	for _, d := range detections {
+		if !notifier.ShouldAlert(d.Severity, b.minSeverity) {
+			continue
+		}
+		if !blocked || notifier.SeverityLevel(d.Severity) > notifier.SeverityLevel(trigger.Severity) {
+			trigger = d
+			blocked = true
+		}
+	}
+	return trigger, blocked
+}
+
+// BlockAuditEntry builds the audit entry recording that a policy blocked a log entry
+func BlockAuditEntry(policyName string, entry detector.LogEntry, trigger detector.DetectionResult, result *redactor.RedactResult) *audit.AuditEntry {
+	return audit.NewAuditEntry(audit.EventTypePIIBlocked, entry.Namespace, policyName, trigger.PatternName).
+		WithPod(entry.Pod, entry.Container).
+		WithSeverity(trigger.Severity).
+		WithAction(audit.ActionBlock).
+		WithMatchCount(len(result.Detections)).
+		WithRedactedText(result.RedactedText)
+}
@@ -0,0 +1,170 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+func TestBlocker_Apply(t *testing.T) {
+	r := redactor.NewRedactor(detector.NewEngine())
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		action      *piiv1alpha1.BlockAction
+		input       string
+		wantBlocked bool
+		wantText    string
+	}{
+		{
+			name:        "critical detection blocks",
+			action:      &piiv1alpha1.BlockAction{Enabled: true},
+			input:       "card 4111-1111-1111-1111 for test@example.com",
+			wantBlocked: true,
+			wantText:    DefaultBlockNotice,
+		},
+		{
+			name:        "custom notice",
+			action:      &piiv1alpha1.BlockAction{Enabled: true, Notice: "[DROPPED]"},
+			input:       "card 4111-1111-1111-1111",
+			wantBlocked: true,
+			wantText:    "[DROPPED]",
+		},
+		{
+			name:        "below minimum severity is redacted",
+			action:      &piiv1alpha1.BlockAction{Enabled: true},
+			input:       "mail test@example.com",
+			wantBlocked: false,
+		},
+		{
+			name:        "lower minimum severity blocks",
+			action:      &piiv1alpha1.BlockAction{Enabled: true, MinSeverity: "medium"},
+			input:       "mail test@example.com",
+			wantBlocked: true,
+			wantText:    DefaultBlockNotice,
+		},
+		{
+			name:        "disabled action",
+			action:      &piiv1alpha1.BlockAction{Enabled: false},
+			input:       "card 4111-1111-1111-1111",
+			wantBlocked: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Redact(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			redacted := result.RedactedText
+
+			_, blocked := NewBlocker(tt.action).Apply(result)
+			if blocked != tt.wantBlocked || result.Blocked != tt.wantBlocked {
+				t.Fatalf("blocked = %v (result.Blocked = %v), want %v", blocked, result.Blocked, tt.wantBlocked)
+			}
+			if tt.wantBlocked && result.RedactedText != tt.wantText {
+				t.Errorf("RedactedText = %q, want %q", result.RedactedText, tt.wantText)
+			}
+			if !tt.wantBlocked && result.RedactedText != redacted {
+				t.Errorf("RedactedText changed to %q for an unblocked entry", result.RedactedText)
+			}
+		})
+	}
+}
+
+func TestBlockAuditEntry(t *testing.T) {
+	r := redactor.NewRedactor(detector.NewEngine())
+	entry := detector.LogEntry{
+		Namespace: "payments",
+		Pod:       "api-0",
+		Container: "app",
+		Message:   "card 4111-1111-1111-1111 for test@example.com",
+	}
+
+	result, err := r.Redact(context.Background(), entry.Message)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	trigger, blocked := NewBlocker(&piiv1alpha1.BlockAction{Enabled: true}).Apply(result)
+	if !blocked {
+		t.Fatal("expected critical detection to block the entry")
+	}
+
+	var buf bytes.Buffer
+	logger := audit.NewJSONLogger(&buf)
+	if err := logger.Log(context.Background(), BlockAuditEntry("fail-closed", entry, trigger, result)); err != nil {
+		t.Fatalf("Log() error = %v", err)
+	}
+
+	var logged audit.AuditEntry
+	if err := json.Unmarshal(buf.Bytes(), &logged); err != nil {
+		t.Fatalf("failed to decode audit entry: %v", err)
+	}
+	if logged.Action != audit.ActionBlock {
+		t.Errorf("Action = %q, want %q", logged.Action, audit.ActionBlock)
+	}
+	if logged.EventType != audit.EventTypePIIBlocked {
+		t.Errorf("EventType = %q, want %q", logged.EventType, audit.EventTypePIIBlocked)
+	}
+	if logged.PatternName != "credit-card" || logged.Severity != "critical" {
+		t.Errorf("trigger = %s/%s, want credit-card/critical", logged.PatternName, logged.Severity)
+	}
+	if logged.RedactedText != DefaultBlockNotice {
+		t.Errorf("RedactedText = %q, want block notice", logged.RedactedText)
+	}
+}
+
+func TestForwarder_ForwardBlocked(t *testing.T) {
+	r := redactor.NewRedactor(detector.NewEngine())
+	ctx := context.Background()
+	entry := detector.LogEntry{Namespace: "default", Pod: "api-0", Container: "app"}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantBlock bool
+	}{
+		{name: "critical detection is blocked", input: "card 4111-1111-1111-1111 charged", wantBlock: true},
+		{name: "lower severity is redacted", input: "user test@example.com logged in", wantBlock: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.Redact(ctx, tt.input)
+			if err != nil {
+				t.Fatalf("Redact() error = %v", err)
+			}
+
+			destination := &fakeDestination{}
+			auditLogger := &fakeAuditLogger{}
+			forwarder := NewForwarder("default-policy", destination, auditLogger, nil, nil)
+			forwarder.SetBlocker(NewBlocker(&piiv1alpha1.BlockAction{Enabled: true}))
+			if err := forwarder.Forward(ctx, entry, result); err != nil {
+				t.Fatalf("Forward() error = %v", err)
+			}
+
+			if len(destination.entries) != 1 || len(auditLogger.entries) != 1 {
+				t.Fatalf("forwarded %d entries and audited %d, want 1 each", len(destination.entries), len(auditLogger.entries))
+			}
+			sent, audited := destination.entries[0], auditLogger.entries[0]
+			if sent.Blocked != tt.wantBlock || (sent.Message == DefaultBlockNotice) != tt.wantBlock {
+				t.Errorf("forwarded %+v, want blocked %v", sent, tt.wantBlock)
+			}
+			wantEvent := audit.EventTypePIIRedacted
+			if tt.wantBlock {
+				wantEvent = audit.EventTypePIIBlocked
+			}
+			if audited.EventType != wantEvent {
+				t.Errorf("audit event = %s, want %s", audited.EventType, wantEvent)
+			}
+		})
+	}
+}
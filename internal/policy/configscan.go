@@ -0,0 +1,236 @@
+package policy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/notifier"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// Sources reported for findings in configuration objects
+const (
+	SourceConfigMap = "configmap"
+	SourceSecret    = "secret"
+)
+
+// ConfigFinding is PII found in a single key of a ConfigMap or Secret
+type ConfigFinding struct {
+	// Source is SourceConfigMap or SourceSecret
+	Source string
+
+	// Namespace and Name identify the object
+	Namespace string
+	Name      string
+
+	// Key is the data key holding the value
+	Key string
+
+	// ResourceVersion is the version of the object that was scanned
+	ResourceVersion string
+
+	// Detections are the matches in the value. MatchedText holds the masked
+	// value so findings never carry the stored data.
+	Detections []detector.DetectionResult
+}
+
+// ConfigScanner scans ConfigMap and Secret data for PII
+type ConfigScanner struct {
+	client client.Client
+
+	mu sync.Mutex
+	// reported holds the fingerprints of the findings of each policy's last
+	// scan, keyed by policy
+	reported map[string]map[string]bool
+}
+
+// NewConfigScanner creates a new ConfigScanner
+func NewConfigScanner(c client.Client) *ConfigScanner {
+	return &ConfigScanner{
+		client:   c,
+		reported: make(map[string]map[string]bool),
+	}
+}
+
+// Unreported returns the findings of a policy's scan that its previous scan
+// did not find, and remembers the findings for its next scan. A finding is
+// reported again once its object changes, as the object's resource version
+// is part of its fingerprint.
+func (s *ConfigScanner) Unreported(policyKey string, findings []ConfigFinding) []ConfigFinding {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous := s.reported[policyKey]
+	current := make(map[string]bool, len(findings))
+	var unreported []ConfigFinding
+	for _, finding := range findings {
+		fingerprint := finding.Fingerprint()
+		if !previous[fingerprint] && !current[fingerprint] {
+			unreported = append(unreported, finding)
+		}
+		current[fingerprint] = true
+	}
+	s.reported[policyKey] = current
+	return unreported
+}
+
+// Forget drops the findings remembered for a policy, so that its next scan
+// reports everything it finds
+func (s *ConfigScanner) Forget(policyKey string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.reported, policyKey)
+}
+
+// Scan scans ConfigMaps, and Secrets when the action includes them, in the
+// given namespaces with the engine's named patterns. Values larger than the
+// policy's MaxLogSizeKB are skipped or truncated like log entries. A nil or
+// disabled action scans nothing.
+func (s *ConfigScanner) Scan(ctx context.Context, engine *detector.Engine, action *piiv1alpha1.ConfigScanAction, perf *piiv1alpha1.PerformanceConfig, namespaces, patternNames []string) ([]ConfigFinding, error) {
+	if action == nil || !action.Enabled || len(patternNames) == 0 {
+		return nil, nil
+	}
+
+	r := redactor.NewRedactor(engine)
+	r.SetInputLimiter(NewInputLimiter(perf))
+
+	var findings []ConfigFinding
+	for _, ns := range namespaces {
+		var configMaps corev1.ConfigMapList
+		if err := s.client.List(ctx, &configMaps, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("list configmaps in %s: %w", ns, err)
+		}
+		for _, cm := range configMaps.Items {
+			for _, key := range sortedKeys(cm.Data) {
+				finding, err := scanValue(ctx, r, SourceConfigMap, &cm.ObjectMeta, key, cm.Data[key], patternNames)
+				if err != nil {
+					return nil, err
+				}
+				if finding != nil {
+					findings = append(findings, *finding)
+				}
+			}
+		}
+
+		if !action.IncludeSecrets {
+			continue
+		}
+
+		var secrets corev1.SecretList
+		if err := s.client.List(ctx, &secrets, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("list secrets in %s: %w", ns, err)
+		}
+		for _, secret := range secrets.Items {
+			for _, key := range sortedKeys(secret.Data) {
+				value := secret.Data[key]
+				if !utf8.Valid(value) {
+					continue
+				}
+				finding, err := scanValue(ctx, r, SourceSecret, &secret.ObjectMeta, key, string(value), patternNames)
+				if err != nil {
+					return nil, err
+				}
+				if finding != nil {
+					findings = append(findings, *finding)
+				}
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// scanValue scans a single data value. It returns nil if the value holds no PII.
+func scanValue(ctx context.Context, r *redactor.Redactor, source string, object *metav1.ObjectMeta, key, value string, patternNames []string) (*ConfigFinding, error) {
+	result, err := r.RedactWithPatterns(ctx, value, patternNames)
+	if err != nil {
+		return nil, fmt.Errorf("scan %s %s/%s key %s: %w", source, object.Namespace, object.Name, key, err)
+	}
+	if len(result.Detections) == 0 {
+		return nil, nil
+	}
+
+	detections := make([]detector.DetectionResult, len(result.Detections))
+	for i, d := range result.Detections {
+		d.MatchedText = d.RedactedText
+		detections[i] = d
+	}
+
+	return &ConfigFinding{
+		Source:          source,
+		Namespace:       object.Namespace,
+		Name:            object.Name,
+		Key:             key,
+		ResourceVersion: object.ResourceVersion,
+		Detections:      detections,
+	}, nil
+}
+
+// Fingerprint identifies the finding: the same detections in the same key of
+// the same version of an object always have the same fingerprint
+func (f ConfigFinding) Fingerprint() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s", f.Source, f.Namespace, f.Name, f.Key, f.ResourceVersion)
+	for _, d := range f.Detections {
+		fmt.Fprintf(h, "\x00%s:%d:%d", d.PatternName, d.Position.Start, d.Position.End)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Alert builds the alert reporting the finding
+func (f ConfigFinding) Alert(policyName string) *notifier.Alert {
+	return notifier.NewAggregatedAlert(detector.LogEntry{Namespace: f.Namespace}, f.Detections).
+		WithPolicy(policyName).
+		WithSource(f.Source).
+		AddLabel("object", f.Name).
+		AddLabel("key", f.Key)
+}
+
+// AuditEntry builds the audit entry recording the finding. Findings that the
+// policy's blocker would block are recorded with the block action: stored
+// data cannot be withheld like a log entry, so they call for the value to be
+// removed from the object.
+func (f ConfigFinding) AuditEntry(policyName string, blocker *Blocker) *audit.AuditEntry {
+	action := audit.ActionLog
+	trigger, blocked := blocker.Trigger(f.Detections)
+	if blocked {
+		action = audit.ActionBlock
+	} else {
+		trigger = f.Detections[0]
+		for _, d := range f.Detections[1:] {
+			if notifier.SeverityLevel(d.Severity) > notifier.SeverityLevel(trigger.Severity) {
+				trigger = d
+			}
+		}
+	}
+
+	return audit.NewAuditEntry(audit.EventTypePIIDetected, f.Namespace, policyName, trigger.PatternName).
+		WithSeverity(trigger.Severity).
+		WithAction(action).
+		WithMatchCount(len(f.Detections)).
+		WithSource(f.Source).
+		AddLabel("object", f.Name).
+		AddLabel("key", f.Key)
+}
+
+// sortedKeys returns the keys of a data map in order
+func sortedKeys[V any](data map[string]V) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,210 @@
+package policy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+func TestConfigScanner_Scan(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data: map[string]string{
+			"contact":  "owner: john.doe@example.com",
+			"loglevel": "info",
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-secret", Namespace: "default"},
+		Data: map[string][]byte{
+			"admin": []byte("admin@example.com"),
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, secret).Build()
+	scanner := NewConfigScanner(fakeClient)
+	engine := detector.NewEngine()
+
+	tests := []struct {
+		name        string
+		action      *piiv1alpha1.ConfigScanAction
+		wantSources []string
+	}{
+		{name: "nil action", action: nil},
+		{name: "disabled", action: &piiv1alpha1.ConfigScanAction{Enabled: false, IncludeSecrets: true}},
+		{name: "configmaps only", action: &piiv1alpha1.ConfigScanAction{Enabled: true}, wantSources: []string{SourceConfigMap}},
+		{name: "with secrets", action: &piiv1alpha1.ConfigScanAction{Enabled: true, IncludeSecrets: true}, wantSources: []string{SourceConfigMap, SourceSecret}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := scanner.Scan(context.Background(), engine, tt.action, nil, []string{"default"}, []string{"email"})
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if len(findings) != len(tt.wantSources) {
+				t.Fatalf("Scan() = %d findings, want %d", len(findings), len(tt.wantSources))
+			}
+			for i, f := range findings {
+				if f.Source != tt.wantSources[i] {
+					t.Errorf("findings[%d].Source = %s, want %s", i, f.Source, tt.wantSources[i])
+				}
+				if len(f.Detections) != 1 || f.Detections[0].PatternName != "email" {
+					t.Errorf("findings[%d].Detections = %v, want one email detection", i, f.Detections)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigScanner_ScanSizeLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	// The email lies beyond the first KB of the value
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data: map[string]string{
+			"head": "owner: john.doe@example.com " + strings.Repeat("x", 2048),
+			"tail": strings.Repeat("x", 2048) + " owner: john.doe@example.com",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+	scanner := NewConfigScanner(fakeClient)
+	action := &piiv1alpha1.ConfigScanAction{Enabled: true}
+
+	tests := []struct {
+		name     string
+		perf     *piiv1alpha1.PerformanceConfig
+		wantKeys []string
+	}{
+		{name: "default limit", perf: nil, wantKeys: []string{"head", "tail"}},
+		{name: "truncate", perf: &piiv1alpha1.PerformanceConfig{MaxLogSizeKB: 1, OversizeAction: "truncate"}, wantKeys: []string{"head"}},
+		{name: "skip", perf: &piiv1alpha1.PerformanceConfig{MaxLogSizeKB: 1, OversizeAction: "skip"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := scanner.Scan(context.Background(), detector.NewEngine(), action, tt.perf, []string{"default"}, []string{"email"})
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			var keys []string
+			for _, f := range findings {
+				keys = append(keys, f.Key)
+			}
+			if strings.Join(keys, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("Scan() found PII in keys %v, want %v", keys, tt.wantKeys)
+			}
+		})
+	}
+}
+
+func TestConfigFinding_DoesNotExposeValue(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"contact": "john.doe@example.com"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+	scanner := NewConfigScanner(fakeClient)
+
+	action := &piiv1alpha1.ConfigScanAction{Enabled: true}
+	findings, err := scanner.Scan(context.Background(), detector.NewEngine(), action, nil, []string{"default"}, []string{"email"})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("Scan() = %d findings, want 1", len(findings))
+	}
+
+	finding := findings[0]
+	if finding.Name != "app-config" || finding.Key != "contact" {
+		t.Errorf("finding = %s/%s, want app-config/contact", finding.Name, finding.Key)
+	}
+
+	alert := finding.Alert("config-policy")
+	if alert.Source != SourceConfigMap {
+		t.Errorf("alert.Source = %s, want %s", alert.Source, SourceConfigMap)
+	}
+	for _, d := range alert.Detections {
+		if strings.Contains(d.MatchedText, "john.doe@example.com") {
+			t.Errorf("alert exposes the stored value: %q", d.MatchedText)
+		}
+	}
+
+	entry := finding.AuditEntry("config-policy", nil)
+	if entry.Source != SourceConfigMap || entry.EventType != audit.EventTypePIIDetected {
+		t.Errorf("audit entry = %s/%s, want %s/%s", entry.EventType, entry.Source, audit.EventTypePIIDetected, SourceConfigMap)
+	}
+	if entry.Labels["object"] != "app-config" {
+		t.Errorf("audit entry object label = %q, want app-config", entry.Labels["object"])
+	}
+	if entry.Action != audit.ActionLog {
+		t.Errorf("audit entry action = %s, want %s", entry.Action, audit.ActionLog)
+	}
+
+	blocker := NewBlocker(&piiv1alpha1.BlockAction{Enabled: true, MinSeverity: "medium"})
+	if entry := finding.AuditEntry("config-policy", blocker); entry.Action != audit.ActionBlock {
+		t.Errorf("blocked audit entry action = %s, want %s", entry.Action, audit.ActionBlock)
+	}
+}
+
+func TestConfigScanner_Unreported(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-config", Namespace: "default"},
+		Data:       map[string]string{"contact": "john.doe@example.com"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+	scanner := NewConfigScanner(fakeClient)
+	action := &piiv1alpha1.ConfigScanAction{Enabled: true}
+
+	ctx := context.Background()
+	scan := func(policyKey string) []ConfigFinding {
+		t.Helper()
+		findings, err := scanner.Scan(ctx, detector.NewEngine(), action, nil, []string{"default"}, []string{"email"})
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		return scanner.Unreported(policyKey, findings)
+	}
+
+	if got := scan("default/policy"); len(got) != 1 {
+		t.Fatalf("first scan reported %d findings, want 1", len(got))
+	}
+	if got := scan("default/policy"); len(got) != 0 {
+		t.Errorf("rescan of an unchanged object reported %d findings, want 0", len(got))
+	}
+	if got := scan("default/other"); len(got) != 1 {
+		t.Errorf("another policy's first scan reported %d findings, want 1", len(got))
+	}
+
+	configMap.Data["contact"] = "owner: john.doe@example.com"
+	if err := fakeClient.Update(ctx, configMap); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := scan("default/policy"); len(got) != 1 {
+		t.Errorf("scan of a changed object reported %d findings, want 1", len(got))
+	}
+
+	scanner.Forget("default/policy")
+	if got := scan("default/policy"); len(got) != 1 {
+		t.Errorf("scan after Forget reported %d findings, want 1", len(got))
+	}
+}
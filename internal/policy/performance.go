@@ -0,0 +1,31 @@
+package policy
+
+import (
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// defaultMaxLogSizeKB is the log size limit applied when a policy does not set one
+const defaultMaxLogSizeKB = 1024
+
+// NewInputLimiter creates the input size limiter for a policy's performance settings
+func NewInputLimiter(perf *piiv1alpha1.PerformanceConfig) *redactor.InputLimiter {
+	if perf == nil {
+		return redactor.NewInputLimiter(defaultMaxLogSizeKB, redactor.OversizeTruncate)
+	}
+
+	maxSizeKB := perf.MaxLogSizeKB
+	if maxSizeKB <= 0 {
+		maxSizeKB = defaultMaxLogSizeKB
+	}
+
+	return redactor.NewInputLimiter(maxSizeKB, redactor.OversizeAction(perf.OversizeAction))
+}
+
+// NewPolicySampler creates the log sampler for a policy's performance settings
+func NewPolicySampler(perf *piiv1alpha1.PerformanceConfig) (*Sampler, error) {
+	if perf == nil {
+		return NewSampler(0, SamplingHash)
+	}
+	return NewSampler(perf.SamplingRate, perf.SamplingMode)
+}
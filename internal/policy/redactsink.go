@@ -0,0 +1,381 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bunseokbot/pii-redactor/internal/audit"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/notifier"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// redactHTTPTimeout bounds a single delivery to an HTTP redact destination
+const redactHTTPTimeout = 10 * time.Second
+
+// RedactedEntry is a redacted log entry forwarded to a redact destination.
+// It never carries the original text.
+type RedactedEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Policy     string    `json:"policy"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Pod        string    `json:"pod,omitempty"`
+	Container  string    `json:"container,omitempty"`
+	Message    string    `json:"message"`
+	Detections int       `json:"detections"`
+	Blocked    bool      `json:"blocked,omitempty"`
+
+	// Skipped is true when the log exceeded the size limit and was not
+	// scanned; its message is withheld
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Truncated is true when only a prefix of the log was scanned; the rest
+	// of its message is withheld
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RedactDestination receives the redacted output of a policy
+type RedactDestination interface {
+	// Send delivers a redacted entry
+	Send(ctx context.Context, entry *RedactedEntry) error
+
+	// Close closes the destination
+	Close() error
+}
+
+// NewRedactDestination creates the destination for a target: "stdout",
+// "stderr", a file given as an absolute path or file:// URL that entries are
+// appended to as JSON lines, or an http(s):// URL entries are POSTed to as JSON
+func NewRedactDestination(target string) (RedactDestination, error) {
+	switch {
+	case target == "stdout":
+		return &writerDestination{writer: os.Stdout}, nil
+	case target == "stderr":
+		return &writerDestination{writer: os.Stderr}, nil
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		return &httpDestination{url: target, client: &http.Client{Timeout: redactHTTPTimeout}}, nil
+	}
+
+	path := strings.TrimPrefix(target, "file://")
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("unsupported target %q: use stdout, stderr, an absolute file path or an http(s) URL", target)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open redact destination file: %w", err)
+	}
+	return &writerDestination{writer: file, closer: file}, nil
+}
+
+// writerDestination writes entries as JSON lines
+type writerDestination struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+// Send writes the entry as a JSON line
+func (d *writerDestination) Send(_ context.Context, entry *RedactedEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal redacted entry: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, err = d.writer.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the underlying file, if any
+func (d *writerDestination) Close() error {
+	if d.closer != nil {
+		return d.closer.Close()
+	}
+	return nil
+}
+
+// httpDestination POSTs entries as JSON
+type httpDestination struct {
+	url    string
+	client *http.Client
+}
+
+// Send POSTs the entry and fails on a non-2xx response
+func (d *httpDestination) Send(ctx context.Context, entry *RedactedEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal redacted entry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send redacted entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("redact destination returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close is a no-op for HTTP destinations
+func (d *httpDestination) Close() error {
+	return nil
+}
+
+// RedactDestinations resolves the destination named by a policy's redact
+// action to a sink configured by the operator. Policies can only select
+// configured sinks, never arbitrary files or URLs.
+type RedactDestinations struct {
+	mu       sync.RWMutex
+	fallback RedactDestination
+	sinks    map[string]RedactDestination
+}
+
+// NewRedactDestinations creates a new RedactDestinations that resolves an
+// empty destination to stdout
+func NewRedactDestinations() *RedactDestinations {
+	return &RedactDestinations{
+		fallback: &writerDestination{writer: os.Stdout},
+		sinks:    make(map[string]RedactDestination),
+	}
+}
+
+// Register configures a named sink for a target accepted by
+// NewRedactDestination. Registering a name again replaces and closes its sink.
+func (d *RedactDestinations) Register(name, target string) error {
+	if name == "" {
+		return fmt.Errorf("redact destination name is empty")
+	}
+
+	destination, err := NewRedactDestination(target)
+	if err != nil {
+		return fmt.Errorf("redact destination %s: %w", name, err)
+	}
+
+	d.mu.Lock()
+	previous := d.sinks[name]
+	d.sinks[name] = destination
+	d.mu.Unlock()
+
+	if previous != nil {
+		return previous.Close()
+	}
+	return nil
+}
+
+// RegisterSpec registers the sinks of a comma-separated list of
+// name=target pairs, e.g. "archive=/var/log/redacted.jsonl,siem=https://siem.example.com/ingest"
+func (d *RedactDestinations) RegisterSpec(spec string) error {
+	for _, pair := range strings.Split(spec, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, target, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid redact destination %q: expected name=target", pair)
+		}
+		if err := d.Register(strings.TrimSpace(name), strings.TrimSpace(target)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Resolve returns the destination for a name. An empty name resolves to stdout.
+func (d *RedactDestinations) Resolve(name string) (RedactDestination, error) {
+	if name == "" {
+		return d.fallback, nil
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	destination, ok := d.sinks[name]
+	if !ok {
+		return nil, fmt.Errorf("redact destination %s is not configured", name)
+	}
+	return destination, nil
+}
+
+// Close closes all sinks
+func (d *RedactDestinations) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var errs []error
+	for name, destination := range d.sinks {
+		if err := destination.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(d.sinks, name)
+	}
+	return errors.Join(errs...)
+}
+
+// Forwarder routes the outcome of redacting a log entry under a policy: the
+// redacted text goes to the redact destination, while detections are recorded
+// in the audit log and alerted to the policy's channels
+type Forwarder struct {
+	policyName  string
+	destination RedactDestination
+	auditLogger audit.AuditLogger
+	notifier    *notifier.Manager
+	channels    []string
+	dedupKey    *notifier.DedupKeyTemplate
+	blocker     *Blocker
+	sampler     *Sampler
+	stats       *detector.Engine
+}
+
+// NewForwarder creates a forwarder for a policy. Any of destination,
+// auditLogger and notifierManager may be nil to skip that route.
+func NewForwarder(policyName string, destination RedactDestination, auditLogger audit.AuditLogger, notifierManager *notifier.Manager, channels []string) *Forwarder {
+	return &Forwarder{
+		policyName:  policyName,
+		destination: destination,
+		auditLogger: auditLogger,
+		notifier:    notifierManager,
+		channels:    channels,
+	}
+}
+
+// SetDedupKeyTemplate sets the template alerts' deduplication keys are
+// rendered from; nil keeps the default key
+func (f *Forwarder) SetDedupKeyTemplate(t *notifier.DedupKeyTemplate) {
+	f.dedupKey = t
+}
+
+// SetBlocker sets the blocker enforcing the policy's block action; nil never
+// blocks
+func (f *Forwarder) SetBlocker(b *Blocker) {
+	f.blocker = b
+}
+
+// SetSampler sets the sampler picking the entries whose detections are
+// audited and alerted; nil processes every entry. Entries left out are
+// counted in the Stats of engine, which may be nil.
+func (f *Forwarder) SetSampler(s *Sampler, engine *detector.Engine) {
+	f.sampler = s
+	f.stats = engine
+}
+
+// Forward sends the redacted text of a result to the destination and, if the
+// result has detections, records and alerts them. The original text is never
+// forwarded, nor is text the input limiter left unscanned: it is replaced
+// with redactor.UnscannedPlaceholder. A result blocked by the policy's block action is forwarded as
+// the block notice and audited as blocked. Entries the sampler leaves out are
+// still forwarded redacted, but their detections are neither audited nor
+// alerted unless they are blocked. All routes are attempted; their errors
+// are joined.
+func (f *Forwarder) Forward(ctx context.Context, entry detector.LogEntry, result *redactor.RedactResult) error {
+	var errs []error
+
+	trigger, blocked := f.blocker.Apply(result)
+
+	if f.destination != nil {
+		err := f.destination.Send(ctx, &RedactedEntry{
+			Timestamp:  time.Now(),
+			Policy:     f.policyName,
+			Namespace:  entry.Namespace,
+			Pod:        entry.Pod,
+			Container:  entry.Container,
+			Message:    forwardedMessage(result),
+			Detections: len(result.Detections),
+			Blocked:    result.Blocked,
+			Skipped:    result.Skipped,
+			Truncated:  result.Truncated,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("forward redacted entry: %w", err))
+		}
+	}
+
+	if len(result.Detections) == 0 {
+		return errors.Join(errs...)
+	}
+	if !blocked && f.sampler != nil && !f.sampler.Sample(entry.Message) {
+		if f.stats != nil {
+			f.stats.RecordSampledOut()
+		}
+		return errors.Join(errs...)
+	}
+
+	detections := make([]detector.DetectionResult, len(result.Detections))
+	for i, d := range result.Detections {
+		d.MatchedText = d.RedactedText
+		detections[i] = d
+	}
+
+	if f.auditLogger != nil {
+		auditEntry := RedactAuditEntry(f.policyName, entry, detections, result)
+		if blocked {
+			auditEntry = BlockAuditEntry(f.policyName, entry, trigger, result)
+		}
+		if err := f.auditLogger.Log(ctx, auditEntry); err != nil {
+			errs = append(errs, fmt.Errorf("audit redacted entry: %w", err))
+		}
+	}
+
+	if f.notifier != nil && len(f.channels) > 0 {
+		alert := notifier.NewAggregatedAlert(entry, detections).WithPolicy(f.policyName).WithDedupKey(f.dedupKey)
+		results, _ := f.notifier.SendAlertToChannels(ctx, f.channels, alert)
+		for _, result := range results {
+			if result.Err != nil {
+				errs = append(errs, fmt.Errorf("alert channel %s: %w", result.Channel, result.Err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// forwardedMessage returns the text of a result that may be forwarded: its
+// redacted text, withheld entirely when the input was skipped, or truncated
+// without its unscanned tail already replaced
+func forwardedMessage(result *redactor.RedactResult) string {
+	switch {
+	case result.Blocked:
+		return result.RedactedText
+	case result.Skipped:
+		return redactor.UnscannedPlaceholder
+	case result.Truncated && !strings.HasSuffix(result.RedactedText, redactor.UnscannedPlaceholder):
+		return redactor.UnscannedPlaceholder
+	}
+	return result.RedactedText
+}
+
+// RedactAuditEntry builds the audit entry recording that a policy redacted a
+// log entry, attributed to its most severe detection
+func RedactAuditEntry(policyName string, entry detector.LogEntry, detections []detector.DetectionResult, result *redactor.RedactResult) *audit.AuditEntry {
+	trigger := detections[0]
+	for _, d := range detections[1:] {
+		if notifier.SeverityLevel(d.Severity) > notifier.SeverityLevel(trigger.Severity) {
+			trigger = d
+		}
+	}
+
+	return audit.NewAuditEntry(audit.EventTypePIIRedacted, entry.Namespace, policyName, trigger.PatternName).
+		WithPod(entry.Pod, entry.Container).
+		WithSeverity(trigger.Severity).
+		WithAction(audit.ActionRedact).
+		WithMatchCount(len(detections)).
+		WithRedactedText(forwardedMessage(result))
+}
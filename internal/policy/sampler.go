@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// Sampling modes
+const (
+	// SamplingHash decides by a hash of the content, identically in every process
+	SamplingHash = "hash"
+
+	// SamplingRandom decides independently for every log, even identical ones
+	SamplingRandom = "random"
+
+	// SamplingSeeded decides by a hash of the content keyed by a seed chosen
+	// per process: identical logs get identical decisions within a process,
+	// but a restarted process samples a different subset
+	SamplingSeeded = "seeded"
+)
+
+// Sampler decides which logs are processed when only a percentage of them is
+type Sampler struct {
+	rate int
+	mode string
+	seed uint64
+}
+
+// NewSampler creates a sampler keeping rate percent of logs. A rate of zero
+// or above 100 keeps every log. Seeded samplers get a random seed.
+func NewSampler(rate int, mode string) (*Sampler, error) {
+	switch mode {
+	case "":
+		mode = SamplingHash
+	case SamplingHash, SamplingRandom:
+	case SamplingSeeded:
+		return NewSeededSampler(rate, rand.Uint64()), nil
+	default:
+		return nil, fmt.Errorf("unknown sampling mode %q", mode)
+	}
+	return &Sampler{rate: rate, mode: mode}, nil
+}
+
+// NewSeededSampler creates a seeded sampler with the given seed, so that a
+// process's decisions can be reproduced
+func NewSeededSampler(rate int, seed uint64) *Sampler {
+	return &Sampler{rate: rate, mode: SamplingSeeded, seed: seed}
+}
+
+// Mode returns the sampling mode
+func (s *Sampler) Mode() string {
+	return s.mode
+}
+
+// Seed returns the seed of a seeded sampler, and zero for other modes
+func (s *Sampler) Seed() uint64 {
+	return s.seed
+}
+
+// Sample reports whether the log with the given content is kept
+func (s *Sampler) Sample(content string) bool {
+	if s.rate <= 0 || s.rate >= 100 {
+		return true
+	}
+
+	var bucket uint64
+	switch s.mode {
+	case SamplingRandom:
+		bucket = rand.Uint64N(100)
+	case SamplingSeeded:
+		bucket = s.hash(content) % 100
+	default:
+		bucket = fnvHash(nil, content) % 100
+	}
+	return bucket < uint64(s.rate)
+}
+
+// hash hashes content keyed by the sampler's seed
+func (s *Sampler) hash(content string) uint64 {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], s.seed)
+	return mix(fnvHash(seed[:], content))
+}
+
+// fnvHash returns the FNV-1a hash of prefix followed by content
+func fnvHash(prefix []byte, content string) uint64 {
+	h := fnv.New64a()
+	h.Write(prefix)
+	h.Write([]byte(content))
+	return h.Sum64()
+}
+
+// mix spreads the bits of a hash (the splitmix64 finalizer), so that
+// seeds differing in a few bits still produce unrelated decisions
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,113 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+)
+
+// sampleKeys returns the sampler's decisions for n distinct logs
+func sampleKeys(s *Sampler, n int) []bool {
+	decisions := make([]bool, n)
+	for i := range decisions {
+		decisions[i] = s.Sample(fmt.Sprintf("user %d logged in from 10.0.0.%d", i, i%256))
+	}
+	return decisions
+}
+
+func TestSampler_Seeded(t *testing.T) {
+	const n = 2000
+
+	first := NewSeededSampler(30, 42)
+	if first.Seed() != 42 {
+		t.Fatalf("Seed() = %d, want 42", first.Seed())
+	}
+
+	t.Run("identical decisions within a session", func(t *testing.T) {
+		want := sampleKeys(first, n)
+		if got := sampleKeys(first, n); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Error("repeated decisions differ for the same sampler")
+		}
+		if got := sampleKeys(NewSeededSampler(30, first.Seed()), n); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Error("decisions differ for a sampler with the same seed")
+		}
+	})
+
+	t.Run("different decisions across seeds", func(t *testing.T) {
+		a := sampleKeys(first, n)
+		b := sampleKeys(NewSeededSampler(30, 43), n)
+		differ := 0
+		for i := range a {
+			if a[i] != b[i] {
+				differ++
+			}
+		}
+		// Independent 30% samples disagree on about 42% of logs
+		if differ < n/4 {
+			t.Errorf("seeds 42 and 43 disagree on %d of %d logs, want at least %d", differ, n, n/4)
+		}
+	})
+
+	t.Run("rate is respected", func(t *testing.T) {
+		kept := 0
+		for _, keep := range sampleKeys(first, n) {
+			if keep {
+				kept++
+			}
+		}
+		if kept < n*25/100 || kept > n*35/100 {
+			t.Errorf("kept %d of %d logs, want about 30%%", kept, n)
+		}
+	})
+}
+
+func TestSampler_Modes(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    int
+		mode    string
+		wantErr bool
+	}{
+		{name: "default mode", rate: 50},
+		{name: "hash", rate: 50, mode: SamplingHash},
+		{name: "random", rate: 50, mode: SamplingRandom},
+		{name: "seeded", rate: 50, mode: SamplingSeeded},
+		{name: "unknown mode", rate: 50, mode: "roundrobin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSampler(tt.rate, tt.mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("NewSampler() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSampler() error = %v", err)
+			}
+			if tt.mode != "" && s.Mode() != tt.mode {
+				t.Errorf("Mode() = %q, want %q", s.Mode(), tt.mode)
+			}
+		})
+	}
+
+	t.Run("hash decisions are identical across samplers", func(t *testing.T) {
+		a, _ := NewSampler(50, SamplingHash)
+		b, _ := NewSampler(50, SamplingHash)
+		if fmt.Sprint(sampleKeys(a, 500)) != fmt.Sprint(sampleKeys(b, 500)) {
+			t.Error("hash samplers disagree")
+		}
+	})
+
+	t.Run("full rate keeps every log", func(t *testing.T) {
+		for _, rate := range []int{0, 100} {
+			s, _ := NewSampler(rate, SamplingRandom)
+			for i, keep := range sampleKeys(s, 100) {
+				if !keep {
+					t.Fatalf("rate %d dropped log %d", rate, i)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package redactor
+
+import (
+	"fmt"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// OversizeAction determines how inputs above the size limit are handled
+type OversizeAction string
+
+const (
+	// OversizeSkip skips detection entirely for oversized inputs
+	OversizeSkip OversizeAction = "skip"
+
+	// OversizeTruncate scans only a bounded prefix of oversized inputs
+	OversizeTruncate OversizeAction = "truncate"
+)
+
+// UnscannedPlaceholder replaces the text of an input that was not scanned
+// because it exceeded the size limit: all of it when skipped, the part past
+// the scanned prefix when truncated
+const UnscannedPlaceholder = "[UNSCANNED]"
+
+// ParseOversizeAction parses an oversize action name. An empty name is
+// OversizeTruncate.
+func ParseOversizeAction(s string) (OversizeAction, error) {
+	switch action := OversizeAction(s); action {
+	case "":
+		return OversizeTruncate, nil
+	case OversizeSkip, OversizeTruncate:
+		return action, nil
+	default:
+		return "", fmt.Errorf("unknown oversize action %q (available: skip, truncate)", s)
+	}
+}
+
+// InputLimiter enforces a maximum input size before detection
+type InputLimiter struct {
+	maxBytes  int
+	action    OversizeAction
+	skipped   atomic.Int64
+	truncated atomic.Int64
+}
+
+// NewInputLimiter creates a limiter for inputs of at most maxSizeKB kilobytes.
+// A non-positive size disables the limit; an unknown action defaults to truncate.
+func NewInputLimiter(maxSizeKB int, action OversizeAction) *InputLimiter {
+	if action != OversizeSkip {
+		action = OversizeTruncate
+	}
+	return &InputLimiter{
+		maxBytes: maxSizeKB * 1024,
+		action:   action,
+	}
+}
+
+// Limit returns the portion of text to scan and whether it should be scanned.
+// Truncation never splits a UTF-8 character.
+func (l *InputLimiter) Limit(text string) (string, bool) {
+	if l == nil || l.maxBytes <= 0 || len(text) <= l.maxBytes {
+		return text, true
+	}
+
+	if l.action == OversizeSkip {
+		l.skipped.Add(1)
+		return "", false
+	}
+
+	end := l.maxBytes
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	l.truncated.Add(1)
+	return text[:end], true
+}
+
+// SkippedCount returns the number of inputs skipped for exceeding the limit
+func (l *InputLimiter) SkippedCount() int64 {
+	return l.skipped.Load()
+}
+
+// TruncatedCount returns the number of inputs truncated to the limit
+func (l *InputLimiter) TruncatedCount() int64 {
+	return l.truncated.Load()
+}
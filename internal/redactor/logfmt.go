@@ -0,0 +1,190 @@
+package redactor
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+// errMalformedLogfmt is returned by parseLogfmt for lines that are not logfmt
+var errMalformedLogfmt = errors.New("malformed logfmt")
+
+// logfmtValue is the span of a value in a logfmt line
+type logfmtValue struct {
+	// Start and End delimit the raw value, including any quotes
+	Start int
+	End   int
+
+	// Quoted is true when the raw value is a quoted string
+	Quoted bool
+
+	// Bare is true for a run of bare words, free text outside of any
+	// key=value pair
+	Bare bool
+}
+
+// RedactLogfmt redacts PII in the values of a logfmt line such as
+// `level=info msg="user email=a@b.com"`. Each value is unquoted, scanned and
+// masked on its own, then quoted again when the masked value needs quoting, so
+// the line stays parseable. Runs of bare words, such as the free text in
+// `level=info user a@b.com logged in`, are scanned the same way. Keys are not
+// scanned. Lines that are not valid logfmt, and lines where masking free text
+// would break them, are redacted as plain text. Detection positions are
+// relative to the unquoted value they were found in.
+func (r *Redactor) RedactLogfmt(ctx context.Context, line string) (*RedactResult, error) {
+	scanText, ok := r.limit(line)
+	if !ok {
+		return skippedResult(line), nil
+	}
+
+	values, err := parseLogfmt(scanText)
+	if err != nil {
+		return r.redactScanned(ctx, line, scanText)
+	}
+
+	var detections []detector.DetectionResult
+	redactedCount, skipped := 0, 0
+	redacted := line
+	for i := len(values) - 1; i >= 0; i-- {
+		v := values[i]
+		raw := scanText[v.Start:v.End]
+		value := raw
+		if v.Quoted {
+			value = unquoteLogfmt(raw)
+		}
+
+		found, err := r.engine.Detect(ctx, detector.LogEntry{Message: value})
+		if err != nil {
+			return nil, err
+		}
+		result := r.redactDetections(value, value, found)
+		if len(result.Detections) == 0 {
+			continue
+		}
+
+		detections = append(detections, result.Detections...)
+		redactedCount += result.RedactedCount
+		skipped += result.SkippedCount
+		masked := result.RedactedText
+		if v.Bare {
+			// Free text cannot be quoted without becoming a malformed key
+			if strings.ContainsAny(masked, `="`) {
+				return r.redactScanned(ctx, line, scanText)
+			}
+		} else if v.Quoted || needsLogfmtQuoting(masked) {
+			masked = strconv.Quote(masked)
+		}
+		redacted = redacted[:v.Start] + masked + redacted[v.End:]
+	}
+
+	return &RedactResult{
+		OriginalText:  line,
+		RedactedText:  dropUnscanned(redacted, line, scanText),
+		Detections:    detections,
+		RedactedCount: redactedCount,
+		SkippedCount:  skipped,
+		Scanned:       true,
+		Truncated:     len(scanText) < len(line),
+	}, nil
+}
+
+// parseLogfmt returns the value spans of the key=value pairs in line, and the
+// spans of runs of bare keys without a value, which are free text
+func parseLogfmt(line string) ([]logfmtValue, error) {
+	var values []logfmtValue
+
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		keyStart := i
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		if i == keyStart || (i < len(line) && line[i] == '"') {
+			return nil, errMalformedLogfmt
+		}
+		if i == len(line) || line[i] != '=' {
+			// Join bare words separated only by blanks into one run, so that
+			// values written with spaces, such as card numbers, are found
+			if n := len(values); n > 0 && values[n-1].Bare && strings.TrimLeft(line[values[n-1].End:keyStart], " \t") == "" {
+				values[n-1].End = i
+			} else {
+				values = append(values, logfmtValue{Start: keyStart, End: i, Bare: true})
+			}
+			continue
+		}
+		i++
+
+		start := i
+		if i < len(line) && line[i] == '"' {
+			end, ok := closingQuote(line, i)
+			if !ok {
+				return nil, errMalformedLogfmt
+			}
+			i = end + 1
+			if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				return nil, errMalformedLogfmt
+			}
+			values = append(values, logfmtValue{Start: start, End: i, Quoted: true})
+			continue
+		}
+
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			if line[i] == '"' {
+				return nil, errMalformedLogfmt
+			}
+			i++
+		}
+		if i > start {
+			values = append(values, logfmtValue{Start: start, End: i})
+		}
+	}
+
+	return values, nil
+}
+
+// closingQuote returns the index of the quote closing the string that opens
+// at line[open]
+func closingQuote(line string, open int) (int, bool) {
+	for i := open + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// unquoteLogfmt unquotes a quoted logfmt value. Escapes Go does not accept
+// are kept as written.
+func unquoteLogfmt(raw string) string {
+	if value, err := strconv.Unquote(raw); err == nil {
+		return value
+	}
+	return raw[1 : len(raw)-1]
+}
+
+// needsLogfmtQuoting reports whether a value must be quoted to be read back
+// as a single logfmt value
+func needsLogfmtQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, c := range value {
+		if c == '=' || c == '"' || c == '\\' || c == utf8.RuneError || unicode.IsSpace(c) || unicode.IsControl(c) {
+			return true
+		}
+	}
+	return false
+}
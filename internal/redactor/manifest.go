@@ -0,0 +1,88 @@
+package redactor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// minManifestKeySize is the shortest key accepted for manifest fingerprints
+const minManifestKeySize = 16
+
+// Manifest lists the redactions made in a text: where each masked value
+// was, which pattern found it and a keyed fingerprint of it, but never the
+// value itself. Stored alongside the redacted text, it tells incident
+// responders which positions held what kind of PII, and lets them check
+// whether a known value was among them, without the PII being retained.
+type Manifest struct {
+	// Redactions are ordered by position
+	Redactions []ManifestEntry `json:"redactions"`
+}
+
+// ManifestEntry records a single redaction
+type ManifestEntry struct {
+	// Start and End are the byte offsets of the masked value in the original text
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// RedactedStart and RedactedEnd are the byte offsets of its replacement
+	// in the redacted text
+	RedactedStart int `json:"redactedStart"`
+	RedactedEnd   int `json:"redactedEnd"`
+
+	Pattern    string `json:"pattern"`
+	Severity   string `json:"severity,omitempty"`
+	Confidence string `json:"confidence,omitempty"`
+
+	// Masking is the type of the masking strategy applied
+	Masking string `json:"masking,omitempty"`
+
+	// Fingerprint is the keyed fingerprint of the masked value; see
+	// Redactor.Fingerprint
+	Fingerprint string `json:"fingerprint"`
+}
+
+// SetManifestKey makes redaction results carry a Manifest, fingerprinting
+// masked values with HMAC-SHA256 under key. Keying keeps fingerprints of
+// low-entropy values such as phone numbers from being reversed by hashing
+// every candidate, so the key must be kept apart from stored manifests.
+// Keys must be at least 16 bytes; a nil key disables manifests.
+func (r *Redactor) SetManifestKey(key []byte) error {
+	if key != nil && len(key) < minManifestKeySize {
+		return fmt.Errorf("manifest key must be at least %d bytes", minManifestKeySize)
+	}
+	r.manifestKey = key
+	return nil
+}
+
+// Fingerprint returns the fingerprint manifests record for value, so that a
+// known value can be looked up in stored manifests. It returns an empty
+// string when manifests are disabled.
+func (r *Redactor) Fingerprint(value string) string {
+	if r.manifestKey == nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, r.manifestKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// newManifest builds a manifest from the entries of replacements made from
+// the end of the text to its start, whose RedactedEnd holds the length of
+// the replacement, resolving their offsets in the redacted text
+func newManifest(reversed []ManifestEntry) *Manifest {
+	manifest := &Manifest{Redactions: make([]ManifestEntry, 0, len(reversed))}
+
+	// shift is how far the replacements so far moved the text after them
+	shift := 0
+	for i := len(reversed) - 1; i >= 0; i-- {
+		entry := reversed[i]
+		length := entry.RedactedEnd
+		entry.RedactedStart = entry.Start + shift
+		entry.RedactedEnd = entry.RedactedStart + length
+		shift += length - (entry.End - entry.Start)
+		manifest.Redactions = append(manifest.Redactions, entry)
+	}
+	return manifest
+}
@@ -0,0 +1,130 @@
+package redactor
+
+import (
+	"container/list"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// DefaultPseudonymPrefix prefixes pseudonyms of strategies without a Replacement
+const DefaultPseudonymPrefix = "PII"
+
+// DefaultMaxPseudonyms is the number of values a Pseudonyms remembers by default
+const DefaultMaxPseudonyms = 100000
+
+// pseudonymWidth is the number of digits of a pseudonym's sequence number.
+// Numbers beyond 999999 use more digits rather than wrap around.
+const pseudonymWidth = 6
+
+// pseudonymKeySize is the size of the random key values are hashed with
+const pseudonymKeySize = 32
+
+// Pseudonyms maps each distinct value to a sequential pseudonym such as
+// USER_000001, so that distinct values can be counted without being seen.
+// Each prefix numbers its values separately, starting at 1.
+//
+// Values are remembered by their HMAC-SHA256 under a random key generated
+// for each Pseudonyms, so the mapping never holds plaintext PII. It holds at
+// most a limited number of values and forgets the least recently seen one
+// to make room for a new value; a forgotten value seen again is assigned a
+// new pseudonym. Pseudonyms is safe for concurrent use.
+type Pseudonyms struct {
+	mu       sync.Mutex
+	key      []byte
+	limit    int
+	assigned map[pseudonymKey]*list.Element
+	// recent orders the assigned pseudonyms from most to least recently seen
+	recent   *list.List
+	counters map[string]int
+}
+
+// pseudonymKey identifies a value, by its keyed digest, within a prefix
+type pseudonymKey struct {
+	prefix string
+	digest [sha256.Size]byte
+}
+
+// pseudonymEntry is an assigned pseudonym in the recency list
+type pseudonymEntry struct {
+	key       pseudonymKey
+	pseudonym string
+}
+
+// NewPseudonyms creates an empty pseudonym mapping remembering up to
+// DefaultMaxPseudonyms values
+func NewPseudonyms() *Pseudonyms {
+	key := make([]byte, pseudonymKeySize)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("generating pseudonym key: %v", err))
+	}
+	return &Pseudonyms{
+		key:      key,
+		limit:    DefaultMaxPseudonyms,
+		assigned: make(map[pseudonymKey]*list.Element),
+		recent:   list.New(),
+		counters: make(map[string]int),
+	}
+}
+
+// SetLimit sets the number of values remembered, forgetting the least
+// recently seen values beyond it. A limit below 1 is treated as 1.
+func (p *Pseudonyms) SetLimit(limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.limit = max(limit, 1)
+	p.evict()
+}
+
+// Pseudonym returns the pseudonym of value under prefix, assigning the next
+// sequence number of the prefix if the value has not been seen before
+func (p *Pseudonyms) Pseudonym(prefix, value string) string {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write([]byte(value))
+	key := pseudonymKey{prefix: prefix}
+	mac.Sum(key.digest[:0])
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.assigned[key]; ok {
+		p.recent.MoveToFront(element)
+		return element.Value.(*pseudonymEntry).pseudonym
+	}
+
+	p.counters[prefix]++
+	pseudonym := fmt.Sprintf("%s_%0*d", prefix, pseudonymWidth, p.counters[prefix])
+	p.assigned[key] = p.recent.PushFront(&pseudonymEntry{key: key, pseudonym: pseudonym})
+	p.evict()
+	return pseudonym
+}
+
+// Len returns the number of values whose pseudonym is remembered
+func (p *Pseudonyms) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.assigned)
+}
+
+// evict forgets the least recently seen values beyond the limit. The caller
+// must hold p.mu.
+func (p *Pseudonyms) evict() {
+	for p.recent.Len() > p.limit {
+		oldest := p.recent.Back()
+		p.recent.Remove(oldest)
+		delete(p.assigned, oldest.Value.(*pseudonymEntry).key)
+	}
+}
+
+// pseudonymPrefix returns the prefix a pseudonym strategy assigns under
+func pseudonymPrefix(strategy patterns.MaskingStrategy) string {
+	if strategy.Replacement != "" {
+		return strategy.Replacement
+	}
+	return DefaultPseudonymPrefix
+}
@@ -0,0 +1,126 @@
+package redactor
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// QueryParamPatternName is the pattern name reported for sensitive URL query values
+const QueryParamPatternName = "url-query-param"
+
+// DefaultSensitiveQueryKeys are query parameter names whose values are
+// redacted when query parameter redaction is enabled
+var DefaultSensitiveQueryKeys = []string{
+	"token", "access_token", "refresh_token", "id_token", "api_key", "apikey",
+	"key", "secret", "client_secret", "password", "passwd", "pwd", "auth",
+	"signature", "sig", "session", "sessionid", "email",
+}
+
+// queryParamMasking is applied to the values of sensitive query parameters
+var queryParamMasking = patterns.MaskingStrategy{Type: "full", Replacement: "[REDACTED]"}
+
+// urlWithQueryRegex finds absolute URLs and request paths that carry a query string
+var urlWithQueryRegex = regexp.MustCompile(`(?:[a-zA-Z][a-zA-Z0-9+.-]*://|/)[^\s"'<>?]*\?[^\s"'<>]+`)
+
+// SetSensitiveQueryKeys enables redaction of URL query parameter values whose
+// keys match one of the given names (case-insensitive), regardless of whether
+// the value matches a pattern. A nil or empty list disables it.
+func (r *Redactor) SetSensitiveQueryKeys(keys []string) {
+	if len(keys) == 0 {
+		r.queryKeys = nil
+		return
+	}
+
+	r.queryKeys = make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			r.queryKeys[key] = true
+		}
+	}
+}
+
+// detectQueryParams returns a detection for the value of every sensitive
+// query parameter in the URLs found in text
+func (r *Redactor) detectQueryParams(text string) []detector.DetectionResult {
+	if len(r.queryKeys) == 0 {
+		return nil
+	}
+
+	var results []detector.DetectionResult
+	for _, loc := range urlWithQueryRegex.FindAllStringIndex(text, -1) {
+		if _, err := url.Parse(text[loc[0]:loc[1]]); err != nil {
+			continue
+		}
+
+		queryStart := loc[0] + strings.IndexByte(text[loc[0]:loc[1]], '?') + 1
+		queryEnd := loc[1]
+		if i := strings.IndexByte(text[queryStart:queryEnd], '#'); i >= 0 {
+			queryEnd = queryStart + i
+		}
+
+		offset := queryStart
+		for _, pair := range strings.Split(text[queryStart:queryEnd], "&") {
+			pairStart := offset
+			offset += len(pair) + 1
+
+			rawKey, value, ok := strings.Cut(pair, "=")
+			if !ok || value == "" {
+				continue
+			}
+			key, err := url.QueryUnescape(rawKey)
+			if err != nil || !r.queryKeys[strings.ToLower(key)] {
+				continue
+			}
+
+			start := pairStart + len(rawKey) + 1
+			results = append(results, detector.DetectionResult{
+				PatternName: QueryParamPatternName,
+				DisplayName: "URL Query Parameter",
+				MatchedText: value,
+				Position:    detector.Position{Start: start, End: start + len(value)},
+				Confidence:  "high",
+				Severity:    "high",
+				Metadata:    map[string]string{"key": key},
+			})
+		}
+	}
+
+	return results
+}
+
+// mergeQueryParams adds query parameter detections to the pattern detections.
+// Pattern matches inside a redacted value are dropped in favour of the query
+// parameter, while a query parameter inside a larger pattern match is dropped.
+func mergeQueryParams(detections, params []detector.DetectionResult) []detector.DetectionResult {
+	for _, p := range params {
+		if partiallyOverlapped(detections, p.Position) {
+			continue
+		}
+
+		kept := detections[:0]
+		for _, d := range detections {
+			if d.Position.Start < p.Position.Start || d.Position.End > p.Position.End {
+				kept = append(kept, d)
+			}
+		}
+		detections = append(kept, p)
+	}
+	return detections
+}
+
+// partiallyOverlapped reports whether any detection overlaps pos without
+// lying entirely inside it
+func partiallyOverlapped(detections []detector.DetectionResult, pos detector.Position) bool {
+	for _, d := range detections {
+		overlaps := d.Position.Start < pos.End && pos.Start < d.Position.End
+		inside := d.Position.Start >= pos.Start && d.Position.End <= pos.End
+		if overlaps && !inside {
+			return true
+		}
+	}
+	return false
+}
@@ -4,125 +4,425 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"sort"
+	"errors"
+	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/bunseokbot/pii-redactor/internal/detector"
 	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
 )
 
+// ErrNoPatterns is returned when an explicit pattern list contains no pattern names
+var ErrNoPatterns = errors.New("no patterns specified")
+
 // Redactor handles masking/redaction of PII
 type Redactor struct {
-	engine *detector.Engine
+	engine          *detector.Engine
+	limiter         *InputLimiter
+	queryKeys       map[string]bool
+	severityMasking map[string]patterns.MaskingStrategy
+	defaultMasking  *patterns.MaskingStrategy
+	pseudonyms      *Pseudonyms
+	minConfidence   string
+	manifestKey     []byte
 }
 
 // NewRedactor creates a new redactor
 func NewRedactor(engine *detector.Engine) *Redactor {
 	return &Redactor{
-		engine: engine,
+		engine:     engine,
+		pseudonyms: NewPseudonyms(),
 	}
 }
 
+// Pseudonyms returns the mapping of values to the pseudonyms assigned by
+// pseudonym masking, which is stable for the lifetime of the redactor as long
+// as values are not forgotten to stay within its limit
+func (r *Redactor) Pseudonyms() *Pseudonyms {
+	return r.pseudonyms
+}
+
+// SetInputLimiter sets the limiter enforcing the maximum input size.
+// A nil limiter disables the limit.
+func (r *Redactor) SetInputLimiter(limiter *InputLimiter) {
+	r.limiter = limiter
+}
+
+// SetSeverityMasking sets masking strategies by severity that override the
+// strategies of the patterns, so that redacted output shows how severe each
+// finding was. Detections of severities missing from the map keep their
+// pattern's strategy. A nil map removes the overrides.
+func (r *Redactor) SetSeverityMasking(strategies map[string]patterns.MaskingStrategy) {
+	r.severityMasking = strategies
+}
+
+// SetDefaultMasking sets the masking strategy used for detections whose
+// pattern has no valid strategy, e.g. a community rule without one, instead
+// of partial masking with whatever reveal amounts the pattern sets. A nil
+// strategy restores that fallback.
+func (r *Redactor) SetDefaultMasking(strategy *patterns.MaskingStrategy) error {
+	if strategy != nil && !strategy.Valid() {
+		return fmt.Errorf("invalid default masking strategy %q", strategy.Type)
+	}
+	r.defaultMasking = strategy
+	return nil
+}
+
+// SetMinRedactConfidence sets the confidence a detection needs to be
+// redacted. Detections below it are still reported in the result's
+// Detections, without a RedactedText, but are left in the text, so that
+// likely false positives can be reviewed without corrupting the output.
+// Detections without a confidence are always redacted. An empty confidence
+// redacts every detection.
+func (r *Redactor) SetMinRedactConfidence(confidence string) error {
+	if confidence != "" && !detector.IsConfidenceLevel(confidence) {
+		return fmt.Errorf("invalid confidence %q: must be low, medium or high", confidence)
+	}
+	r.minConfidence = confidence
+	return nil
+}
+
+// redactsConfidence reports whether detections of the given confidence are
+// redacted
+func (r *Redactor) redactsConfidence(confidence string) bool {
+	return r.minConfidence == "" || confidence == "" || detector.ConfidenceAtLeast(confidence, r.minConfidence)
+}
+
 // RedactResult represents the result of redaction
 type RedactResult struct {
-	OriginalText  string
-	RedactedText  string
-	Detections    []detector.DetectionResult
+	OriginalText string
+	RedactedText string
+	Detections   []detector.DetectionResult
+
+	// RedactedCount is the number of detections that were masked, which is
+	// less than len(Detections) when some fall below the minimum redaction
+	// confidence
 	RedactedCount int
+
+	// SkippedCount is the number of detections that could not be masked
+	// because their span lies outside the text
+	SkippedCount int
+
+	// Scanned is true when the input was scanned for PII, in full or, when
+	// Truncated, in part
+	Scanned bool
+
+	// Skipped is true when the input exceeded the size limit and was not
+	// scanned; RedactedText is then UnscannedPlaceholder
+	Skipped bool
+
+	// Truncated is true when only a prefix of the input was scanned; the rest
+	// is replaced with UnscannedPlaceholder in RedactedText
+	Truncated bool
+
+	// Blocked is true when a policy replaced the entire text with a block notice
+	Blocked bool
+
+	// Manifest lists the redactions made in the text when a manifest key is
+	// set (see SetManifestKey), and is nil otherwise or when the input was
+	// not scanned
+	Manifest *Manifest
+}
+
+// Clean reports whether the whole input was scanned and no PII was found.
+// A nil, skipped or truncated result is never clean, so a failed or partial
+// scan cannot be mistaken for one that found nothing.
+func (r *RedactResult) Clean() bool {
+	return r != nil && r.Scanned && !r.Truncated && len(r.Detections) == 0
 }
 
 // Redact detects and redacts PII from text
 func (r *Redactor) Redact(ctx context.Context, text string) (*RedactResult, error) {
-	// Detect PII
-	detections, err := r.engine.Detect(ctx, detector.LogEntry{Message: text})
+	scanText, ok := r.limit(text)
+	if !ok {
+		return skippedResult(text), nil
+	}
+	return r.redactScanned(ctx, text, scanText)
+}
+
+// redactScanned detects PII with all enabled patterns in scanText, the
+// prefix of text left by the input limiter, and redacts it in text
+func (r *Redactor) redactScanned(ctx context.Context, text, scanText string) (*RedactResult, error) {
+	detections, err := r.engine.Detect(ctx, detector.LogEntry{Message: scanText})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(detections) == 0 {
-		return &RedactResult{
-			OriginalText:  text,
-			RedactedText:  text,
-			Detections:    detections,
-			RedactedCount: 0,
-		}, nil
+	return r.redactDetections(text, scanText, detections), nil
+}
+
+// limit applies the input limiter, counting skipped and truncated inputs in
+// the engine's stats
+func (r *Redactor) limit(text string) (string, bool) {
+	scanText, ok := r.limiter.Limit(text)
+	if !ok || len(scanText) < len(text) {
+		r.engine.RecordOversizedInput(!ok)
 	}
+	return scanText, ok
+}
 
-	// Sort detections by position (descending) to process from end to start
-	sort.Slice(detections, func(i, j int) bool {
-		return detections[i].Position.Start > detections[j].Position.Start
-	})
+// RedactWithPatterns redacts using only specified patterns. Pattern names are
+// trimmed and blank names ignored; ErrNoPatterns is returned if none remain.
+// Use Redact to scan with all enabled patterns.
+func (r *Redactor) RedactWithPatterns(ctx context.Context, text string, patternNames []string) (*RedactResult, error) {
+	patternNames = cleanPatternNames(patternNames)
+	if len(patternNames) == 0 {
+		return nil, ErrNoPatterns
+	}
+
+	scanText, ok := r.limit(text)
+	if !ok {
+		return skippedResult(text), nil
+	}
+
+	// Detect PII with specified patterns
+	detections, err := r.engine.DetectWithPatterns(ctx, scanText, patternNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.redactDetections(text, scanText, detections), nil
+}
+
+// redactDetections masks the detections in text. scanText is the prefix of
+// text that was scanned; the rest is replaced with UnscannedPlaceholder.
+func (r *Redactor) redactDetections(text, scanText string, detections []detector.DetectionResult) *RedactResult {
+	if params := r.detectQueryParams(scanText); len(params) > 0 {
+		detections = mergeQueryParams(detections, params)
+	}
+	detections = r.resolveOverlaps(text, detections)
+
+	// Detections are reported in order of position, so that identical input
+	// gives identical results, and replaced from end to start
+	detector.SortDetections(detections)
 
 	redactedText := text
-	for i := range detections {
+	// replacedFrom is the start of the earliest span replaced so far; the
+	// text after it may no longer line up with the original offsets
+	replacedFrom := len(text)
+	redactedCount, skipped := 0, 0
+	var replaced []ManifestEntry
+	for i := len(detections) - 1; i >= 0; i-- {
 		d := &detections[i]
-		strategy, ok := r.engine.GetMaskingStrategy(d.PatternName)
+		if _, ok := widenSpan(d.Position, text); !ok {
+			skipped++
+			continue
+		}
+		if !r.redactsConfidence(d.Confidence) {
+			continue
+		}
+		strategy, ok := r.MaskingStrategy(d)
 		if !ok {
 			continue
 		}
+		// Mask the bytes that are replaced, so that a matched text that
+		// does not line up with the span cannot shift the text around it
+		d.MatchedText = text[d.Position.Start:d.Position.End]
 
-		masked := ApplyMasking(d.MatchedText, strategy)
+		masked := r.MaskDetection(d, strategy)
 		d.RedactedText = masked
+		redactedCount++
 
-		// Replace in text
+		// Replace in text, unless the span overlaps one already replaced
+		if d.Position.End > replacedFrom {
+			continue
+		}
 		redactedText = redactedText[:d.Position.Start] + masked + redactedText[d.Position.End:]
+		replacedFrom = d.Position.Start
+
+		if r.manifestKey != nil {
+			replaced = append(replaced, ManifestEntry{
+				Start:       d.Position.Start,
+				End:         d.Position.End,
+				RedactedEnd: len(masked),
+				Pattern:     d.PatternName,
+				Severity:    d.Severity,
+				Confidence:  d.Confidence,
+				Masking:     strategy.Type,
+				Fingerprint: r.Fingerprint(d.MatchedText),
+			})
+		}
 	}
 
-	return &RedactResult{
+	result := &RedactResult{
 		OriginalText:  text,
-		RedactedText:  redactedText,
+		RedactedText:  dropUnscanned(redactedText, text, scanText),
 		Detections:    detections,
-		RedactedCount: len(detections),
-	}, nil
+		RedactedCount: redactedCount,
+		SkippedCount:  skipped,
+		Scanned:       true,
+		Truncated:     len(scanText) < len(text),
+	}
+	if r.manifestKey != nil {
+		result.Manifest = newManifest(replaced)
+	}
+	return result
 }
 
-// RedactWithPatterns redacts using only specified patterns
-func (r *Redactor) RedactWithPatterns(ctx context.Context, text string, patternNames []string) (*RedactResult, error) {
-	// Detect PII with specified patterns
-	detections, err := r.engine.DetectWithPatterns(ctx, text, patternNames)
-	if err != nil {
-		return nil, err
+// resolveOverlaps drops detections that overlap a stronger one, so that no
+// span of text is masked twice. Spans are first widened to whole characters
+// (see widenSpan); detections whose span does not overlap the text cannot be
+// masked and are kept without competing with the others.
+func (r *Redactor) resolveOverlaps(text string, detections []detector.DetectionResult) []detector.DetectionResult {
+	var valid, invalid []detector.DetectionResult
+	for _, d := range detections {
+		if pos, ok := widenSpan(d.Position, text); ok {
+			d.Position = pos
+			valid = append(valid, d)
+		} else {
+			invalid = append(invalid, d)
+		}
 	}
+	return append(r.engine.ResolveOverlaps(valid), invalid...)
+}
 
-	if len(detections) == 0 {
-		return &RedactResult{
-			OriginalText:  text,
-			RedactedText:  text,
-			Detections:    detections,
-			RedactedCount: 0,
-		}, nil
+// widenSpan clamps pos to text and widens it to the enclosing character
+// boundaries, so that it can be replaced without slicing out of range or
+// splitting a multibyte character, and masks at least the bytes it covered.
+// It reports false when no part of the span lies within text.
+func widenSpan(pos detector.Position, text string) (detector.Position, bool) {
+	start, end := max(pos.Start, 0), min(pos.End, len(text))
+	if start >= end {
+		return pos, false
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
 	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	pos.Start, pos.End = start, end
+	return pos, true
+}
 
-	// Sort detections by position (descending) to process from end to start
-	sort.Slice(detections, func(i, j int) bool {
-		return detections[i].Position.Start > detections[j].Position.Start
-	})
+// MaskingStrategy returns the masking strategy for a detection: the
+// override for its severity if one is set, otherwise its pattern's strategy,
+// or the default strategy if the pattern's is not valid
+func (r *Redactor) MaskingStrategy(d *detector.DetectionResult) (patterns.MaskingStrategy, bool) {
+	if strategy, ok := r.severityMasking[d.Severity]; ok {
+		return strategy, true
+	}
+	if d.PatternName == QueryParamPatternName {
+		return queryParamMasking, true
+	}
+	strategy, ok := r.engine.GetMaskingStrategyForConfidence(d.PatternName, d.Confidence)
+	if ok && r.defaultMasking != nil && !strategy.Valid() {
+		return *r.defaultMasking, true
+	}
+	return strategy, ok
+}
 
-	redactedText := text
-	for i := range detections {
-		d := &detections[i]
-		strategy, ok := r.engine.GetMaskingStrategy(d.PatternName)
-		if !ok {
-			continue
+// cleanPatternNames trims pattern names and drops blank ones
+func cleanPatternNames(names []string) []string {
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			cleaned = append(cleaned, name)
 		}
+	}
+	return cleaned
+}
 
-		masked := ApplyMasking(d.MatchedText, strategy)
-		d.RedactedText = masked
+// skippedResult returns the result for an input that was not scanned, whose
+// text is withheld behind UnscannedPlaceholder
+func skippedResult(text string) *RedactResult {
+	return &RedactResult{
+		OriginalText: text,
+		RedactedText: UnscannedPlaceholder,
+		Skipped:      true,
+	}
+}
 
-		// Replace in text
-		redactedText = redactedText[:d.Position.Start] + masked + redactedText[d.Position.End:]
+// dropUnscanned replaces the tail of redacted that was not scanned, the part
+// of text past scanText, with UnscannedPlaceholder. Redaction only replaces
+// spans within scanText, so the tail is still at the end of redacted.
+func dropUnscanned(redacted, text, scanText string) string {
+	if len(scanText) >= len(text) {
+		return redacted
 	}
+	return redacted[:len(redacted)-(len(text)-len(scanText))] + UnscannedPlaceholder
+}
 
-	return &RedactResult{
-		OriginalText:  text,
-		RedactedText:  redactedText,
-		Detections:    detections,
-		RedactedCount: len(detections),
-	}, nil
+// Mask applies a masking strategy to text like ApplyMasking, assigning
+// pseudonyms from the redactor's mapping
+func (r *Redactor) Mask(text string, strategy patterns.MaskingStrategy) string {
+	if head, body, tail, ok := splitBoundaryLines(text, strategy); ok {
+		strategy.PreserveBoundaryLines = false
+		return head + r.Mask(body, strategy) + tail
+	}
+
+	var masked string
+	if strategy.Type == "pseudonym" {
+		masked = r.pseudonyms.Pseudonym(pseudonymPrefix(strategy), text)
+	} else {
+		masked = applyMaskingStep(text, strategy)
+	}
+	for _, step := range strategy.Chain {
+		masked = r.Mask(masked, step)
+	}
+	return masked
+}
+
+// MaskDetection masks the matched text of a detection like Mask. When the
+// strategy targets a capture group, only the group's text is masked; if the
+// group cannot be located in the match, the whole match is masked.
+func (r *Redactor) MaskDetection(d *detector.DetectionResult, strategy patterns.MaskingStrategy) string {
+	if strategy.Group == "" {
+		return r.Mask(d.MatchedText, strategy)
+	}
+	start, end, ok := r.engine.CaptureSpan(d.PatternName, d.MatchedText, strategy.Group)
+	strategy.Group = ""
+	if !ok {
+		return r.Mask(d.MatchedText, strategy)
+	}
+	return d.MatchedText[:start] + r.Mask(d.MatchedText[start:end], strategy) + d.MatchedText[end:]
 }
 
-// ApplyMasking applies a masking strategy to text
+// ApplyMasking applies a masking strategy to text, followed by each step of
+// its chain in order. Without a redactor's mapping to keep pseudonyms
+// sequential, pseudonym masking falls back to tokenize.
 func ApplyMasking(text string, strategy patterns.MaskingStrategy) string {
+	if head, body, tail, ok := splitBoundaryLines(text, strategy); ok {
+		strategy.PreserveBoundaryLines = false
+		return head + ApplyMasking(body, strategy) + tail
+	}
+
+	masked := applyMaskingStep(text, strategy)
+	for _, step := range strategy.Chain {
+		masked = ApplyMasking(masked, step)
+	}
+	return masked
+}
+
+// splitBoundaryLines splits multiline text into its first line, the lines
+// between the first and last, and its last line when the strategy preserves
+// boundary lines. The line breaks stay with head and tail. ok is false for
+// text of fewer than three lines, which is masked as a whole.
+func splitBoundaryLines(text string, strategy patterns.MaskingStrategy) (head, body, tail string, ok bool) {
+	if !strategy.PreserveBoundaryLines {
+		return "", "", "", false
+	}
+
+	first := strings.IndexByte(text, '\n')
+	last := strings.LastIndexByte(text, '\n')
+	if first < 0 || first == last {
+		return "", "", "", false
+	}
+
+	body = text[first+1 : last]
+	cut := len(body)
+	if strings.HasSuffix(body, "\r") {
+		cut--
+	}
+	if cut == 0 {
+		return "", "", "", false
+	}
+	return text[:first+1], body[:cut], body[cut:] + text[last:], true
+}
+
+// applyMaskingStep applies a single masking strategy, ignoring its chain
+func applyMaskingStep(text string, strategy patterns.MaskingStrategy) string {
 	switch strategy.Type {
 	case "full":
 		if strategy.Replacement != "" {
@@ -136,9 +436,12 @@ func ApplyMasking(text string, strategy patterns.MaskingStrategy) string {
 	case "hash":
 		return hashText(text)
 
-	case "tokenize":
+	case "tokenize", "pseudonym":
 		return tokenize(text)
 
+	case "maskBefore", "maskAfter":
+		return applyDelimiterMasking(text, strategy)
+
 	default:
 		return applyPartialMasking(text, strategy)
 	}
@@ -149,8 +452,8 @@ func applyPartialMasking(text string, strategy patterns.MaskingStrategy) string
 	runes := []rune(text)
 	length := len(runes)
 
-	showFirst := strategy.ShowFirst
-	showLast := strategy.ShowLast
+	showFirst := resolveReveal(strategy.ShowFirst, strategy.ShowFirstPercent, length)
+	showLast := resolveReveal(strategy.ShowLast, strategy.ShowLastPercent, length)
 	maskChar := getMaskChar(strategy)
 
 	// Adjust if total visible characters exceed length
@@ -177,6 +480,44 @@ func applyPartialMasking(text string, strategy patterns.MaskingStrategy) string
 	return result.String()
 }
 
+// applyDelimiterMasking masks the text before the last occurrence of the
+// delimiter (maskBefore) or after the first one (maskAfter), leaving the
+// delimiter and the other side visible. Text without the delimiter is
+// masked with partial masking.
+func applyDelimiterMasking(text string, strategy patterns.MaskingStrategy) string {
+	start, end, ok := delimiterMaskSpan(text, strategy)
+	if !ok {
+		return applyPartialMasking(text, strategy)
+	}
+
+	masked := strings.Repeat(getMaskChar(strategy), utf8.RuneCountInString(text[start:end]))
+	return text[:start] + masked + text[end:]
+}
+
+// delimiterMaskSpan returns the byte span of text that delimiter masking
+// hides. ok is false if the text does not contain the delimiter.
+func delimiterMaskSpan(text string, strategy patterns.MaskingStrategy) (start, end int, ok bool) {
+	if strategy.Delimiter == "" {
+		return 0, 0, false
+	}
+
+	if strategy.Type == "maskBefore" {
+		i := strings.LastIndex(text, strategy.Delimiter)
+		return 0, i, i >= 0
+	}
+	i := strings.Index(text, strategy.Delimiter)
+	return i + len(strategy.Delimiter), len(text), i >= 0
+}
+
+// resolveReveal returns the number of characters to reveal, scaling the
+// percentage against the matched length when one is set
+func resolveReveal(count, percent, length int) int {
+	if percent > 0 {
+		return length * percent / 100
+	}
+	return count
+}
+
 // getMaskChar returns the masking character
 func getMaskChar(strategy patterns.MaskingStrategy) string {
 	if strategy.MaskChar != "" {
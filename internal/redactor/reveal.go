@@ -0,0 +1,69 @@
+package redactor
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+)
+
+// DefaultMaxRevealRatio is the largest share of a value that masking may leave
+// visible for critical and high severity patterns
+const DefaultMaxRevealRatio = 0.5
+
+// RevealedCount returns how many characters of text the masking strategy
+// leaves visible. Chained steps can only hide more of the original value.
+func RevealedCount(text string, strategy patterns.MaskingStrategy) int {
+	if head, body, tail, ok := splitBoundaryLines(text, strategy); ok {
+		strategy.PreserveBoundaryLines = false
+		return utf8.RuneCountInString(head) + RevealedCount(body, strategy) + utf8.RuneCountInString(tail)
+	}
+
+	revealed := revealedByStep(text, strategy)
+	for _, step := range strategy.Chain {
+		revealed = min(revealed, RevealedCount(text, step))
+	}
+	return revealed
+}
+
+// revealedByStep returns how many characters a single masking step leaves visible
+func revealedByStep(text string, strategy patterns.MaskingStrategy) int {
+	switch strategy.Type {
+	case "full", "hash", "tokenize", "pseudonym":
+		return 0
+	case "maskBefore", "maskAfter":
+		if start, end, ok := delimiterMaskSpan(text, strategy); ok {
+			return utf8.RuneCountInString(text) - utf8.RuneCountInString(text[start:end])
+		}
+	}
+
+	length := len([]rune(text))
+	showFirst := resolveReveal(strategy.ShowFirst, strategy.ShowFirstPercent, length)
+	showLast := resolveReveal(strategy.ShowLast, strategy.ShowLastPercent, length)
+
+	// applyPartialMasking masks everything when the reveal covers the value
+	if showFirst+showLast >= length {
+		return 0
+	}
+	return showFirst + showLast
+}
+
+// RevealViolations returns a message for every sample value of which the
+// masking strategy reveals more than maxRatio. Messages identify samples by
+// index so that the values themselves are not repeated.
+func RevealViolations(strategy patterns.MaskingStrategy, samples []string, maxRatio float64) []string {
+	var violations []string
+	for i, sample := range samples {
+		length := len([]rune(sample))
+		if length == 0 {
+			continue
+		}
+
+		revealed := RevealedCount(sample, strategy)
+		if float64(revealed)/float64(length) > maxRatio {
+			violations = append(violations, fmt.Sprintf("reveals %d of %d characters of sample %d (max %.0f%%)",
+				revealed, length, i, maxRatio*100))
+		}
+	}
+	return violations
+}
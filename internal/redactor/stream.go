@@ -0,0 +1,123 @@
+package redactor
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineBytes is the longest line RedactStream redacts when the
+// options set no limit
+const DefaultMaxLineBytes = 1024 * 1024
+
+// LineFailedPlaceholder replaces lines that could not be redacted, so that
+// the output keeps one line per input line without leaking the original
+const LineFailedPlaceholder = "[REDACTION_FAILED]"
+
+// ErrLineTooLong is reported for lines longer than the stream's maximum
+var ErrLineTooLong = errors.New("line too long")
+
+// StreamOptions configures RedactStream
+type StreamOptions struct {
+	// MaxLineBytes is the longest line redacted, excluding the newline.
+	// Longer lines are discarded without being buffered whole. Zero uses
+	// DefaultMaxLineBytes.
+	MaxLineBytes int
+
+	// OnError is called with the 1-based line number of every line that
+	// could not be redacted. Such lines are written as LineFailedPlaceholder
+	// and the stream continues.
+	OnError func(line int, err error)
+}
+
+// RedactStream redacts in line by line, writing each redacted line to out
+// before reading the next, so that output keeps the input's order and a slow
+// reader of out slows down the reading of in. It returns the number of lines
+// written, and stops early only when reading or writing fails or ctx is
+// done; errors redacting a single line are reported to OnError instead.
+func (r *Redactor) RedactStream(ctx context.Context, in io.Reader, out io.Writer, opts StreamOptions) (int, error) {
+	maxLine := opts.MaxLineBytes
+	if maxLine <= 0 {
+		maxLine = DefaultMaxLineBytes
+	}
+	reader := bufio.NewReaderSize(in, 64*1024)
+
+	lines := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return lines, err
+		}
+
+		line, newline, err := readLine(reader, maxLine)
+		eof := err == io.EOF
+		if eof {
+			if line == "" {
+				return lines, nil
+			}
+			err = nil
+		} else if err != nil && !errors.Is(err, ErrLineTooLong) {
+			return lines, err
+		}
+		lines++
+
+		redacted := LineFailedPlaceholder
+		if err == nil {
+			var result *RedactResult
+			if result, err = r.Redact(ctx, line); err == nil {
+				redacted = result.RedactedText
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return lines - 1, ctx.Err()
+			}
+			if opts.OnError != nil {
+				opts.OnError(lines, err)
+			}
+		}
+
+		if newline {
+			redacted += "\n"
+		}
+		if _, err := io.WriteString(out, redacted); err != nil {
+			return lines - 1, err
+		}
+		if eof {
+			return lines, nil
+		}
+	}
+}
+
+// readLine reads the next line without its newline, reporting whether it
+// ended in one. A line longer than maxLine is consumed up to its newline and
+// returned empty with ErrLineTooLong.
+func readLine(reader *bufio.Reader, maxLine int) (string, bool, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		newline := err == nil
+		if newline {
+			chunk = chunk[:len(chunk)-1]
+		}
+		if !tooLong {
+			if len(line)+len(chunk) > maxLine {
+				tooLong = true
+				line = nil
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case tooLong && (newline || err == io.EOF):
+			return "", newline, fmt.Errorf("%w: exceeds %d bytes", ErrLineTooLong, maxLine)
+		default:
+			return string(line), newline, err
+		}
+	}
+}
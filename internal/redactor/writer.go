@@ -0,0 +1,66 @@
+package redactor
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// Writer is an io.WriteCloser that redacts complete lines before passing
+// them to the underlying writer. Partial lines are buffered across writes
+// and flushed on Close.
+type Writer struct {
+	w        io.Writer
+	redactor *Redactor
+	buf      bytes.Buffer
+}
+
+// NewWriter wraps w so that everything written through it is redacted by r
+func NewWriter(w io.Writer, r *Redactor) io.WriteCloser {
+	return &Writer{
+		w:        w,
+		redactor: r,
+	}
+}
+
+// Write buffers p and writes out every complete line in redacted form
+func (w *Writer) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := string(w.buf.Next(idx + 1))
+		if err := w.writeRedacted(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close redacts and writes any buffered partial line. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	line := w.buf.String()
+	w.buf.Reset()
+	return w.writeRedacted(line)
+}
+
+// writeRedacted redacts a single line and writes it to the underlying writer
+func (w *Writer) writeRedacted(line string) error {
+	result, err := w.redactor.Redact(context.Background(), line)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w.w, result.RedactedText)
+	return err
+}
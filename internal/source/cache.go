@@ -1,15 +1,23 @@
 package source
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
 
+// DefaultMaxPatternsPerSource is the number of patterns a source may
+// contribute before the rest are dropped
+const DefaultMaxPatternsPerSource = 1000
+
 // Cache stores fetched rule sets in memory
 type Cache struct {
 	mu       sync.RWMutex
 	sources  map[string]*CachedSource
 	patterns map[string]*CachedPattern
+
+	// maxPatternsPerSource caps the patterns kept per source (0 = unlimited)
+	maxPatternsPerSource int
 }
 
 // CachedSource represents a cached source
@@ -28,6 +36,9 @@ type CachedSource struct {
 
 	// Error is the last error if any
 	Error string
+
+	// Warning explains why patterns of the source were dropped, if any were
+	Warning string
 }
 
 // CachedPattern represents a cached pattern
@@ -48,12 +59,23 @@ type CachedPattern struct {
 // NewCache creates a new cache
 func NewCache() *Cache {
 	return &Cache{
-		sources:  make(map[string]*CachedSource),
-		patterns: make(map[string]*CachedPattern),
+		sources:              make(map[string]*CachedSource),
+		patterns:             make(map[string]*CachedPattern),
+		maxPatternsPerSource: DefaultMaxPatternsPerSource,
 	}
 }
 
-// SetSource stores or updates a cached source
+// SetMaxPatternsPerSource sets how many patterns a source may contribute.
+// Sources set afterwards keep their first limit patterns, in rule set order,
+// and record a warning. Zero or less removes the limit.
+func (c *Cache) SetMaxPatternsPerSource(limit int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxPatternsPerSource = limit
+}
+
+// SetSource stores or updates a cached source. Patterns beyond the per-source
+// limit are dropped and the source's Warning says how many.
 func (c *Cache) SetSource(name string, ruleSets []*RuleSet) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -63,11 +85,29 @@ func (c *Cache) SetSource(name string, ruleSets []*RuleSet) {
 		totalPatterns += len(rs.Patterns)
 	}
 
+	var warning string
+	if c.maxPatternsPerSource > 0 && totalPatterns > c.maxPatternsPerSource {
+		ruleSets = truncateRuleSets(ruleSets, c.maxPatternsPerSource)
+		warning = fmt.Sprintf("source declares %d patterns, only the first %d are used",
+			totalPatterns, c.maxPatternsPerSource)
+		totalPatterns = c.maxPatternsPerSource
+	}
+
+	// Drop patterns of the previous version of the source
+	if previous, exists := c.sources[name]; exists {
+		for _, rs := range previous.RuleSets {
+			for _, p := range rs.Patterns {
+				delete(c.patterns, c.patternKey(name, rs.Name, p.Name))
+			}
+		}
+	}
+
 	c.sources[name] = &CachedSource{
 		Name:          name,
 		RuleSets:      ruleSets,
 		LastSync:      time.Now(),
 		TotalPatterns: totalPatterns,
+		Warning:       warning,
 	}
 
 	// Update pattern cache
@@ -85,6 +125,28 @@ func (c *Cache) SetSource(name string, ruleSets []*RuleSet) {
 	}
 }
 
+// truncateRuleSets returns copies of the rule sets holding only their first
+// limit patterns in total. Rule sets left without patterns are dropped; the
+// given rule sets are not modified.
+func truncateRuleSets(ruleSets []*RuleSet, limit int) []*RuleSet {
+	kept := make([]*RuleSet, 0, len(ruleSets))
+	for _, rs := range ruleSets {
+		if limit == 0 {
+			break
+		}
+		if len(rs.Patterns) <= limit {
+			kept = append(kept, rs)
+			limit -= len(rs.Patterns)
+			continue
+		}
+		truncated := *rs
+		truncated.Patterns = rs.Patterns[:limit:limit]
+		kept = append(kept, &truncated)
+		limit = 0
+	}
+	return kept
+}
+
 // SetSourceError sets an error for a source
 func (c *Cache) SetSourceError(name string, err string) {
 	c.mu.Lock()
@@ -202,3 +202,73 @@ func TestCache_GetRuleSetsForSource(t *testing.T) {
 		t.Error("Expected nil for nonexistent source")
 	}
 }
+
+func TestCache_SetSourcePatternLimit(t *testing.T) {
+	cache := NewCache()
+	cache.SetMaxPatternsPerSource(3)
+
+	ruleSets := []*RuleSet{
+		{
+			Name:     "first",
+			Version:  "1.0.0",
+			Patterns: []PatternDefinition{{Name: "a"}, {Name: "b"}},
+		},
+		{
+			Name:     "second",
+			Version:  "1.0.0",
+			Patterns: []PatternDefinition{{Name: "c"}, {Name: "d"}},
+		},
+		{
+			Name:     "third",
+			Version:  "1.0.0",
+			Patterns: []PatternDefinition{{Name: "e"}},
+		},
+	}
+
+	cache.SetSource("big-source", ruleSets)
+
+	got, exists := cache.GetSource("big-source")
+	if !exists {
+		t.Fatal("Expected source to exist")
+	}
+	if got.TotalPatterns != 3 {
+		t.Errorf("TotalPatterns = %d, want 3", got.TotalPatterns)
+	}
+	if len(got.RuleSets) != 2 {
+		t.Errorf("Expected 2 rule sets, got %d", len(got.RuleSets))
+	}
+	if want := "source declares 5 patterns, only the first 3 are used"; got.Warning != want {
+		t.Errorf("Warning = %q, want %q", got.Warning, want)
+	}
+	if len(ruleSets[1].Patterns) != 2 {
+		t.Errorf("Expected the given rule set to keep 2 patterns, got %d", len(ruleSets[1].Patterns))
+	}
+
+	if _, exists := cache.GetPattern("big-source", "second", "c"); !exists {
+		t.Error("Expected pattern c to be cached")
+	}
+	if _, exists := cache.GetPattern("big-source", "second", "d"); exists {
+		t.Error("Expected pattern d to be dropped")
+	}
+	if _, exists := cache.GetPattern("big-source", "third", "e"); exists {
+		t.Error("Expected pattern e to be dropped")
+	}
+
+	// A source back under the limit clears the warning
+	cache.SetSource("big-source", ruleSets[:1])
+	got, _ = cache.GetSource("big-source")
+	if got.Warning != "" {
+		t.Errorf("Warning = %q, want empty", got.Warning)
+	}
+	if _, exists := cache.GetPattern("big-source", "second", "c"); exists {
+		t.Error("Expected pattern c of the previous version to be removed")
+	}
+
+	// Zero removes the limit
+	cache.SetMaxPatternsPerSource(0)
+	cache.SetSource("big-source", ruleSets)
+	got, _ = cache.GetSource("big-source")
+	if got.TotalPatterns != 5 || got.Warning != "" {
+		t.Errorf("TotalPatterns = %d, Warning = %q, want 5 and empty", got.TotalPatterns, got.Warning)
+	}
+}
@@ -0,0 +1,240 @@
+package source
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrExtractLimit is returned when an archive exceeds the extraction limits
+var ErrExtractLimit = errors.New("archive exceeds extraction limits")
+
+// Default extraction limits
+const (
+	DefaultMaxExtractFileSize  = 10 << 20
+	DefaultMaxExtractTotalSize = 100 << 20
+	DefaultMaxExtractEntries   = 10000
+)
+
+// ExtractLimits bounds what extracting an archive may write, guarding
+// against decompression bombs. Zero fields use the defaults.
+type ExtractLimits struct {
+	// MaxFileSize is the largest size in bytes of a single extracted file
+	MaxFileSize int64
+
+	// MaxTotalSize is the largest combined size in bytes of all extracted files
+	MaxTotalSize int64
+
+	// MaxEntries is the largest number of entries an archive may hold
+	MaxEntries int
+}
+
+// withDefaults returns the limits with zero fields set to the defaults
+func (l ExtractLimits) withDefaults() ExtractLimits {
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = DefaultMaxExtractFileSize
+	}
+	if l.MaxTotalSize <= 0 {
+		l.MaxTotalSize = DefaultMaxExtractTotalSize
+	}
+	if l.MaxEntries <= 0 {
+		l.MaxEntries = DefaultMaxExtractEntries
+	}
+	return l
+}
+
+// extractor writes archive entries below a target directory within limits.
+// It remembers the files and directories it created so that a failed
+// extraction can be undone with cleanup.
+type extractor struct {
+	ctx       context.Context
+	targetDir string
+	limits    ExtractLimits
+	entries   int
+	total     int64
+	created   []string
+}
+
+// newExtractor creates an extractor writing below targetDir
+func newExtractor(ctx context.Context, targetDir string, limits ExtractLimits) *extractor {
+	return &extractor{
+		ctx:       ctx,
+		targetDir: filepath.Clean(targetDir),
+		limits:    limits.withDefaults(),
+	}
+}
+
+// next is called before each entry. It fails once the context is done or
+// the archive has too many entries.
+func (x *extractor) next() error {
+	if err := x.ctx.Err(); err != nil {
+		return err
+	}
+	x.entries++
+	if x.entries > x.limits.MaxEntries {
+		return fmt.Errorf("%w: more than %d entries", ErrExtractLimit, x.limits.MaxEntries)
+	}
+	return nil
+}
+
+// path returns the target path of an entry, or false if it would escape
+// the target directory
+func (x *extractor) path(name string) (string, bool) {
+	targetPath := filepath.Join(x.targetDir, name)
+	return targetPath, strings.HasPrefix(targetPath, x.targetDir+string(os.PathSeparator))
+}
+
+// mkdirAll creates dir and any missing parents, recording those it created
+func (x *extractor) mkdirAll(dir string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := x.mkdirAll(filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := os.Mkdir(dir, 0755); err != nil {
+		if os.IsExist(err) {
+			return nil
+		}
+		return err
+	}
+	x.created = append(x.created, dir)
+	return nil
+}
+
+// writeFile writes the content of an entry of the given declared size to
+// path, enforcing the size limits on what is actually written
+func (x *extractor) writeFile(path string, size int64, r io.Reader) error {
+	allowed := min(x.limits.MaxFileSize, x.limits.MaxTotalSize-x.total)
+	if size > allowed {
+		return fmt.Errorf("%w: %s is larger than %d bytes", ErrExtractLimit, path, allowed)
+	}
+
+	if err := x.mkdirAll(filepath.Dir(path)); err != nil {
+		return err
+	}
+	_, statErr := os.Stat(path)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if os.IsNotExist(statErr) {
+		x.created = append(x.created, path)
+	}
+
+	n, err := io.Copy(file, io.LimitReader(r, allowed+1))
+	closeErr := file.Close()
+	x.total += n
+	if err != nil {
+		return err
+	}
+	if n > allowed {
+		return fmt.Errorf("%w: %s is larger than %d bytes", ErrExtractLimit, path, allowed)
+	}
+	return closeErr
+}
+
+// cleanup removes everything the extractor created, newest first
+func (x *extractor) cleanup() {
+	for i := len(x.created) - 1; i >= 0; i-- {
+		os.Remove(x.created[i])
+	}
+	x.created = nil
+}
+
+// extractTar extracts a tar stream
+func (x *extractor) extractTar(reader io.Reader) error {
+	tarReader := tar.NewReader(reader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := x.next(); err != nil {
+			return err
+		}
+
+		targetPath, ok := x.path(header.Name)
+		if !ok {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := x.mkdirAll(targetPath); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := x.writeFile(targetPath, header.Size, tarReader); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// extractZipStream spools a zip archive, which needs random access, to a
+// temporary file within the total size limit and extracts it
+func (x *extractor) extractZipStream(reader io.Reader) error {
+	tmp, err := os.CreateTemp("", "pii-redactor-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	n, err := io.Copy(tmp, io.LimitReader(reader, x.limits.MaxTotalSize+1))
+	if err != nil {
+		return err
+	}
+	if n > x.limits.MaxTotalSize {
+		return fmt.Errorf("%w: zip archive is larger than %d bytes", ErrExtractLimit, x.limits.MaxTotalSize)
+	}
+
+	zipReader, err := zip.NewReader(tmp, n)
+	if err != nil {
+		return err
+	}
+	return x.extractZip(zipReader)
+}
+
+// extractZip extracts a zip archive
+func (x *extractor) extractZip(reader *zip.Reader) error {
+	for _, file := range reader.File {
+		if err := x.next(); err != nil {
+			return err
+		}
+
+		targetPath, ok := x.path(file.Name)
+		if !ok {
+			continue
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := x.mkdirAll(targetPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = x.writeFile(targetPath, int64(file.UncompressedSize64), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -57,6 +57,9 @@ type PatternDefinition struct {
 	// Category is the pattern category
 	Category string `json:"category,omitempty" yaml:"category,omitempty"`
 
+	// Tags are cross-cutting labels such as compliance regimes (pci, gdpr, hipaa)
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
 	// Patterns contains the regex rules
 	Patterns []PatternRule `json:"patterns" yaml:"patterns"`
 
@@ -72,6 +75,21 @@ type PatternDefinition struct {
 	// Enabled indicates if the pattern is enabled by default
 	Enabled bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
 
+	// MinLength drops matches shorter than this many characters
+	MinLength int `json:"minLength,omitempty" yaml:"minLength,omitempty"`
+
+	// MaxMatchLength bounds the length of a match for streaming detection
+	MaxMatchLength int `json:"maxMatchLength,omitempty" yaml:"maxMatchLength,omitempty"`
+
+	// ContextKeywords require one of the keywords near each match
+	ContextKeywords []string `json:"contextKeywords,omitempty" yaml:"contextKeywords,omitempty"`
+
+	// ContextWindow is how many characters around a match are searched for ContextKeywords
+	ContextWindow int `json:"contextWindow,omitempty" yaml:"contextWindow,omitempty"`
+
+	// SensitiveGroups are capture groups kept out of detection metadata
+	SensitiveGroups []string `json:"sensitiveGroups,omitempty" yaml:"sensitiveGroups,omitempty"`
+
 	// TestCases for validation
 	TestCases *TestCases `json:"testCases,omitempty" yaml:"testCases,omitempty"`
 }
@@ -83,6 +101,10 @@ type PatternRule struct {
 
 	// Confidence is the confidence level (high, medium, low)
 	Confidence string `json:"confidence,omitempty" yaml:"confidence,omitempty"`
+
+	// ContextKeywords and ContextWindow override those of the pattern
+	ContextKeywords []string `json:"contextKeywords,omitempty" yaml:"contextKeywords,omitempty"`
+	ContextWindow   int      `json:"contextWindow,omitempty" yaml:"contextWindow,omitempty"`
 }
 
 // TestCases contains test cases for pattern validation
@@ -121,16 +143,24 @@ func (p *PatternDefinition) ToPatternSpec() patterns.PIIPatternSpec {
 		DisplayName:     p.DisplayName,
 		Description:     p.Description,
 		Category:        p.Category,
+		Tags:            p.Tags,
 		Validator:       p.Validator,
 		MaskingStrategy: p.MaskingStrategy,
 		Severity:        p.Severity,
 		Enabled:         p.Enabled,
+		MinLength:       p.MinLength,
+		MaxMatchLength:  p.MaxMatchLength,
+		ContextKeywords: p.ContextKeywords,
+		ContextWindow:   p.ContextWindow,
+		SensitiveGroups: p.SensitiveGroups,
 	}
 
 	for _, rule := range p.Patterns {
 		spec.Patterns = append(spec.Patterns, patterns.PatternRule{
-			Regex:      rule.Regex,
-			Confidence: rule.Confidence,
+			Regex:           rule.Regex,
+			Confidence:      rule.Confidence,
+			ContextKeywords: rule.ContextKeywords,
+			ContextWindow:   rule.ContextWindow,
 		})
 	}
 
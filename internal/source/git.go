@@ -3,13 +3,10 @@ package source
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 // GitFetcher fetches rules from a Git repository
@@ -185,36 +182,16 @@ func (g *GitFetcher) readRules(rulesPath string) (*RuleSet, error) {
 
 // readPatternFile reads patterns from a YAML file
 func (g *GitFetcher) readPatternFile(path string) ([]PatternDefinition, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	data, err := io.ReadAll(file)
+	patterns, err := ParsePatterns(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse pattern file: %s", path)
 	}
-
-	// Try parsing as a single pattern first
-	var single PatternDefinition
-	if err := yaml.Unmarshal(data, &single); err == nil && single.Name != "" {
-		return []PatternDefinition{single}, nil
-	}
-
-	// Try parsing as pattern list
-	var patterns []PatternDefinition
-	if err := yaml.Unmarshal(data, &patterns); err == nil {
-		return patterns, nil
-	}
-
-	// Try parsing as rule set
-	var ruleSet RuleSet
-	if err := yaml.Unmarshal(data, &ruleSet); err == nil {
-		return ruleSet.Patterns, nil
-	}
-
-	return nil, fmt.Errorf("failed to parse pattern file: %s", path)
+	return patterns, nil
 }
 
 // isYAMLFile checks if a file is a YAML file
@@ -0,0 +1,38 @@
+package source
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// IsGzip reports whether data starts with the gzip magic bytes
+func IsGzip(data []byte) bool {
+	return len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// Gunzip decompresses gzip compressed data, guarding against decompression
+// bombs: output beyond maxSize bytes is not read, and the first maxSize bytes
+// are returned with an error wrapping ErrExtractLimit. A maxSize of zero or
+// less uses DefaultMaxExtractTotalSize.
+func Gunzip(data []byte, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxExtractTotalSize
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress: %w", err)
+	}
+	if int64(len(decompressed)) > maxSize {
+		return decompressed[:maxSize], fmt.Errorf("%w: decompressed data is larger than %d bytes", ErrExtractLimit, maxSize)
+	}
+	return decompressed, nil
+}
@@ -3,14 +3,13 @@ package source
 import (
 	"archive/tar"
 	"archive/zip"
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -19,15 +18,19 @@ import (
 
 // HTTPFetcher fetches rules from an HTTP endpoint
 type HTTPFetcher struct {
-	url        string
-	headers    map[string]string
-	httpClient *http.Client
+	url           string
+	headers       map[string]string
+	httpClient    *http.Client
+	extractLimits ExtractLimits
 }
 
 // HTTPConfig holds configuration for HTTPFetcher
 type HTTPConfig struct {
 	URL     string
 	Headers map[string]string
+
+	// ExtractLimits bounds FetchToDir extraction; zero fields use the defaults
+	ExtractLimits ExtractLimits
 }
 
 // NewHTTPFetcher creates a new HTTP fetcher
@@ -42,6 +45,7 @@ func NewHTTPFetcher(config HTTPConfig) *HTTPFetcher {
 		httpClient: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
+		extractLimits: config.ExtractLimits,
 	}
 }
 
@@ -115,7 +119,7 @@ func (h *HTTPFetcher) processContent(data []byte, contentType string) (*RuleSet,
 	}
 
 	// Try to detect format by content
-	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+	if IsGzip(data) {
 		return h.processGzip(data)
 	}
 
@@ -164,15 +168,9 @@ func (h *HTTPFetcher) processYAML(data []byte) (*RuleSet, error) {
 
 // processGzip processes gzip compressed content
 func (h *HTTPFetcher) processGzip(data []byte) (*RuleSet, error) {
-	reader, err := gzip.NewReader(bytes.NewReader(data))
+	decompressed, err := Gunzip(data, h.extractLimits.withDefaults().MaxTotalSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
-	}
-	defer reader.Close()
-
-	decompressed, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decompress: %w", err)
+		return nil, err
 	}
 
 	// Try as tar
@@ -269,22 +267,7 @@ func (h *HTTPFetcher) processZip(data []byte) (*RuleSet, error) {
 
 // parsePatternContent parses pattern content from bytes
 func (h *HTTPFetcher) parsePatternContent(data []byte) ([]PatternDefinition, error) {
-	var single PatternDefinition
-	if err := yaml.Unmarshal(data, &single); err == nil && single.Name != "" {
-		return []PatternDefinition{single}, nil
-	}
-
-	var patterns []PatternDefinition
-	if err := yaml.Unmarshal(data, &patterns); err == nil {
-		return patterns, nil
-	}
-
-	var ruleSet RuleSet
-	if err := yaml.Unmarshal(data, &ruleSet); err == nil {
-		return ruleSet.Patterns, nil
-	}
-
-	return nil, fmt.Errorf("failed to parse pattern content")
+	return ParsePatterns(data)
 }
 
 // SetHTTPClient sets a custom HTTP client
@@ -297,7 +280,10 @@ func (h *HTTPFetcher) AddHeader(key, value string) {
 	h.headers[key] = value
 }
 
-// FetchToDir fetches and extracts content to a directory (for archive types)
+// FetchToDir fetches an archive and extracts it to a directory, streaming the
+// response into the extractor within the fetcher's extraction limits.
+// Cancelling ctx aborts the extraction; whatever a failed extraction created
+// is removed again.
 func (h *HTTPFetcher) FetchToDir(ctx context.Context, targetDir string) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
 	if err != nil {
@@ -318,119 +304,33 @@ func (h *HTTPFetcher) FetchToDir(ctx context.Context, targetDir string) error {
 		return fmt.Errorf("HTTP request failed: status %d", resp.StatusCode)
 	}
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
+	x := newExtractor(ctx, targetDir, h.extractLimits)
+	if err := extractToDir(x, resp.Body); err != nil {
+		x.cleanup()
 		return err
 	}
-
-	return h.extractToDir(data, targetDir)
-}
-
-// extractToDir extracts archive content to a directory
-func (h *HTTPFetcher) extractToDir(data []byte, targetDir string) error {
-	// Try gzip+tar
-	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
-		reader, err := gzip.NewReader(bytes.NewReader(data))
-		if err != nil {
-			return err
-		}
-		defer reader.Close()
-		return h.extractTarToDir(reader, targetDir)
-	}
-
-	// Try zip
-	if len(data) > 4 && data[0] == 0x50 && data[1] == 0x4b {
-		return h.extractZipToDir(data, targetDir)
-	}
-
-	// Try tar
-	return h.extractTarToDir(bytes.NewReader(data), targetDir)
-}
-
-// extractTarToDir extracts a tar archive to a directory
-func (h *HTTPFetcher) extractTarToDir(reader io.Reader, targetDir string) error {
-	tarReader := tar.NewReader(reader)
-
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
-		}
-
-		targetPath := filepath.Join(targetDir, header.Name)
-
-		if !strings.HasPrefix(targetPath, filepath.Clean(targetDir)+string(os.PathSeparator)) {
-			continue
-		}
-
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(targetPath, 0755); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-				return err
-			}
-			file, err := os.Create(targetPath)
-			if err != nil {
-				return err
-			}
-			if _, err := io.Copy(file, tarReader); err != nil {
-				file.Close()
-				return err
-			}
-			file.Close()
-		}
-	}
-
 	return nil
 }
 
-// extractZipToDir extracts a zip archive to a directory
-func (h *HTTPFetcher) extractZipToDir(data []byte, targetDir string) error {
-	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
-	if err != nil {
-		return err
-	}
-
-	for _, file := range reader.File {
-		targetPath := filepath.Join(targetDir, file.Name)
-
-		if !strings.HasPrefix(targetPath, filepath.Clean(targetDir)+string(os.PathSeparator)) {
-			continue
-		}
-
-		if file.FileInfo().IsDir() {
-			os.MkdirAll(targetPath, 0755)
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-			return err
-		}
-
-		rc, err := file.Open()
-		if err != nil {
-			return err
-		}
-
-		outFile, err := os.Create(targetPath)
-		if err != nil {
-			rc.Close()
-			return err
-		}
+// extractToDir streams gzip-compressed tar, zip or tar content into the
+// extractor, detecting the format by its magic bytes
+func extractToDir(x *extractor, body io.Reader) error {
+	reader := bufio.NewReader(body)
+	// Peek no further than the magic bytes, so a slow stream is extracted as
+	// it arrives. A short read matches no magic and is left to the tar reader.
+	header, _ := reader.Peek(4)
 
-		_, err = io.Copy(outFile, rc)
-		outFile.Close()
-		rc.Close()
+	switch {
+	case IsGzip(header):
+		gzReader, err := gzip.NewReader(reader)
 		if err != nil {
 			return err
 		}
+		defer gzReader.Close()
+		return x.extractTar(gzReader)
+	case len(header) == 4 && header[0] == 0x50 && header[1] == 0x4b:
+		return x.extractZipStream(reader)
+	default:
+		return x.extractTar(reader)
 	}
-
-	return nil
 }
@@ -0,0 +1,209 @@
+package source
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// archiveFile is a file in a test archive
+type archiveFile struct {
+	name    string
+	content string
+}
+
+// writeTar writes the files as tar entries to w
+func writeTar(t *testing.T, w *tar.Writer, files ...archiveFile) {
+	t.Helper()
+	for _, f := range files {
+		header := &tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content)), Typeflag: tar.TypeReg}
+		if err := w.WriteHeader(header); err != nil {
+			t.Fatalf("write tar header: %v", err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			t.Fatalf("write tar content: %v", err)
+		}
+	}
+}
+
+func buildTarGz(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	writeTar(t, tw, files...)
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func buildZip(t *testing.T, files ...archiveFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatalf("create zip entry: %v", err)
+		}
+		w.Write([]byte(f.content))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+// dirEntries returns the paths below dir, relative to it
+func dirEntries(t *testing.T, dir string) []string {
+	t.Helper()
+	var paths []string
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && path != dir {
+			rel, _ := filepath.Rel(dir, path)
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	return paths
+}
+
+func TestHTTPFetcher_FetchToDir(t *testing.T) {
+	files := []archiveFile{
+		{name: "rules/email.yaml", content: "name: email\n"},
+		{name: "rules/phone.yaml", content: "name: phone\n"},
+		{name: "../escape.yaml", content: "name: escape\n"},
+	}
+
+	tests := []struct {
+		name    string
+		archive []byte
+		limits  ExtractLimits
+		wantErr error
+	}{
+		{name: "tar.gz", archive: buildTarGz(t, files...)},
+		{name: "zip", archive: buildZip(t, files...)},
+		{
+			name:    "file above size limit",
+			archive: buildTarGz(t, files[0], archiveFile{name: "rules/big.yaml", content: strings.Repeat("x", 64)}),
+			limits:  ExtractLimits{MaxFileSize: 32},
+			wantErr: ErrExtractLimit,
+		},
+		{
+			name:    "zip above total limit",
+			archive: buildZip(t, files[0], archiveFile{name: "rules/big.yaml", content: strings.Repeat("x", 64)}),
+			limits:  ExtractLimits{MaxTotalSize: 48},
+			wantErr: ErrExtractLimit,
+		},
+		{
+			name:    "too many entries",
+			archive: buildTarGz(t, files...),
+			limits:  ExtractLimits{MaxEntries: 1},
+			wantErr: ErrExtractLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(tt.archive)
+			}))
+			defer server.Close()
+
+			root := t.TempDir()
+			targetDir := filepath.Join(root, "bundle")
+			fetcher := NewHTTPFetcher(HTTPConfig{URL: server.URL, ExtractLimits: tt.limits})
+			err := fetcher.FetchToDir(context.Background(), targetDir)
+
+			if _, statErr := os.Stat(filepath.Join(root, "escape.yaml")); statErr == nil {
+				t.Error("entry escaping the target directory was extracted")
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FetchToDir() error = %v, want %v", err, tt.wantErr)
+				}
+				if entries := dirEntries(t, root); len(entries) != 0 {
+					t.Errorf("failed extraction left %v behind", entries)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("FetchToDir() error = %v", err)
+			}
+			content, err := os.ReadFile(filepath.Join(targetDir, "rules", "phone.yaml"))
+			if err != nil || string(content) != "name: phone\n" {
+				t.Errorf("rules/phone.yaml = %q, %v", content, err)
+			}
+		})
+	}
+}
+
+func TestHTTPFetcher_FetchToDir_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		writeTar(t, tw, archiveFile{name: "rules/email.yaml", content: "name: email\n"})
+		tw.Flush()
+		gz.Flush()
+		w.(http.Flusher).Flush()
+
+		// Hold the rest of the archive back until the client gives up
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	preexisting := t.TempDir()
+	if err := os.WriteFile(filepath.Join(preexisting, "keep.yaml"), []byte("name: keep\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- NewHTTPFetcher(HTTPConfig{URL: server.URL}).FetchToDir(ctx, preexisting)
+	}()
+
+	// Cancel once the first entry has been extracted
+	extracted := filepath.Join(preexisting, "rules", "email.yaml")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(extracted); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first entry was never extracted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("FetchToDir() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("FetchToDir() did not return after cancellation")
+	}
+
+	if entries := dirEntries(t, preexisting); len(entries) != 1 || entries[0] != "keep.yaml" {
+		t.Errorf("directory after cancelled extraction = %v, want only keep.yaml", entries)
+	}
+}
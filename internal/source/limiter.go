@@ -0,0 +1,66 @@
+package source
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrFetchLimitReached is returned when the concurrent fetch limit is reached
+var ErrFetchLimitReached = errors.New("concurrent fetch limit reached")
+
+// FetchLimiter limits the number of fetches running at once across all
+// sources. A nil limiter imposes no limit.
+type FetchLimiter struct {
+	slots chan struct{}
+}
+
+// NewFetchLimiter creates a limiter allowing up to maxConcurrent fetches at
+// once. A value of 0 or less disables the limit.
+func NewFetchLimiter(maxConcurrent int) *FetchLimiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &FetchLimiter{slots: make(chan struct{}, maxConcurrent)}
+}
+
+// TryAcquire reserves a fetch slot without blocking and reports whether one
+// was available. Every successful call must be paired with Release.
+func (l *FetchLimiter) TryAcquire() bool {
+	if l == nil {
+		return true
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot reserved by TryAcquire
+func (l *FetchLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// InFlight returns the number of fetches currently holding a slot
+func (l *FetchLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// Fetch runs the fetcher if a slot is available, or returns
+// ErrFetchLimitReached without fetching
+func (l *FetchLimiter) Fetch(ctx context.Context, fetcher Fetcher) (*RuleSet, error) {
+	if !l.TryAcquire() {
+		return nil, ErrFetchLimitReached
+	}
+	defer l.Release()
+
+	return fetcher.Fetch(ctx)
+}
@@ -0,0 +1,106 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// trackingFetcher records the peak number of concurrent Fetch calls
+type trackingFetcher struct {
+	inFlight *atomic.Int32
+	peak     *atomic.Int32
+}
+
+func (f *trackingFetcher) Fetch(ctx context.Context) (*RuleSet, error) {
+	n := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+
+	for {
+		peak := f.peak.Load()
+		if n <= peak || f.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	return &RuleSet{Name: "test"}, nil
+}
+
+func (f *trackingFetcher) Type() string    { return "test" }
+func (f *trackingFetcher) Validate() error { return nil }
+
+func TestFetchLimiter_LimitsConcurrentFetches(t *testing.T) {
+	const (
+		maxConcurrent = 3
+		sources       = 25
+	)
+
+	limiter := NewFetchLimiter(maxConcurrent)
+	var inFlight, peak, rejected atomic.Int32
+	fetcher := &trackingFetcher{inFlight: &inFlight, peak: &peak}
+
+	var wg sync.WaitGroup
+	for i := 0; i < sources; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Retry as a requeued reconcile would
+			for {
+				_, err := limiter.Fetch(context.Background(), fetcher)
+				if errors.Is(err, ErrFetchLimitReached) {
+					rejected.Add(1)
+					time.Sleep(time.Millisecond)
+					continue
+				}
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got > maxConcurrent {
+		t.Errorf("peak concurrent fetches = %d, want <= %d", got, maxConcurrent)
+	}
+	if rejected.Load() == 0 {
+		t.Error("expected some fetches to be rejected at the limit")
+	}
+	if limiter.InFlight() != 0 {
+		t.Errorf("InFlight() = %d after all fetches finished, want 0", limiter.InFlight())
+	}
+}
+
+func TestFetchLimiter_Unlimited(t *testing.T) {
+	limiter := NewFetchLimiter(0)
+	if limiter != nil {
+		t.Fatal("expected nil limiter for a non-positive limit")
+	}
+
+	for i := 0; i < 100; i++ {
+		if !limiter.TryAcquire() {
+			t.Fatal("nil limiter should never reject")
+		}
+	}
+	limiter.Release()
+}
+
+func TestFetchLimiter_TryAcquireRelease(t *testing.T) {
+	limiter := NewFetchLimiter(1)
+
+	if !limiter.TryAcquire() {
+		t.Fatal("expected first acquire to succeed")
+	}
+	if limiter.TryAcquire() {
+		t.Fatal("expected second acquire to fail at the limit")
+	}
+	limiter.Release()
+	if !limiter.TryAcquire() {
+		t.Fatal("expected acquire to succeed after release")
+	}
+}
@@ -0,0 +1,93 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParsePatterns parses pattern definitions from YAML holding a single
+// pattern, a list of patterns or a rule set
+func ParsePatterns(data []byte) ([]PatternDefinition, error) {
+	var single PatternDefinition
+	if err := yaml.Unmarshal(data, &single); err == nil && isSinglePattern(&single) {
+		return []PatternDefinition{single}, nil
+	}
+
+	var patterns []PatternDefinition
+	if err := yaml.Unmarshal(data, &patterns); err == nil {
+		return patterns, nil
+	}
+
+	var ruleSet RuleSet
+	if err := yaml.Unmarshal(data, &ruleSet); err == nil {
+		return ruleSet.Patterns, nil
+	}
+
+	return nil, fmt.Errorf("failed to parse pattern content")
+}
+
+// isSinglePattern reports whether a document parsed as one pattern really is
+// one. A named rule set also parses as a pattern, but its pattern entries
+// carry no regex.
+func isSinglePattern(p *PatternDefinition) bool {
+	if p.Name == "" || len(p.Patterns) == 0 {
+		return false
+	}
+	for _, rule := range p.Patterns {
+		if rule.Regex == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadLocalRules reads the pattern definitions of a YAML rule file, or of
+// every YAML file below a directory, into one rule set per file. A file that
+// cannot be read or holds no patterns is recorded in the result's errors
+// without stopping the others from loading.
+func LoadLocalRules(path string) (*FetchResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isYAMLFile(p) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := NewFetchResult()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			result.AddError(err.Error())
+			continue
+		}
+		patterns, err := ParsePatterns(data)
+		if err != nil {
+			result.AddError(fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if len(patterns) == 0 {
+			result.AddError(fmt.Sprintf("%s: no pattern definitions found", file))
+			continue
+		}
+		result.AddRuleSet(&RuleSet{Name: file, Patterns: patterns})
+	}
+	return result, nil
+}
@@ -1,21 +1,55 @@
 package source
 
 import (
-	"archive/tar"
-	"compress/gzip"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+)
+
+// DefaultMaxBlobSize is the default largest size in bytes of a downloaded layer blob
+const DefaultMaxBlobSize = 100 << 20
+
+// ErrBlobSizeMismatch is returned when a downloaded blob's size differs from
+// the size its manifest declares
+var ErrBlobSizeMismatch = errors.New("blob size does not match the manifest")
+
+// ErrDigestMismatch is returned when a downloaded blob's content does not
+// match its digest
+var ErrDigestMismatch = errors.New("blob content does not match its digest")
 
-	"gopkg.in/yaml.v3"
+// ErrNoReferrer is returned when no artifact of the configured type refers
+// to the subject
+var ErrNoReferrer = errors.New("no artifact of the requested type refers to the subject")
+
+// DefaultRulesArtifactType is the artifact type of rule bundles attached to
+// a subject when OCIConfig.ArtifactType is empty
+const DefaultRulesArtifactType = "application/vnd.pii-redactor.rules.v1+tar"
+
+// OCI media types of the manifests the fetcher reads
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociIndexMediaType    = "application/vnd.oci.image.index.v1+json"
 )
 
+// maxManifestSize is the largest manifest or index read, the size the OCI
+// distribution spec asks registries to accept
+const maxManifestSize = 4 << 20
+
+// ociCreatedAnnotation records when an artifact was created, and picks the
+// newest of several matching referrers
+const ociCreatedAnnotation = "org.opencontainers.image.created"
+
 // OCIFetcher fetches rules from an OCI registry
 type OCIFetcher struct {
 	registry   string
@@ -23,7 +57,14 @@ type OCIFetcher struct {
 	tag        string
 	username   string
 	password   string
+	path       string
 	httpClient *http.Client
+
+	subject      string
+	artifactType string
+
+	maxBlobSize   int64
+	extractLimits ExtractLimits
 }
 
 // OCIConfig holds configuration for OCIFetcher
@@ -33,6 +74,27 @@ type OCIConfig struct {
 	Tag        string
 	Username   string
 	Password   string
+
+	// Path is the file or directory within the artifact to read rules from,
+	// like GitConfig.Path; empty reads the whole artifact
+	Path string
+
+	// Subject is the digest ("sha256:<hex>") of a manifest, such as a base
+	// image, that the rules artifact is attached to. When set, the artifact
+	// is discovered through the registry's referrers API instead of Tag.
+	Subject string
+
+	// ArtifactType is the artifact type of the rules artifact referring to
+	// Subject; empty uses DefaultRulesArtifactType
+	ArtifactType string
+
+	// MaxBlobSize is the largest size in bytes of a layer blob; zero uses
+	// DefaultMaxBlobSize
+	MaxBlobSize int64
+
+	// ExtractLimits bounds what extracting the layers may write, across all
+	// layers; zero fields use the defaults
+	ExtractLimits ExtractLimits
 }
 
 // NewOCIFetcher creates a new OCI fetcher
@@ -40,6 +102,12 @@ func NewOCIFetcher(config OCIConfig) *OCIFetcher {
 	if config.Tag == "" {
 		config.Tag = "latest"
 	}
+	if config.MaxBlobSize <= 0 {
+		config.MaxBlobSize = DefaultMaxBlobSize
+	}
+	if config.ArtifactType == "" {
+		config.ArtifactType = DefaultRulesArtifactType
+	}
 
 	return &OCIFetcher{
 		registry:   config.Registry,
@@ -47,9 +115,14 @@ func NewOCIFetcher(config OCIConfig) *OCIFetcher {
 		tag:        config.Tag,
 		username:   config.Username,
 		password:   config.Password,
+		path:       config.Path,
+		subject:    config.Subject,
 		httpClient: &http.Client{
 			Timeout: 5 * time.Minute,
 		},
+		artifactType:  config.ArtifactType,
+		maxBlobSize:   config.MaxBlobSize,
+		extractLimits: config.ExtractLimits,
 	}
 }
 
@@ -66,13 +139,29 @@ func (o *OCIFetcher) Validate() error {
 	if o.repository == "" {
 		return fmt.Errorf("OCI repository is required")
 	}
+	if o.path != "" && !filepath.IsLocal(o.path) {
+		return fmt.Errorf("OCI path %q must be relative to the artifact root", o.path)
+	}
+	if o.subject != "" {
+		if _, err := parseDigest(o.subject); err != nil {
+			return fmt.Errorf("OCI subject: %w", err)
+		}
+	}
 	return nil
 }
 
 // Fetch fetches rules from the OCI registry
 func (o *OCIFetcher) Fetch(ctx context.Context) (*RuleSet, error) {
-	// Get manifest
-	manifest, err := o.getManifest(ctx)
+	// Get the manifest, tagged or attached to the subject
+	reference := o.tag
+	if o.subject != "" {
+		artifact, err := o.findReferrer(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find rules artifact for %s: %w", o.subject, err)
+		}
+		reference = artifact.Digest
+	}
+	manifest, err := o.getManifest(ctx, reference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifest: %w", err)
 	}
@@ -84,14 +173,17 @@ func (o *OCIFetcher) Fetch(ctx context.Context) (*RuleSet, error) {
 	}
 	defer os.RemoveAll(tmpDir)
 
+	// One extractor for all layers, so the extraction limits apply to the
+	// artifact as a whole
+	x := newExtractor(ctx, tmpDir, o.extractLimits)
 	for _, layer := range manifest.Layers {
-		if err := o.downloadLayer(ctx, layer.Digest, tmpDir); err != nil {
-			return nil, fmt.Errorf("failed to download layer: %w", err)
+		if err := o.downloadLayer(ctx, layer, x); err != nil {
+			return nil, fmt.Errorf("failed to download layer %s: %w", layer.Digest, err)
 		}
 	}
 
-	// Read rules from extracted content
-	ruleSet, err := o.readRules(tmpDir)
+	// Read rules from the path within the extracted content
+	ruleSet, err := o.readRules(filepath.Join(tmpDir, o.path), reference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read rules: %w", err)
 	}
@@ -103,8 +195,10 @@ func (o *OCIFetcher) Fetch(ctx context.Context) (*RuleSet, error) {
 type ociManifest struct {
 	SchemaVersion int         `json:"schemaVersion"`
 	MediaType     string      `json:"mediaType"`
+	ArtifactType  string      `json:"artifactType,omitempty"`
 	Config        ociLayer    `json:"config"`
 	Layers        []ociLayer  `json:"layers"`
+	Subject       *ociLayer   `json:"subject,omitempty"`
 	Annotations   interface{} `json:"annotations,omitempty"`
 }
 
@@ -115,16 +209,94 @@ type ociLayer struct {
 	Size      int64  `json:"size"`
 }
 
-// getManifest retrieves the OCI manifest
-func (o *OCIFetcher) getManifest(ctx context.Context) (*ociManifest, error) {
-	url := fmt.Sprintf("https://%s/v2/%s/manifests/%s", o.registry, o.repository, o.tag)
+// ociIndex is an OCI image index, the form of a referrers response
+type ociIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+// ociDescriptor describes a manifest listed in an index
+type ociDescriptor struct {
+	MediaType    string            `json:"mediaType"`
+	Digest       string            `json:"digest"`
+	Size         int64             `json:"size"`
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+}
+
+// getManifest retrieves the OCI manifest of a tag or digest. A manifest
+// requested by digest must match it.
+func (o *OCIFetcher) getManifest(ctx context.Context, reference string) (*ociManifest, error) {
+	body, err := o.getJSON(ctx, "manifests/"+reference, ociManifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyReference(reference, body); err != nil {
+		return nil, err
+	}
+
+	var manifest ociManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// findReferrer returns the newest manifest of the configured artifact type
+// referring to the subject. Registries without the referrers API are asked
+// for the fallback index tagged after the subject's digest
+// ("sha256-<hex>"), which tools like ORAS maintain for them.
+func (o *OCIFetcher) findReferrer(ctx context.Context) (*ociDescriptor, error) {
+	body, err := o.getJSON(ctx, "referrers/"+o.subject+"?artifactType="+url.QueryEscape(o.artifactType), ociIndexMediaType)
+	if errors.Is(err, errNotFound) {
+		body, err = o.getJSON(ctx, "manifests/"+strings.Replace(o.subject, ":", "-", 1), ociIndexMediaType)
+		if errors.Is(err, errNotFound) {
+			return nil, ErrNoReferrer
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var index ociIndex
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, err
+	}
+
+	// Registries may ignore the artifactType filter, and the fallback index
+	// lists every referrer
+	var newest *ociDescriptor
+	for i := range index.Manifests {
+		m := &index.Manifests[i]
+		if m.ArtifactType != o.artifactType {
+			continue
+		}
+		if newest == nil || m.Annotations[ociCreatedAnnotation] > newest.Annotations[ociCreatedAnnotation] {
+			newest = m
+		}
+	}
+	if newest == nil {
+		return nil, ErrNoReferrer
+	}
+	return newest, nil
+}
+
+// errNotFound is returned by getJSON for a 404 response
+var errNotFound = errors.New("not found")
+
+// getJSON retrieves a JSON document from the repository's API path,
+// accepting the given media type
+func (o *OCIFetcher) getJSON(ctx context.Context, path, mediaType string) ([]byte, error) {
+	url := fmt.Sprintf("https://%s/v2/%s/%s", o.registry, o.repository, path)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/vnd.oci.image.manifest.v1+json")
+	req.Header.Set("Accept", mediaType)
 	o.setAuth(req)
 
 	resp, err := o.httpClient.Do(req)
@@ -133,21 +305,54 @@ func (o *OCIFetcher) getManifest(ctx context.Context) (*ociManifest, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get manifest: status %d", resp.StatusCode)
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: status %d", errNotFound, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
 	}
 
-	var manifest ociManifest
-	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
 		return nil, err
 	}
+	if len(body) > maxManifestSize {
+		return nil, fmt.Errorf("%w: document is larger than %d bytes", ErrExtractLimit, maxManifestSize)
+	}
+	return body, nil
+}
 
-	return &manifest, nil
+// verifyReference checks that content requested by a digest reference
+// matches the digest. Tags are not checked.
+func verifyReference(reference string, content []byte) error {
+	if !strings.HasPrefix(reference, "sha256:") {
+		return nil
+	}
+	expected, err := parseDigest(reference)
+	if err != nil {
+		return err
+	}
+	if actual := sha256.Sum256(content); !bytes.Equal(actual[:], expected) {
+		return fmt.Errorf("%w: manifest has digest sha256:%x", ErrDigestMismatch, actual)
+	}
+	return nil
 }
 
-// downloadLayer downloads and extracts a layer
-func (o *OCIFetcher) downloadLayer(ctx context.Context, digest string, targetDir string) error {
-	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", o.registry, o.repository, digest)
+// downloadLayer downloads a layer blob to a temporary file and extracts it
+// once it is verified. The blob may not exceed the size limit, its size must
+// match the size the manifest declares for it and its content must match
+// the digest.
+func (o *OCIFetcher) downloadLayer(ctx context.Context, layer ociLayer, x *extractor) error {
+	if layer.Size > o.maxBlobSize {
+		return fmt.Errorf("%w: manifest declares %d bytes, limit is %d", ErrExtractLimit, layer.Size, o.maxBlobSize)
+	}
+	expected, err := parseDigest(layer.Digest)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://%s/v2/%s/blobs/%s", o.registry, o.repository, layer.Digest)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -166,70 +371,88 @@ func (o *OCIFetcher) downloadLayer(ctx context.Context, digest string, targetDir
 		return fmt.Errorf("failed to download layer: status %d", resp.StatusCode)
 	}
 
-	// Extract tar.gz
-	gzReader, err := gzip.NewReader(resp.Body)
-	if err != nil {
-		// Try as plain tar
-		return o.extractTar(resp.Body, targetDir)
-	}
-	defer gzReader.Close()
-
-	return o.extractTar(gzReader, targetDir)
-}
-
-// extractTar extracts a tar archive
-func (o *OCIFetcher) extractTar(reader io.Reader, targetDir string) error {
-	tarReader := tar.NewReader(reader)
-
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return err
+	limit := o.maxBlobSize
+	if layer.Size > 0 {
+		limit = layer.Size
+		if resp.ContentLength >= 0 && resp.ContentLength != layer.Size {
+			return fmt.Errorf("%w: registry sent %d bytes, manifest declares %d", ErrBlobSizeMismatch, resp.ContentLength, layer.Size)
 		}
+	}
 
-		targetPath := filepath.Join(targetDir, header.Name)
+	blob, err := os.CreateTemp("", "pii-rules-blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(blob.Name())
+	defer blob.Close()
+
+	// Read one byte past the limit to tell a blob at the limit from a larger one
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(blob, hash), io.LimitReader(resp.Body, limit+1))
+	switch {
+	case n > limit && layer.Size > 0:
+		return fmt.Errorf("%w: downloaded more than the %d bytes the manifest declares", ErrBlobSizeMismatch, layer.Size)
+	case n > limit:
+		return fmt.Errorf("%w: blob is larger than %d bytes", ErrExtractLimit, limit)
+	case err != nil:
+		return err
+	case layer.Size > 0 && n != layer.Size:
+		return fmt.Errorf("%w: downloaded %d bytes, manifest declares %d", ErrBlobSizeMismatch, n, layer.Size)
+	}
 
-		// Security: prevent path traversal
-		if !strings.HasPrefix(targetPath, filepath.Clean(targetDir)+string(os.PathSeparator)) {
-			continue
-		}
+	if actual := hash.Sum(nil); !bytes.Equal(actual, expected) {
+		return fmt.Errorf("%w: downloaded blob has digest sha256:%x", ErrDigestMismatch, actual)
+	}
 
-		switch header.Typeflag {
-		case tar.TypeDir:
-			if err := os.MkdirAll(targetPath, 0755); err != nil {
-				return err
-			}
-		case tar.TypeReg:
-			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-				return err
-			}
-			file, err := os.Create(targetPath)
-			if err != nil {
-				return err
-			}
-			if _, err := io.Copy(file, tarReader); err != nil {
-				file.Close()
-				return err
-			}
-			file.Close()
-		}
+	if _, err := blob.Seek(0, io.SeekStart); err != nil {
+		return err
 	}
+	return extractToDir(x, blob)
+}
 
-	return nil
+// parseDigest returns the hash of a "sha256:<hex>" digest
+func parseDigest(digest string) ([]byte, error) {
+	algorithm, encoded, ok := strings.Cut(digest, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	if algorithm != "sha256" {
+		return nil, fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	sum, err := hex.DecodeString(encoded)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("invalid sha256 digest %q", digest)
+	}
+	return sum, nil
 }
 
-// readRules reads rules from extracted content
-func (o *OCIFetcher) readRules(rulesPath string) (*RuleSet, error) {
+// readRules reads rules from the extracted file or directory at rulesPath,
+// versioned by the tag or digest the artifact was fetched by. A path the
+// artifact does not contain yields an empty rule set.
+func (o *OCIFetcher) readRules(rulesPath, version string) (*RuleSet, error) {
 	ruleSet := &RuleSet{
 		Name:     o.repository,
-		Version:  o.tag,
+		Version:  version,
 		Patterns: make([]PatternDefinition, 0),
 	}
 
-	err := filepath.Walk(rulesPath, func(path string, info os.FileInfo, err error) error {
+	info, err := os.Stat(rulesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ruleSet, nil
+		}
+		return nil, err
+	}
+	if !info.IsDir() {
+		patterns, err := o.readPatternFile(rulesPath)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet.Patterns = patterns
+		return ruleSet, nil
+	}
+
+	err = filepath.Walk(rulesPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -257,33 +480,16 @@ func (o *OCIFetcher) readRules(rulesPath string) (*RuleSet, error) {
 
 // readPatternFile reads patterns from a YAML file
 func (o *OCIFetcher) readPatternFile(path string) ([]PatternDefinition, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
 
-	data, err := io.ReadAll(file)
+	patterns, err := ParsePatterns(data)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse pattern file: %s", path)
 	}
-
-	var single PatternDefinition
-	if err := yaml.Unmarshal(data, &single); err == nil && single.Name != "" {
-		return []PatternDefinition{single}, nil
-	}
-
-	var patterns []PatternDefinition
-	if err := yaml.Unmarshal(data, &patterns); err == nil {
-		return patterns, nil
-	}
-
-	var ruleSet RuleSet
-	if err := yaml.Unmarshal(data, &ruleSet); err == nil {
-		return ruleSet.Patterns, nil
-	}
-
-	return nil, fmt.Errorf("failed to parse pattern file: %s", path)
+	return patterns, nil
 }
 
 // setAuth sets authentication headers
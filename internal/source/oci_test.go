@@ -0,0 +1,408 @@
+package source
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// digestOf returns the sha256 digest of a blob
+func digestOf(blob []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(blob))
+}
+
+// newOCITestServer serves a manifest with a single layer, whose blob is
+// written by serveBlob
+func newOCITestServer(t *testing.T, layer ociLayer, serveBlob http.HandlerFunc) (*httptest.Server, *bool) {
+	t.Helper()
+	blobRequested := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/rules/manifests/latest", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ociManifest{SchemaVersion: 2, Layers: []ociLayer{layer}})
+	})
+	mux.HandleFunc("/v2/rules/blobs/"+layer.Digest, func(w http.ResponseWriter, r *http.Request) {
+		blobRequested = true
+		serveBlob(w, r)
+	})
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+	return server, &blobRequested
+}
+
+// newTestOCIFetcher creates a fetcher for the test server
+func newTestOCIFetcher(server *httptest.Server, config OCIConfig) *OCIFetcher {
+	config.Registry = strings.TrimPrefix(server.URL, "https://")
+	config.Repository = "rules"
+	fetcher := NewOCIFetcher(config)
+	fetcher.SetHTTPClient(server.Client())
+	return fetcher
+}
+
+// streamBlob writes the blob in flushed chunks, so no Content-Length is sent
+func streamBlob(blob []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for rest := blob; len(rest) > 0; {
+			n := min(len(rest), 16)
+			w.Write(rest[:n])
+			w.(http.Flusher).Flush()
+			rest = rest[n:]
+		}
+	}
+}
+
+func TestOCIFetcher_Fetch(t *testing.T) {
+	layer := buildTarGz(t, archiveFile{name: "rules/employee.yaml", content: "name: employee-id\npatterns:\n  - regex: 'EMP-\\d{6}'\n"})
+	big := buildTarGz(t, archiveFile{name: "rules/big.yaml", content: strings.Repeat("# padding that does not compress well 0123456789\n", 64)})
+
+	corrupted := append([]byte{}, layer...)
+	corrupted[len(corrupted)/2] ^= 0xff
+
+	tests := []struct {
+		name          string
+		declaredSize  int64
+		digest        string
+		blob          http.HandlerFunc
+		config        OCIConfig
+		wantErr       error
+		wantNoRequest bool
+	}{
+		{
+			name:         "layer within limits",
+			declaredSize: int64(len(layer)),
+			blob:         streamBlob(layer),
+		},
+		{
+			name:         "corrupted blob",
+			declaredSize: int64(len(layer)),
+			digest:       digestOf(layer),
+			blob:         streamBlob(corrupted),
+			wantErr:      ErrDigestMismatch,
+		},
+		{
+			name:          "manifest declares oversized layer",
+			declaredSize:  int64(len(big)),
+			blob:          streamBlob(big),
+			config:        OCIConfig{MaxBlobSize: 64},
+			wantErr:       ErrExtractLimit,
+			wantNoRequest: true,
+		},
+		{
+			name:         "blob larger than the manifest declares",
+			declaredSize: int64(len(layer)),
+			blob:         streamBlob(append(append([]byte{}, layer...), make([]byte, 1024)...)),
+			wantErr:      ErrBlobSizeMismatch,
+		},
+		{
+			name:         "blob smaller than the manifest declares",
+			declaredSize: int64(len(layer)) + 512,
+			blob:         streamBlob(layer),
+			wantErr:      ErrBlobSizeMismatch,
+		},
+		{
+			name:         "content length differs from the manifest",
+			declaredSize: int64(len(layer)) + 1,
+			blob: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(layer)
+			},
+			wantErr: ErrBlobSizeMismatch,
+		},
+		{
+			name:    "undeclared size above blob limit",
+			blob:    streamBlob(big),
+			config:  OCIConfig{MaxBlobSize: 64},
+			wantErr: ErrExtractLimit,
+		},
+		{
+			name:         "extracted files above size limit",
+			declaredSize: int64(len(big)),
+			blob:         streamBlob(big),
+			config:       OCIConfig{ExtractLimits: ExtractLimits{MaxFileSize: 64}},
+			wantErr:      ErrExtractLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The digest defaults to that of the blob the server sends
+			digest := tt.digest
+			if digest == "" {
+				recorder := httptest.NewRecorder()
+				tt.blob(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+				digest = digestOf(recorder.Body.Bytes())
+			}
+			server, blobRequested := newOCITestServer(t, ociLayer{Digest: digest, Size: tt.declaredSize}, tt.blob)
+			ruleSet, err := newTestOCIFetcher(server, tt.config).Fetch(context.Background())
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Fetch() error = %v, want %v", err, tt.wantErr)
+				}
+				if tt.wantNoRequest && *blobRequested {
+					t.Error("oversized blob was downloaded")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if len(ruleSet.Patterns) != 1 || ruleSet.Patterns[0].Name != "employee-id" {
+				t.Errorf("patterns = %+v, want employee-id", ruleSet.Patterns)
+			}
+		})
+	}
+}
+
+func TestOCIFetcher_FetchPath(t *testing.T) {
+	layer := buildTarGz(t,
+		archiveFile{name: "rules/korea/rrn.yaml", content: "name: kr-rrn\npatterns:\n  - regex: '\\d{6}-\\d{7}'\n"},
+		archiveFile{name: "rules/korea/phone.yaml", content: "name: kr-phone\npatterns:\n  - regex: '010-\\d{4}-\\d{4}'\n"},
+		archiveFile{name: "rules/usa/ssn.yaml", content: "name: us-ssn\npatterns:\n  - regex: '\\d{3}-\\d{2}-\\d{4}'\n"},
+	)
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "", want: []string{"kr-phone", "kr-rrn", "us-ssn"}},
+		{path: "rules/korea", want: []string{"kr-phone", "kr-rrn"}},
+		{path: "rules/usa/ssn.yaml", want: []string{"us-ssn"}},
+		{path: "rules/japan", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			server, _ := newOCITestServer(t, ociLayer{Digest: digestOf(layer), Size: int64(len(layer))}, streamBlob(layer))
+			ruleSet, err := newTestOCIFetcher(server, OCIConfig{Path: tt.path}).Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+
+			var got []string
+			for _, p := range ruleSet.Patterns {
+				got = append(got, p.Name)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("patterns = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOCIFetcher_ValidatePath(t *testing.T) {
+	for _, path := range []string{"../rules", "/etc/rules"} {
+		fetcher := NewOCIFetcher(OCIConfig{Registry: "ghcr.io", Repository: "rules", Path: path})
+		if err := fetcher.Validate(); err == nil {
+			t.Errorf("Validate() with path %q succeeded, want error", path)
+		}
+	}
+	fetcher := NewOCIFetcher(OCIConfig{Registry: "ghcr.io", Repository: "rules", Path: "rules/korea"})
+	if err := fetcher.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	valid := digestOf([]byte("rules"))
+	if _, err := parseDigest(valid); err != nil {
+		t.Errorf("parseDigest(%q) error = %v", valid, err)
+	}
+	for _, digest := range []string{"", "sha256", "sha256:abc", "sha512:" + strings.Repeat("0", 128), "sha256:" + strings.Repeat("z", 64)} {
+		if _, err := parseDigest(digest); err == nil {
+			t.Errorf("parseDigest(%q) succeeded, want error", digest)
+		}
+	}
+}
+
+func TestOCIFetcher_FetchDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	server, _ := newOCITestServer(t, ociLayer{Digest: digestOf(nil)}, func(w http.ResponseWriter, r *http.Request) {
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		writeTar(t, tw, archiveFile{name: "rules/email.yaml", content: "name: email\n"})
+		tw.Flush()
+		gz.Flush()
+		w.(http.Flusher).Flush()
+
+		// Stall until the client gives up
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := newTestOCIFetcher(server, OCIConfig{}).Fetch(ctx)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Fetch() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Fetch() did not return after the deadline")
+	}
+}
+
+// ociArtifact is a manifest served by newReferrersTestServer, with a single
+// layer holding one rule file
+type ociArtifact struct {
+	artifactType string
+	created      string
+	rule         string
+}
+
+// newReferrersTestServer serves artifacts referring to subject, listed by
+// the referrers API or, without it, by the fallback tag. The listing is not
+// filtered by artifact type. It returns the server and a pointer to the
+// artifact type the last referrers request asked for.
+func newReferrersTestServer(t *testing.T, subject string, referrersAPI bool, artifacts ...ociArtifact) (*httptest.Server, *string) {
+	t.Helper()
+	mux := http.NewServeMux()
+	index := ociIndex{SchemaVersion: 2, MediaType: ociIndexMediaType}
+	for _, a := range artifacts {
+		layer := buildTarGz(t, archiveFile{name: a.rule + ".yaml", content: "name: " + a.rule + "\npatterns:\n  - regex: 'X-\\d+'\n"})
+		manifest, err := json.Marshal(ociManifest{
+			SchemaVersion: 2,
+			MediaType:     ociManifestMediaType,
+			ArtifactType:  a.artifactType,
+			Layers:        []ociLayer{{Digest: digestOf(layer), Size: int64(len(layer))}},
+			Subject:       &ociLayer{MediaType: ociManifestMediaType, Digest: subject},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mux.HandleFunc("/v2/rules/manifests/"+digestOf(manifest), func(w http.ResponseWriter, r *http.Request) {
+			w.Write(manifest)
+		})
+		mux.HandleFunc("/v2/rules/blobs/"+digestOf(layer), streamBlob(layer))
+		index.Manifests = append(index.Manifests, ociDescriptor{
+			MediaType:    ociManifestMediaType,
+			Digest:       digestOf(manifest),
+			Size:         int64(len(manifest)),
+			ArtifactType: a.artifactType,
+			Annotations:  map[string]string{ociCreatedAnnotation: a.created},
+		})
+	}
+
+	requestedType := new(string)
+	serveIndex := func(w http.ResponseWriter, r *http.Request) {
+		*requestedType = r.URL.Query().Get("artifactType")
+		w.Header().Set("Content-Type", ociIndexMediaType)
+		json.NewEncoder(w).Encode(index)
+	}
+	if referrersAPI {
+		mux.HandleFunc("/v2/rules/referrers/"+subject, serveIndex)
+	} else {
+		mux.HandleFunc("/v2/rules/manifests/"+strings.Replace(subject, ":", "-", 1), serveIndex)
+	}
+
+	server := httptest.NewTLSServer(mux)
+	t.Cleanup(server.Close)
+	return server, requestedType
+}
+
+func TestOCIFetcher_FetchReferrer(t *testing.T) {
+	subject := digestOf([]byte("base image manifest"))
+	artifacts := []ociArtifact{
+		{artifactType: "application/vnd.dev.cosign.artifact.sig.v1+json", created: "2025-03-01T00:00:00Z", rule: "signature"},
+		{artifactType: DefaultRulesArtifactType, created: "2025-01-01T00:00:00Z", rule: "old-rules"},
+		{artifactType: DefaultRulesArtifactType, created: "2025-02-01T00:00:00Z", rule: "current-rules"},
+		{artifactType: "application/vnd.example.rules.v2", created: "2025-02-15T00:00:00Z", rule: "custom-rules"},
+	}
+
+	tests := []struct {
+		name         string
+		referrersAPI bool
+		artifactType string
+		want         string
+	}{
+		{name: "referrers API", referrersAPI: true, want: "current-rules"},
+		{name: "fallback tag", referrersAPI: false, want: "current-rules"},
+		{name: "custom artifact type", referrersAPI: true, artifactType: "application/vnd.example.rules.v2", want: "custom-rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requestedType := newReferrersTestServer(t, subject, tt.referrersAPI, artifacts...)
+			fetcher := newTestOCIFetcher(server, OCIConfig{Subject: subject, ArtifactType: tt.artifactType})
+			if err := fetcher.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			ruleSet, err := fetcher.Fetch(context.Background())
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if len(ruleSet.Patterns) != 1 || ruleSet.Patterns[0].Name != tt.want {
+				t.Errorf("patterns = %+v, want %s", ruleSet.Patterns, tt.want)
+			}
+			if !strings.HasPrefix(ruleSet.Version, "sha256:") {
+				t.Errorf("Version = %q, want the artifact's manifest digest", ruleSet.Version)
+			}
+			if want := fetcher.artifactType; tt.referrersAPI && *requestedType != want {
+				t.Errorf("referrers request filtered by %q, want %q", *requestedType, want)
+			}
+		})
+	}
+}
+
+func TestOCIFetcher_FetchReferrerErrors(t *testing.T) {
+	subject := digestOf([]byte("base image manifest"))
+
+	t.Run("no artifact of the type", func(t *testing.T) {
+		server, _ := newReferrersTestServer(t, subject, true, ociArtifact{artifactType: "application/vnd.example.sbom", rule: "sbom"})
+		_, err := newTestOCIFetcher(server, OCIConfig{Subject: subject}).Fetch(context.Background())
+		if !errors.Is(err, ErrNoReferrer) {
+			t.Errorf("Fetch() error = %v, want %v", err, ErrNoReferrer)
+		}
+	})
+
+	t.Run("no referrers at all", func(t *testing.T) {
+		server, _ := newReferrersTestServer(t, digestOf([]byte("other image")), false)
+		_, err := newTestOCIFetcher(server, OCIConfig{Subject: subject}).Fetch(context.Background())
+		if !errors.Is(err, ErrNoReferrer) {
+			t.Errorf("Fetch() error = %v, want %v", err, ErrNoReferrer)
+		}
+	})
+
+	t.Run("invalid subject", func(t *testing.T) {
+		fetcher := NewOCIFetcher(OCIConfig{Registry: "ghcr.io", Repository: "rules", Subject: "latest"})
+		if err := fetcher.Validate(); err == nil {
+			t.Error("Validate() with a tag as subject succeeded, want error")
+		}
+	})
+}
+
+func TestVerifyReference(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2}`)
+	if err := verifyReference(digestOf(manifest), manifest); err != nil {
+		t.Errorf("verifyReference() error = %v", err)
+	}
+	if err := verifyReference("latest", manifest); err != nil {
+		t.Errorf("verifyReference() of a tag error = %v", err)
+	}
+	if err := verifyReference(digestOf([]byte("other")), manifest); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("verifyReference() error = %v, want %v", err, ErrDigestMismatch)
+	}
+}
@@ -0,0 +1,96 @@
+package subscription
+
+import (
+	"context"
+	"sort"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+)
+
+// CorpusReport is the result of dry running a subscription against a corpus
+type CorpusReport struct {
+	// Lines is the number of corpus entries scanned
+	Lines int
+
+	// Patterns lists the match counts of every candidate pattern that
+	// compiled, noisiest first
+	Patterns []piiv1alpha1.CorpusPatternResult
+
+	// Errors lists problems with the subscription or its patterns
+	Errors []string
+}
+
+// DryRunAgainstCorpus matches the patterns spec subscribes to against every
+// entry of corpus and reports the matches per pattern, so that noisy
+// community patterns can be found before they are activated. The patterns
+// are loaded into an isolated engine; the shared engine is not touched.
+func (m *Manager) DryRunAgainstCorpus(ctx context.Context, spec piiv1alpha1.PIIRuleSubscriptionSpec, corpus []string) (*CorpusReport, error) {
+	result := NewSubscriptionResult()
+	_, candidates, ok, err := m.candidates(spec, result)
+	if err != nil {
+		return nil, err
+	}
+	report := &CorpusReport{Lines: len(corpus)}
+	if !ok {
+		result.finishErrors()
+		report.Errors = result.Errors
+		return report, nil
+	}
+
+	// The engine comes with the built-in patterns, but only the candidates
+	// are matched
+	engine := detector.NewEngine()
+	specs := make([]detector.NamedPatternSpec, 0, len(candidates))
+	for _, c := range candidates {
+		specs = append(specs, c.spec())
+	}
+	failed := engine.AddPatterns(specs)
+
+	names := make([]string, 0, len(candidates))
+	counts := make(map[string]*piiv1alpha1.CorpusPatternResult, len(candidates))
+	for _, c := range candidates {
+		if _, ok := failed[c.key]; ok {
+			result.addError("failed to add pattern: " + c.pattern.Pattern.Name)
+			continue
+		}
+		if _, ok := counts[c.key]; ok {
+			continue
+		}
+		names = append(names, c.key)
+		counts[c.key] = &piiv1alpha1.CorpusPatternResult{Name: c.pattern.Pattern.Name}
+	}
+
+	for _, line := range corpus {
+		detections, err := engine.DetectWithPatterns(ctx, line, names)
+		if err != nil {
+			return nil, err
+		}
+		matched := make(map[string]bool)
+		for _, d := range detections {
+			count := counts[d.PatternName]
+			if count == nil {
+				continue
+			}
+			count.Matches++
+			if !matched[d.PatternName] {
+				matched[d.PatternName] = true
+				count.MatchedLines++
+			}
+		}
+	}
+
+	for _, name := range names {
+		report.Patterns = append(report.Patterns, *counts[name])
+	}
+	sort.SliceStable(report.Patterns, func(i, j int) bool {
+		if report.Patterns[i].MatchedLines != report.Patterns[j].MatchedLines {
+			return report.Patterns[i].MatchedLines > report.Patterns[j].MatchedLines
+		}
+		return report.Patterns[i].Matches > report.Patterns[j].Matches
+	})
+
+	result.finishErrors()
+	report.Errors = result.Errors
+	return report, nil
+}
@@ -2,28 +2,77 @@ package subscription
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
 	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/policy"
 	"github.com/bunseokbot/pii-redactor/internal/source"
 )
 
+// Backoff between attempts to register a pattern that failed to register
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// ErrInvalidOverrides is returned for a subscription with an override that
+// cannot be applied, such as one with an invalid masking strategy
+var ErrInvalidOverrides = errors.New("invalid overrides")
+
+// maxResultErrors caps the errors a SubscriptionResult keeps, so a source
+// with many broken patterns cannot grow the subscription status unbounded
+const maxResultErrors = 20
+
 // Manager manages rule subscriptions
 type Manager struct {
 	cache  *source.Cache
 	engine *detector.Engine
+
+	// addPatterns registers patterns, normally with the engine
+	addPatterns func([]detector.NamedPatternSpec) map[string]error
+	now         func() time.Time
+
+	// failures tracks patterns that failed to register, keyed by pattern key
+	mu       sync.Mutex
+	failures map[string]*patternFailure
+}
+
+// patternFailure tracks the failed registration attempts of a pattern
+type patternFailure struct {
+	attempts  int
+	lastError string
+	nextRetry time.Time
 }
 
 // NewManager creates a new subscription manager
 func NewManager(cache *source.Cache, engine *detector.Engine) *Manager {
 	return &Manager{
-		cache:  cache,
-		engine: engine,
+		cache:       cache,
+		engine:      engine,
+		addPatterns: engine.AddPatterns,
+		now:         time.Now,
+		failures:    make(map[string]*patternFailure),
 	}
 }
 
+// retryDelay returns the backoff after the given number of failed attempts
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
+
 // SubscriptionResult holds the result of processing a subscription
 type SubscriptionResult struct {
 	// SubscribedPatterns is the list of subscribed patterns
@@ -32,8 +81,20 @@ type SubscriptionResult struct {
 	// TotalPatterns is the total count
 	TotalPatterns int
 
-	// Errors contains any errors encountered
+	// Errors contains any errors encountered, at most maxResultErrors of
+	// them followed by a count of the omitted ones
 	Errors []string
+
+	// omittedErrors counts errors dropped beyond maxResultErrors
+	omittedErrors int
+
+	// Retrying lists patterns that failed to register and are retried on a
+	// later Subscribe once their backoff has passed
+	Retrying []piiv1alpha1.RetryingPatternInfo
+
+	// RetryAfter is the time until the earliest retry is due, or zero when
+	// no pattern is retrying
+	RetryAfter time.Duration
 }
 
 // NewSubscriptionResult creates a new SubscriptionResult
@@ -44,10 +105,127 @@ func NewSubscriptionResult() *SubscriptionResult {
 	}
 }
 
-// Subscribe processes a subscription and returns matching patterns
+// addError records an error, counting it as omitted once the cap is reached
+func (r *SubscriptionResult) addError(msg string) {
+	if len(r.Errors) >= maxResultErrors {
+		r.omittedErrors++
+		return
+	}
+	r.Errors = append(r.Errors, msg)
+}
+
+// finishErrors appends the number of omitted errors, if any
+func (r *SubscriptionResult) finishErrors() {
+	if r.omittedErrors > 0 {
+		r.Errors = append(r.Errors, fmt.Sprintf("%d more errors omitted", r.omittedErrors))
+		r.omittedErrors = 0
+	}
+}
+
+// Subscribe processes a subscription and returns matching patterns. A
+// subscription with invalid overrides is rejected with an error wrapping
+// ErrInvalidOverrides before any of its patterns are registered.
 func (m *Manager) Subscribe(ctx context.Context, spec piiv1alpha1.PIIRuleSubscriptionSpec) (*SubscriptionResult, error) {
 	result := NewSubscriptionResult()
 
+	sourceKey, pending, ok, err := m.candidates(spec, result)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return result, nil
+	}
+	specs := make([]detector.NamedPatternSpec, 0, len(pending))
+	for _, pp := range pending {
+		specs = append(specs, pp.spec())
+	}
+
+	// Patterns that failed before are left out until their backoff has passed
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	due := make([]detector.NamedPatternSpec, 0, len(specs))
+	for _, named := range specs {
+		if f, ok := m.failures[named.Name]; ok && now.Before(f.nextRetry) {
+			continue
+		}
+		due = append(due, named)
+	}
+
+	// Add to engine in a single batch
+	failed := m.addPatterns(due)
+	attempted := make(map[string]bool, len(due))
+	for _, named := range due {
+		attempted[named.Name] = true
+	}
+
+	for _, pp := range pending {
+		if err, ok := failed[pp.key]; ok {
+			f := m.failures[pp.key]
+			if f == nil {
+				f = &patternFailure{}
+				m.failures[pp.key] = f
+			}
+			f.attempts++
+			f.lastError = err.Error()
+			f.nextRetry = now.Add(retryDelay(f.attempts))
+			result.addError("failed to add pattern: " + pp.pattern.Pattern.Name)
+		}
+
+		if f, ok := m.failures[pp.key]; ok {
+			if attempted[pp.key] && failed[pp.key] == nil {
+				// The retry succeeded
+				delete(m.failures, pp.key)
+			} else {
+				result.Retrying = append(result.Retrying, piiv1alpha1.RetryingPatternInfo{
+					Name:      pp.pattern.Pattern.Name,
+					Source:    sourceKey,
+					Attempts:  f.attempts,
+					LastError: f.lastError,
+					NextRetry: metav1.NewTime(f.nextRetry),
+				})
+				if wait := f.nextRetry.Sub(now); result.RetryAfter == 0 || wait < result.RetryAfter {
+					result.RetryAfter = wait
+				}
+				continue
+			}
+		}
+
+		// Add to result
+		info := piiv1alpha1.SubscribedPatternInfo{
+			Name:       pp.pattern.Pattern.Name,
+			Category:   pp.pattern.Pattern.Category,
+			Version:    "", // Would need to track version
+			Source:     sourceKey,
+			Overridden: pp.overridden,
+		}
+		result.SubscribedPatterns = append(result.SubscribedPatterns, info)
+	}
+
+	result.TotalPatterns = len(result.SubscribedPatterns)
+	result.finishErrors()
+	return result, nil
+}
+
+// candidatePattern is a subscribed pattern, with overrides applied, keyed
+// by the name it is registered under
+type candidatePattern struct {
+	pattern    *matchedPattern
+	key        string
+	overridden bool
+}
+
+// spec returns the pattern's engine specification
+func (c candidatePattern) spec() detector.NamedPatternSpec {
+	return detector.NamedPatternSpec{Name: c.key, Spec: c.pattern.Pattern.ToPatternSpec()}
+}
+
+// candidates returns the key of the subscribed source and the patterns the
+// subscription selects from it, adding problems to result. It reports false
+// when the source is not cached, and returns an error wrapping
+// ErrInvalidOverrides when an override is invalid. Overrides that match no
+// subscribed pattern, such as misspelled ones, are only warned about.
+func (m *Manager) candidates(spec piiv1alpha1.PIIRuleSubscriptionSpec, result *SubscriptionResult) (string, []candidatePattern, bool, error) {
 	// Get source from cache
 	sourceKey := spec.SourceRef.Namespace + "/" + spec.SourceRef.Name
 	if spec.SourceRef.Namespace == "" {
@@ -56,8 +234,11 @@ func (m *Manager) Subscribe(ctx context.Context, spec piiv1alpha1.PIIRuleSubscri
 
 	cachedSource, exists := m.cache.GetSource(sourceKey)
 	if !exists {
-		result.Errors = append(result.Errors, "source not found: "+sourceKey)
-		return result, nil
+		result.addError("source not found: " + sourceKey)
+		return sourceKey, nil, false, nil
+	}
+	if cachedSource.Warning != "" {
+		result.addError(sourceKey + ": " + cachedSource.Warning)
 	}
 
 	// Get maturity levels (default: stable, incubating)
@@ -76,39 +257,43 @@ func (m *Manager) Subscribe(ctx context.Context, spec piiv1alpha1.PIIRuleSubscri
 		overrides[o.Pattern] = o
 	}
 
-	// Process each subscription
+	// Collect matching patterns across all subscriptions
+	var pending []candidatePattern
+	var overrideErrors []string
+	applied := make(map[string]bool, len(overrides))
+
 	for _, sub := range spec.Subscribe {
 		patterns := m.matchPatterns(cachedSource, sub, maturitySet)
 		for _, p := range patterns {
 			// Apply overrides
 			overridden := false
 			if override, exists := overrides[p.Name]; exists {
-				p = m.applyOverride(p, override)
-				overridden = true
+				applied[override.Pattern] = true
+				if overriddenPattern, err := m.applyOverride(p, override); err != nil {
+					overrideErrors = append(overrideErrors, err.Error())
+				} else {
+					p = overriddenPattern
+					overridden = true
+				}
 			}
 
-			// Add to engine
-			patternSpec := p.Pattern.ToPatternSpec()
 			patternKey := sourceKey + "/" + p.RuleSetName + "/" + p.Pattern.Name
-			if err := m.engine.AddPattern(patternKey, patternSpec); err != nil {
-				result.Errors = append(result.Errors, "failed to add pattern: "+p.Pattern.Name)
-				continue
-			}
+			pending = append(pending, candidatePattern{pattern: p, key: patternKey, overridden: overridden})
+		}
+	}
 
-			// Add to result
-			info := piiv1alpha1.SubscribedPatternInfo{
-				Name:       p.Pattern.Name,
-				Category:   p.Pattern.Category,
-				Version:    "", // Would need to track version
-				Source:     sourceKey,
-				Overridden: overridden,
-			}
-			result.SubscribedPatterns = append(result.SubscribedPatterns, info)
+	// Report overrides that did not match any subscribed pattern
+	for _, o := range spec.Overrides {
+		if !applied[o.Pattern] {
+			result.addError("override does not match any subscribed pattern: " + o.Pattern)
+			applied[o.Pattern] = true
 		}
 	}
+	if len(overrideErrors) > 0 {
+		return sourceKey, nil, true, fmt.Errorf("%w: %s", ErrInvalidOverrides, strings.Join(overrideErrors, "; "))
+	}
 
-	result.TotalPatterns = len(result.SubscribedPatterns)
-	return result, nil
+	return sourceKey, pending, true, nil
 }
 
 // matchedPattern holds a matched pattern with context
@@ -201,8 +386,17 @@ func (m *Manager) matchesPatternNames(patternName string, patterns []string) boo
 	return false
 }
 
-// applyOverride applies an override to a matched pattern
-func (m *Manager) applyOverride(mp *matchedPattern, override piiv1alpha1.PatternOverride) *matchedPattern {
+// applyOverride applies an override to a matched pattern. An override with
+// an invalid masking strategy is not applied.
+func (m *Manager) applyOverride(mp *matchedPattern, override piiv1alpha1.PatternOverride) (*matchedPattern, error) {
+	var masking patterns.MaskingStrategy
+	if override.MaskingStrategy != nil {
+		var err error
+		if masking, err = policy.ConvertMaskingStrategy(*override.MaskingStrategy); err != nil {
+			return mp, fmt.Errorf("invalid masking override for %s: %w", override.Pattern, err)
+		}
+	}
+
 	// Create a copy
 	patternCopy := *mp.Pattern
 	mp.Pattern = &patternCopy
@@ -216,14 +410,10 @@ func (m *Manager) applyOverride(mp *matchedPattern, override piiv1alpha1.Pattern
 	}
 
 	if override.MaskingStrategy != nil {
-		mp.Pattern.MaskingStrategy.Type = override.MaskingStrategy.Type
-		mp.Pattern.MaskingStrategy.ShowFirst = override.MaskingStrategy.ShowFirst
-		mp.Pattern.MaskingStrategy.ShowLast = override.MaskingStrategy.ShowLast
-		mp.Pattern.MaskingStrategy.MaskChar = override.MaskingStrategy.MaskChar
-		mp.Pattern.MaskingStrategy.Replacement = override.MaskingStrategy.Replacement
+		mp.Pattern.MaskingStrategy = masking
 	}
 
-	return mp
+	return mp, nil
 }
 
 // Unsubscribe removes patterns from a subscription
@@ -235,6 +425,15 @@ func (m *Manager) Unsubscribe(sourceKey string) {
 	for _, patternKey := range patterns {
 		m.engine.RemovePattern(patternKey)
 	}
+
+	// Stop retrying the source's failed patterns
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for patternKey := range m.failures {
+		if strings.HasPrefix(patternKey, sourceKey+"/") {
+			delete(m.failures, patternKey)
+		}
+	}
 }
 
 // GetSubscribedPatterns returns the list of patterns for a source
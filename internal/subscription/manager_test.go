@@ -0,0 +1,313 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	piiv1alpha1 "github.com/bunseokbot/pii-redactor/api/v1alpha1"
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/source"
+)
+
+func TestManager_SubscribeOverrides(t *testing.T) {
+	cache := source.NewCache()
+	cache.SetSource("community", []*source.RuleSet{{
+		Name:     "korea",
+		Version:  "1.0.0",
+		Maturity: "stable",
+		Patterns: []source.PatternDefinition{
+			{Name: "kr-phone", Category: "korea", Patterns: []source.PatternRule{{Regex: `010-\d{4}-\d{4}`, Confidence: "high"}}, Severity: "high"},
+			{Name: "kr-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\d{6}-\d{7}`, Confidence: "high"}}, Severity: "critical"},
+		},
+	}})
+
+	tests := []struct {
+		name           string
+		overrides      []piiv1alpha1.PatternOverride
+		wantWarnings   []string
+		wantErr        string
+		wantOverridden map[string]bool
+	}{
+		{
+			name:           "valid override",
+			overrides:      []piiv1alpha1.PatternOverride{{Pattern: "kr-phone", Severity: "medium"}},
+			wantWarnings:   []string{},
+			wantOverridden: map[string]bool{"kr-phone": true, "kr-rrn": false},
+		},
+		{
+			name: "typo override",
+			overrides: []piiv1alpha1.PatternOverride{
+				{Pattern: "kr-phone", Severity: "medium"},
+				{Pattern: "kr-phnoe", Severity: "low"},
+			},
+			wantWarnings:   []string{"override does not match any subscribed pattern: kr-phnoe"},
+			wantOverridden: map[string]bool{"kr-phone": true, "kr-rrn": false},
+		},
+		{
+			name:           "override for pattern outside the subscription",
+			overrides:      []piiv1alpha1.PatternOverride{{Pattern: "us-ssn", Severity: "low"}},
+			wantWarnings:   []string{"override does not match any subscribed pattern: us-ssn"},
+			wantOverridden: map[string]bool{"kr-phone": false, "kr-rrn": false},
+		},
+		{
+			name: "invalid masking override",
+			overrides: []piiv1alpha1.PatternOverride{{Pattern: "kr-phone", MaskingStrategy: &piiv1alpha1.MaskingStrategy{
+				Type:     "partial",
+				ShowLast: intstr.FromString("150%"),
+			}}},
+			wantErr: "invalid overrides: invalid masking override for kr-phone: showLast: invalid percentage \"150%\": must be between 0% and 100%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := detector.NewEngine()
+			before := len(engine.ListPatterns())
+			manager := NewManager(cache, engine)
+			result, err := manager.Subscribe(context.Background(), piiv1alpha1.PIIRuleSubscriptionSpec{
+				SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+				Subscribe: []piiv1alpha1.CategorySubscription{{Category: "korea"}},
+				Overrides: tt.overrides,
+			})
+			registered := len(engine.ListPatterns()) - before
+			if tt.wantErr != "" {
+				if !errors.Is(err, ErrInvalidOverrides) || err.Error() != tt.wantErr {
+					t.Fatalf("Subscribe() error = %v, want %q", err, tt.wantErr)
+				}
+				if registered != 0 {
+					t.Errorf("%d patterns registered despite invalid overrides", registered)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Errors, tt.wantWarnings) {
+				t.Errorf("Errors = %v, want %v", result.Errors, tt.wantWarnings)
+			}
+			if registered != 2 {
+				t.Errorf("%d patterns registered, want both", registered)
+			}
+
+			overridden := make(map[string]bool)
+			for _, p := range result.SubscribedPatterns {
+				overridden[p.Name] = p.Overridden
+			}
+			if !reflect.DeepEqual(overridden, tt.wantOverridden) {
+				t.Errorf("overridden = %v, want %v", overridden, tt.wantOverridden)
+			}
+		})
+	}
+}
+
+func TestManager_SubscribeRetriesFailedPatterns(t *testing.T) {
+	cache := source.NewCache()
+	cache.SetSource("community", []*source.RuleSet{{
+		Name:     "korea",
+		Version:  "1.0.0",
+		Maturity: "stable",
+		Patterns: []source.PatternDefinition{
+			{Name: "kr-phone", Category: "korea", Patterns: []source.PatternRule{{Regex: `010-\d{4}-\d{4}`, Confidence: "high"}}, Severity: "high"},
+			{Name: "kr-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\d{6}-\d{7}`, Confidence: "high"}}, Severity: "critical"},
+		},
+	}})
+	spec := piiv1alpha1.PIIRuleSubscriptionSpec{
+		SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+		Subscribe: []piiv1alpha1.CategorySubscription{{Category: "korea"}},
+	}
+	const rrnKey = "community/korea/kr-rrn"
+
+	engine := detector.NewEngine()
+	manager := NewManager(cache, engine)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+
+	// kr-rrn fails to register on its first attempt only
+	attempts := make(map[string]int)
+	manager.addPatterns = func(specs []detector.NamedPatternSpec) map[string]error {
+		var ok []detector.NamedPatternSpec
+		var failed map[string]error
+		for _, named := range specs {
+			attempts[named.Name]++
+			if named.Name == rrnKey && attempts[named.Name] == 1 {
+				failed = map[string]error{named.Name: errors.New("engine busy")}
+				continue
+			}
+			ok = append(ok, named)
+		}
+		engine.AddPatterns(ok)
+		return failed
+	}
+
+	subscribe := func() *SubscriptionResult {
+		t.Helper()
+		result, err := manager.Subscribe(context.Background(), spec)
+		if err != nil {
+			t.Fatalf("Subscribe() error = %v", err)
+		}
+		return result
+	}
+
+	result := subscribe()
+	if result.TotalPatterns != 1 || len(result.Retrying) != 1 {
+		t.Fatalf("first Subscribe() subscribed %d and retrying %v, want 1 and kr-rrn", result.TotalPatterns, result.Retrying)
+	}
+	retrying := result.Retrying[0]
+	if retrying.Name != "kr-rrn" || retrying.Attempts != 1 || retrying.LastError != "engine busy" || !retrying.NextRetry.Time.Equal(now.Add(retryBaseDelay)) {
+		t.Errorf("Retrying = %+v, want kr-rrn after 1 attempt retried at %v", retrying, now.Add(retryBaseDelay))
+	}
+	if result.RetryAfter != retryBaseDelay {
+		t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, retryBaseDelay)
+	}
+	if engine.HasPattern(rrnKey) {
+		t.Fatal("failed pattern was registered")
+	}
+
+	// Within the backoff the failed pattern is not attempted again
+	now = now.Add(retryBaseDelay / 2)
+	result = subscribe()
+	if attempts[rrnKey] != 1 || len(result.Retrying) != 1 {
+		t.Errorf("kr-rrn attempted %d times and retrying %v within backoff, want 1 and still retrying", attempts[rrnKey], result.Retrying)
+	}
+	if result.RetryAfter != retryBaseDelay/2 {
+		t.Errorf("RetryAfter = %v, want %v", result.RetryAfter, retryBaseDelay/2)
+	}
+
+	// Once the backoff has passed the retry succeeds
+	now = now.Add(retryBaseDelay)
+	result = subscribe()
+	if attempts[rrnKey] != 2 || len(result.Retrying) != 0 || result.TotalPatterns != 2 || result.RetryAfter != 0 {
+		t.Errorf("after backoff: attempts %d, retrying %v, subscribed %d, RetryAfter %v; want 2, none, 2, 0",
+			attempts[rrnKey], result.Retrying, result.TotalPatterns, result.RetryAfter)
+	}
+	if !engine.HasPattern(rrnKey) {
+		t.Error("retried pattern was not registered")
+	}
+}
+
+func TestManager_SubscribeLimits(t *testing.T) {
+	var patterns []source.PatternDefinition
+	for i := 0; i < 30; i++ {
+		patterns = append(patterns, source.PatternDefinition{
+			Name:     fmt.Sprintf("p%02d", i),
+			Category: "bulk",
+			Patterns: []source.PatternRule{{Regex: `\d{4}`, Confidence: "high"}},
+			Severity: "low",
+		})
+	}
+	cache := source.NewCache()
+	cache.SetMaxPatternsPerSource(25)
+	cache.SetSource("community", []*source.RuleSet{{
+		Name:     "bulk",
+		Version:  "1.0.0",
+		Maturity: "stable",
+		Patterns: patterns,
+	}})
+
+	manager := NewManager(cache, detector.NewEngine())
+	manager.addPatterns = func(specs []detector.NamedPatternSpec) map[string]error {
+		failed := make(map[string]error)
+		for _, named := range specs {
+			failed[named.Name] = errors.New("engine busy")
+		}
+		return failed
+	}
+
+	result, err := manager.Subscribe(context.Background(), piiv1alpha1.PIIRuleSubscriptionSpec{
+		SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+		Subscribe: []piiv1alpha1.CategorySubscription{{Category: "bulk"}},
+	})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	if len(result.Errors) != maxResultErrors+1 {
+		t.Fatalf("got %d errors, want %d", len(result.Errors), maxResultErrors+1)
+	}
+	if want := "community: source declares 30 patterns, only the first 25 are used"; result.Errors[0] != want {
+		t.Errorf("Errors[0] = %q, want %q", result.Errors[0], want)
+	}
+	// The warning and 19 failures are kept, the other 6 failures are counted
+	if want := "6 more errors omitted"; result.Errors[maxResultErrors] != want {
+		t.Errorf("last error = %q, want %q", result.Errors[maxResultErrors], want)
+	}
+	if len(result.Retrying) != 25 {
+		t.Errorf("Retrying %d patterns, want 25", len(result.Retrying))
+	}
+}
+
+func TestManager_DryRunAgainstCorpus(t *testing.T) {
+	cache := source.NewCache()
+	cache.SetSource("community", []*source.RuleSet{{
+		Name:     "korea",
+		Version:  "1.0.0",
+		Maturity: "stable",
+		Patterns: []source.PatternDefinition{
+			{Name: "noisy-id", Category: "korea", Patterns: []source.PatternRule{{Regex: `\d{6}`, Confidence: "low"}}, Severity: "low"},
+			{Name: "precise-rrn", Category: "korea", Patterns: []source.PatternRule{{Regex: `\b\d{6}-[1-4]\d{6}\b`, Confidence: "high"}}, Severity: "critical"},
+			{Name: "broken", Category: "korea", Patterns: []source.PatternRule{{Regex: `(`}}, Severity: "low"},
+		},
+	}})
+	engine := detector.NewEngine()
+	manager := NewManager(cache, engine)
+
+	corpus := []string{
+		"order 123456 shipped",
+		"request took 250000us, trace 987654321",
+		"rrn=920101-1234567",
+		"no numbers here",
+	}
+	report, err := manager.DryRunAgainstCorpus(context.Background(), piiv1alpha1.PIIRuleSubscriptionSpec{
+		SourceRef: piiv1alpha1.SourceRef{Name: "community"},
+		Subscribe: []piiv1alpha1.CategorySubscription{{Category: "korea"}},
+	}, corpus)
+	if err != nil {
+		t.Fatalf("DryRunAgainstCorpus() error = %v", err)
+	}
+
+	want := []piiv1alpha1.CorpusPatternResult{
+		{Name: "noisy-id", Matches: 5, MatchedLines: 3},
+		{Name: "precise-rrn", Matches: 1, MatchedLines: 1},
+	}
+	if !reflect.DeepEqual(report.Patterns, want) {
+		t.Errorf("Patterns = %+v, want %+v", report.Patterns, want)
+	}
+	if report.Lines != len(corpus) {
+		t.Errorf("Lines = %d, want %d", report.Lines, len(corpus))
+	}
+	if wantErrors := []string{"failed to add pattern: broken"}; !reflect.DeepEqual(report.Errors, wantErrors) {
+		t.Errorf("Errors = %v, want %v", report.Errors, wantErrors)
+	}
+
+	// The dry run leaves the shared engine alone
+	for _, name := range []string{"community/korea/noisy-id", "community/korea/precise-rrn"} {
+		if engine.HasPattern(name) {
+			t.Errorf("shared engine has pattern %s after a dry run", name)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: retryBaseDelay},
+		{attempts: 2, want: 2 * retryBaseDelay},
+		{attempts: 3, want: 4 * retryBaseDelay},
+		{attempts: 10, want: retryMaxDelay},
+		{attempts: 100, want: retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
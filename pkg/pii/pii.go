@@ -0,0 +1,266 @@
+// Package pii is the public API for embedding PII detection and redaction in
+// other Go programs. It wraps the operator's detection engine and redactor
+// behind stable exported types.
+package pii
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bunseokbot/pii-redactor/internal/detector"
+	"github.com/bunseokbot/pii-redactor/internal/detector/patterns"
+	"github.com/bunseokbot/pii-redactor/internal/redactor"
+)
+
+// ErrNoPatterns is returned when an explicit pattern list contains no pattern names
+var ErrNoPatterns = redactor.ErrNoPatterns
+
+// Detection is a single PII match
+type Detection struct {
+	// Pattern is the name of the pattern that matched
+	Pattern string
+
+	// DisplayName is the human-readable pattern name
+	DisplayName string
+
+	// Text is the matched text
+	Text string
+
+	// Start and End are the byte offsets of the match in the input
+	Start int
+	End   int
+
+	// Confidence is the confidence of the matching rule (high, medium, low)
+	Confidence string
+
+	// Severity is the pattern severity (critical, high, medium, low)
+	Severity string
+
+	// Redacted is the masked replacement, set by Redact
+	Redacted string
+
+	// Metadata holds attributes derived from the match, if any
+	Metadata map[string]string
+}
+
+// Result is the outcome of redacting a text
+type Result struct {
+	// Original is the input text
+	Original string
+
+	// Redacted is the text with all detections masked
+	Redacted string
+
+	// Detections lists the detections, ordered by position
+	Detections []Detection
+
+	// Truncated is true when only a prefix of the input was scanned; the rest
+	// is replaced with "[UNSCANNED]" in Redacted
+	Truncated bool
+
+	// Skipped is true when the input exceeded the size limit and was not
+	// scanned; Redacted is then "[UNSCANNED]"
+	Skipped bool
+
+	// Scanned is true when the input was scanned for PII, in full or, when
+	// Truncated, in part
+	Scanned bool
+}
+
+// Clean reports whether the whole input was scanned and no PII was found.
+// A nil, skipped or truncated result is never clean.
+func (r *Result) Clean() bool {
+	return r != nil && r.Scanned && !r.Truncated && len(r.Detections) == 0
+}
+
+// MaskingStrategy defines how a detection is masked. It is the strategy the
+// redactor applies, so its fields are those of PIIPattern maskingStrategy:
+// Type is one of full, partial, hash, tokenize, pseudonym, maskBefore or
+// maskAfter; ShowFirst/ShowLast or ShowFirstPercent/ShowLastPercent leave
+// characters visible for partial masking; MaskChar, Replacement, Delimiter,
+// Chain, PreserveBoundaryLines and Group refine it.
+type MaskingStrategy = patterns.MaskingStrategy
+
+// Rule is a regular expression with the confidence of its matches
+type Rule struct {
+	Regex      string
+	Confidence string
+}
+
+// Pattern defines a custom PII pattern
+type Pattern struct {
+	DisplayName string
+	Description string
+	Rules       []Rule
+
+	// Validator optionally names a built-in validator, e.g. "luhn"
+	Validator string
+
+	Masking  MaskingStrategy
+	Severity string
+}
+
+// Scanner detects and redacts PII. It is safe for concurrent use.
+type Scanner struct {
+	engine   *detector.Engine
+	redactor *redactor.Redactor
+}
+
+// NewScanner creates a scanner with all built-in patterns loaded
+func NewScanner() *Scanner {
+	engine := detector.NewEngine()
+	return &Scanner{
+		engine:   engine,
+		redactor: redactor.NewRedactor(engine),
+	}
+}
+
+// SetValidation enables or disables checksum validation, e.g. Luhn for cards
+func (s *Scanner) SetValidation(enabled bool) {
+	if enabled {
+		s.engine.EnableValidation()
+	} else {
+		s.engine.DisableValidation()
+	}
+}
+
+// SetEvasionHardening enables or disables matching through zero-width
+// characters and look-alike Unicode characters
+func (s *Scanner) SetEvasionHardening(enabled bool) {
+	s.engine.SetEvasionHardening(enabled)
+}
+
+// SetTrivialNumberFilter enables or disables dropping obvious test numbers
+// such as 111-11-1111 or 123456789 for numeric ID patterns. It is enabled
+// by default.
+func (s *Scanner) SetTrivialNumberFilter(enabled bool) {
+	s.engine.SetTrivialNumberFilter(enabled)
+}
+
+// SetMaxInputSizeKB limits the size of scanned input. Larger input is
+// truncated, or skipped entirely if skip is true. A size of 0 removes the limit.
+func (s *Scanner) SetMaxInputSizeKB(sizeKB int, skip bool) {
+	action := redactor.OversizeTruncate
+	if skip {
+		action = redactor.OversizeSkip
+	}
+	s.redactor.SetInputLimiter(redactor.NewInputLimiter(sizeKB, action))
+}
+
+// Patterns returns the names of all patterns, sorted
+func (s *Scanner) Patterns() []string {
+	names := s.engine.ListPatterns()
+	sort.Strings(names)
+	return names
+}
+
+// EnablePattern enables a pattern by name and reports whether it exists
+func (s *Scanner) EnablePattern(name string) bool {
+	return s.engine.EnablePattern(name)
+}
+
+// DisablePattern disables a pattern by name and reports whether it exists
+func (s *Scanner) DisablePattern(name string) bool {
+	return s.engine.DisablePattern(name)
+}
+
+// AddPattern adds or replaces a custom pattern. Custom patterns are used
+// when selected by name with DetectWithPatterns or RedactWithPatterns.
+func (s *Scanner) AddPattern(name string, p Pattern) error {
+	spec := patterns.PIIPatternSpec{
+		DisplayName:     p.DisplayName,
+		Description:     p.Description,
+		Validator:       p.Validator,
+		MaskingStrategy: p.Masking,
+		Severity:        p.Severity,
+	}
+	for _, r := range p.Rules {
+		spec.Patterns = append(spec.Patterns, patterns.PatternRule{
+			Regex:      r.Regex,
+			Confidence: r.Confidence,
+		})
+	}
+	return s.engine.AddPattern(name, spec)
+}
+
+// RemovePattern removes a pattern by name
+func (s *Scanner) RemovePattern(name string) {
+	s.engine.RemovePattern(name)
+}
+
+// Detect scans text with all enabled patterns
+func (s *Scanner) Detect(ctx context.Context, text string) ([]Detection, error) {
+	results, err := s.engine.DetectInText(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalDetections(results), nil
+}
+
+// DetectWithPatterns scans text with the named patterns only
+func (s *Scanner) DetectWithPatterns(ctx context.Context, text string, names []string) ([]Detection, error) {
+	results, err := s.engine.DetectWithPatterns(ctx, text, names)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalDetections(results), nil
+}
+
+// Redact detects and masks PII using all enabled patterns
+func (s *Scanner) Redact(ctx context.Context, text string) (*Result, error) {
+	result, err := s.redactor.Redact(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalResult(result), nil
+}
+
+// RedactWithPatterns detects and masks PII using the named patterns only.
+// ErrNoPatterns is returned if names contains no pattern names.
+func (s *Scanner) RedactWithPatterns(ctx context.Context, text string, names []string) (*Result, error) {
+	result, err := s.redactor.RedactWithPatterns(ctx, text, names)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalResult(result), nil
+}
+
+// ApplyMasking masks text with the given strategy
+func ApplyMasking(text string, strategy MaskingStrategy) string {
+	return redactor.ApplyMasking(text, strategy)
+}
+
+// fromInternalDetections converts detection results to public detections
+// ordered by position
+func fromInternalDetections(results []detector.DetectionResult) []Detection {
+	detections := make([]Detection, len(results))
+	for i, r := range results {
+		detections[i] = Detection{
+			Pattern:     r.PatternName,
+			DisplayName: r.DisplayName,
+			Text:        r.MatchedText,
+			Start:       r.Position.Start,
+			End:         r.Position.End,
+			Confidence:  r.Confidence,
+			Severity:    r.Severity,
+			Redacted:    r.RedactedText,
+			Metadata:    r.Metadata,
+		}
+	}
+	sort.SliceStable(detections, func(i, j int) bool {
+		return detections[i].Start < detections[j].Start
+	})
+	return detections
+}
+
+// fromInternalResult converts a redaction result to the public type
+func fromInternalResult(r *redactor.RedactResult) *Result {
+	return &Result{
+		Original:   r.OriginalText,
+		Redacted:   r.RedactedText,
+		Detections: fromInternalDetections(r.Detections),
+		Truncated:  r.Truncated,
+		Skipped:    r.Skipped,
+		Scanned:    r.Scanned,
+	}
+}
@@ -0,0 +1,152 @@
+package pii_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bunseokbot/pii-redactor/pkg/pii"
+)
+
+func TestScanner_Detect(t *testing.T) {
+	scanner := pii.NewScanner()
+
+	input := "contact test@example.com or 010-1234-5678"
+	detections, err := scanner.Detect(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	found := make(map[string]pii.Detection)
+	for _, d := range detections {
+		found[d.Pattern] = d
+	}
+
+	email, ok := found["email"]
+	if !ok {
+		t.Fatalf("expected an email detection, got %+v", detections)
+	}
+	if email.Text != "test@example.com" || input[email.Start:email.End] != email.Text {
+		t.Errorf("email detection = %+v", email)
+	}
+	if _, ok := found["phone-kr"]; !ok {
+		t.Error("expected a phone-kr detection")
+	}
+}
+
+func TestScanner_Redact(t *testing.T) {
+	scanner := pii.NewScanner()
+	ctx := context.Background()
+
+	result, err := scanner.RedactWithPatterns(ctx, "mail test@example.com", []string{"email"})
+	if err != nil {
+		t.Fatalf("RedactWithPatterns() error = %v", err)
+	}
+	if strings.Contains(result.Redacted, "test@example.com") {
+		t.Errorf("Redacted = %q still contains the email", result.Redacted)
+	}
+	if len(result.Detections) != 1 || result.Detections[0].Redacted == "" {
+		t.Errorf("Detections = %+v, want one redacted detection", result.Detections)
+	}
+
+	if _, err := scanner.RedactWithPatterns(ctx, "text", nil); !errors.Is(err, pii.ErrNoPatterns) {
+		t.Errorf("expected ErrNoPatterns, got %v", err)
+	}
+}
+
+func TestScanner_CustomPattern(t *testing.T) {
+	scanner := pii.NewScanner()
+
+	err := scanner.AddPattern("employee-id", pii.Pattern{
+		DisplayName: "Employee ID",
+		Rules:       []pii.Rule{{Regex: `EMP-\d{6}`, Confidence: "high"}},
+		Masking:     pii.MaskingStrategy{Type: "full", Replacement: "[EMPLOYEE]"},
+		Severity:    "medium",
+	})
+	if err != nil {
+		t.Fatalf("AddPattern() error = %v", err)
+	}
+
+	result, err := scanner.RedactWithPatterns(context.Background(), "badge EMP-123456 scanned", []string{"employee-id"})
+	if err != nil {
+		t.Fatalf("RedactWithPatterns() error = %v", err)
+	}
+	if result.Redacted != "badge [EMPLOYEE] scanned" {
+		t.Errorf("Redacted = %q", result.Redacted)
+	}
+
+	if err := scanner.AddPattern("broken", pii.Pattern{Rules: []pii.Rule{{Regex: `(`}}}); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+
+	scanner.RemovePattern("employee-id")
+	detections, err := scanner.DetectWithPatterns(context.Background(), "EMP-123456", []string{"employee-id"})
+	if err != nil {
+		t.Fatalf("DetectWithPatterns() error = %v", err)
+	}
+	if len(detections) != 0 {
+		t.Errorf("expected no detections after RemovePattern, got %d", len(detections))
+	}
+}
+
+func TestApplyMasking(t *testing.T) {
+	got := pii.ApplyMasking("4111111111111111", pii.MaskingStrategy{Type: "partial", ShowLast: 4, MaskChar: "#"})
+	if got != "############1111" {
+		t.Errorf("ApplyMasking() = %q", got)
+	}
+}
+
+func TestResult_Clean(t *testing.T) {
+	scanner := pii.NewScanner()
+
+	clean, err := scanner.Redact(context.Background(), "healthcheck ok")
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	if !clean.Scanned || !clean.Clean() {
+		t.Errorf("result without PII: Scanned = %v, Clean() = %v, want both true", clean.Scanned, clean.Clean())
+	}
+
+	found, err := scanner.Redact(context.Background(), "mail test@example.com")
+	if err != nil {
+		t.Fatalf("Redact() error = %v", err)
+	}
+	if !found.Scanned || found.Clean() {
+		t.Errorf("result with PII: Scanned = %v, Clean() = %v, want true, false", found.Scanned, found.Clean())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	failed, err := scanner.Redact(ctx, "healthcheck ok")
+	if err == nil || failed.Clean() {
+		t.Errorf("cancelled scan: error = %v, Clean() = %v, want an error and not clean", err, failed.Clean())
+	}
+}
+
+func TestScanner_BlankInput(t *testing.T) {
+	scanner := pii.NewScanner()
+	ctx := context.Background()
+
+	for _, input := range []string{"", "   ", " \t\r\n "} {
+		detections, err := scanner.Detect(ctx, input)
+		if err != nil || len(detections) != 0 {
+			t.Errorf("Detect(%q) = %+v, %v, want no detections", input, detections, err)
+		}
+
+		detections, err = scanner.DetectWithPatterns(ctx, input, []string{"email"})
+		if err != nil || len(detections) != 0 {
+			t.Errorf("DetectWithPatterns(%q) = %+v, %v, want no detections", input, detections, err)
+		}
+
+		result, err := scanner.Redact(ctx, input)
+		if err != nil || !result.Clean() || result.Redacted != input {
+			t.Errorf("Redact(%q) = %+v, %v, want a clean result", input, result, err)
+		}
+
+		result, err = scanner.RedactWithPatterns(ctx, input, []string{"email"})
+		if err != nil || !result.Clean() || result.Redacted != input {
+			t.Errorf("RedactWithPatterns(%q) = %+v, %v, want a clean result", input, result, err)
+		}
+	}
+}